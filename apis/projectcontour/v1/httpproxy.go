@@ -53,9 +53,55 @@ type Condition struct {
 	// +optional
 	Prefix string `json:"prefix,omitempty"`
 
+	// Exact defines an exact match for the request path.
+	// Exact conditions are only permitted on routes, not on includes.
+	// +optional
+	Exact string `json:"exact,omitempty"`
+
+	// Regex defines a regular expression match for the request path.
+	// The regular expression must match the whole path.
+	// Regex conditions are only permitted on routes, not on includes.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
 	// Header specifies the header condition to match.
 	// +optional
 	Header *HeaderCondition `json:"header,omitempty"`
+
+	// QueryParameter specifies the query parameter condition to match.
+	// +optional
+	QueryParameter *QueryParameterCondition `json:"queryParameter,omitempty"`
+}
+
+// QueryParameterCondition specifies how to conditionally match against
+// HTTP query parameters. The Name field is required, but only one of
+// the remaining fields should be be provided.
+type QueryParameterCondition struct {
+	// Name is the name of the query parameter to match against.
+	// Name is required. Query parameter names are case sensitive.
+	Name string `json:"name"`
+
+	// Exact specifies a string that the query parameter value
+	// must be equal to.
+	// +optional
+	Exact string `json:"exact,omitempty"`
+
+	// Prefix specifies a string that the query parameter value
+	// must begin with.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Regex specifies a regular expression that the query parameter
+	// value must match in full.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// Present specifies that condition is true when the named query
+	// parameter is present, regardless of its value. Note that setting
+	// Present to false does not make the condition true if the named
+	// query parameter is absent.
+	// +optional
+	Present bool `json:"present,omitempty"`
 }
 
 // HeaderCondition specifies how to conditionally match against HTTP
@@ -91,6 +137,34 @@ type HeaderCondition struct {
 	// equal to. The condition is true if the header has any other value.
 	// +optional
 	NotExact string `json:"notexact,omitempty"`
+
+	// Prefix specifies a string that the header value must begin with.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// NotPrefix specifies a string that the header value must not
+	// begin with.
+	// +optional
+	NotPrefix string `json:"notprefix,omitempty"`
+
+	// Suffix specifies a string that the header value must end with.
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+
+	// NotSuffix specifies a string that the header value must not
+	// end with.
+	// +optional
+	NotSuffix string `json:"notsuffix,omitempty"`
+
+	// Regex specifies a regular expression that the header value
+	// must match in full.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// NotRegex specifies a regular expression that the header value
+	// must not match.
+	// +optional
+	NotRegex string `json:"notregex,omitempty"`
 }
 
 // VirtualHost appears at most once. If it is present, the object is considered
@@ -99,13 +173,88 @@ type VirtualHost struct {
 	// The fully qualified domain name of the root of the ingress tree
 	// all leaves of the DAG rooted at this object relate to the fqdn
 	Fqdn string `json:"fqdn"`
+	// Aliases are additional fully qualified domain names which share
+	// the routes, TLS settings and status of the fqdn.
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
 	// If present describes tls properties. The SNI names that will be matched on
-	// are described in fqdn, the tls.secretName secret must contain a
+	// are described in fqdn and aliases, the tls.secretName secret must contain a
 	// matching certificate
 	// +optional
 	TLS *TLS `json:"tls,omitempty"`
+	// The policy for rate limiting on the virtual host.
+	// +optional
+	RateLimitPolicy *RateLimitPolicy `json:"rateLimitPolicy,omitempty"`
+	// Authorization configures an external authorization service
+	// for this virtual host. Authorization can only be configured
+	// on virtual hosts that terminate TLS.
+	// +optional
+	Authorization *AuthorizationServer `json:"authorization,omitempty"`
+	// Specifies the cross-origin policy to apply to the VirtualHost.
+	// +optional
+	CORSPolicy *CORSPolicy `json:"corsPolicy,omitempty"`
+	// Tracing overrides the global tracing configuration
+	// for requests to this virtual host.
+	// +optional
+	Tracing *TracingPolicy `json:"tracing,omitempty"`
+	// Compression overrides the global response compression
+	// configuration for this virtual host.
+	// +optional
+	Compression *CompressionPolicy `json:"compression,omitempty"`
+}
+
+// CompressionPolicy defines the response compression parameters of a virtual host.
+type CompressionPolicy struct {
+	// Disabled stops responses from this virtual host being compressed.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// TracingPolicy defines the tracing parameters of a virtual host.
+// Tracing must also be enabled in the Contour configuration file.
+type TracingPolicy struct {
+	// Disabled turns off tracing for requests to this virtual host.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// RandomSampling is the percentage of requests to this virtual
+	// host which are randomly selected for tracing. When unset, the
+	// globally configured sampling rate is used.
+	// +optional
+	// +kubebuilder:validation:Maximum=100
+	RandomSampling uint32 `json:"randomSampling,omitempty"`
+}
+
+// CORSPolicy allows setting the CORS policy
+type CORSPolicy struct {
+	// Specifies whether the resource allows credentials.
+	// +optional
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+	// AllowOrigin specifies the origins that will be allowed to do CORS requests. "*" means
+	// allow any origin.
+	// +kubebuilder:validation:MinItems=1
+	AllowOrigin []string `json:"allowOrigin"`
+	// AllowMethods specifies the content for the *access-control-allow-methods* header.
+	// +kubebuilder:validation:MinItems=1
+	AllowMethods []CORSHeaderValue `json:"allowMethods"`
+	// AllowHeaders specifies the content for the *access-control-allow-headers* header.
+	// +optional
+	AllowHeaders []CORSHeaderValue `json:"allowHeaders,omitempty"`
+	// ExposeHeaders Specifies the content for the *access-control-expose-headers* header.
+	// +optional
+	ExposeHeaders []CORSHeaderValue `json:"exposeHeaders,omitempty"`
+	// MaxAge indicates for how long the results of a preflight request can be cached.
+	// MaxAge durations are expressed in the Go [Duration format](https://godoc.org/time#ParseDuration).
+	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h". Only positive
+	// values are allowed. If not specified, the browser default is used.
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
 }
 
+// CORSHeaderValue specifies the value of the string headers returned by a cross-domain request.
+// +kubebuilder:validation:Pattern="^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$"
+type CORSHeaderValue string
+
 // TLS describes tls properties. The SNI names that will be matched on
 // are described in fqdn, the tls.secretName secret must contain a
 // matching certificate unless tls.passthrough is set to true.
@@ -115,11 +264,38 @@ type TLS struct {
 	// Minimum TLS version this vhost should negotiate
 	// +optional
 	MinimumProtocolVersion string `json:"minimumProtocolVersion,omitempty"`
+	// Maximum TLS version this vhost should negotiate.
+	// Must not be lower than the minimum version, or than the
+	// minimum version in the Contour configuration file.
+	// +optional
+	MaximumProtocolVersion string `json:"maximumProtocolVersion,omitempty"`
+	// CipherSuites is the list of TLS 1.2 cipher suites this vhost
+	// offers to clients, in order of preference. If empty, Contour's
+	// default cipher suites are used. TLS 1.3 cipher suites are not
+	// configurable.
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
 	// If Passthrough is set to true, the SecretName will be ignored
 	// and the encrypted handshake will be passed through to the
 	// backing cluster.
 	// +optional
 	Passthrough bool `json:"passthrough,omitempty"`
+	// ClientValidation defines how to verify the client certificate
+	// when an external client establishes a TLS connection to Envoy.
+	//
+	// This setting:
+	//
+	// 1. Enables TLS client certificate validation.
+	// 2. Requires clients to present a TLS certificate (i.e. not optional validation).
+	// 3. Specifies how the client certificate will be validated.
+	// +optional
+	ClientValidation *DownstreamValidation `json:"clientValidation,omitempty"`
+	// EnableFallbackCertificate routes TLS connections from clients
+	// which do not send SNI to this virtual host, using the fallback
+	// certificate set in the Contour configuration file. Requests are
+	// matched to the virtual host by their Host header.
+	// +optional
+	EnableFallbackCertificate bool `json:"enableFallbackCertificate,omitempty"`
 }
 
 // Route contains the set of routes for a virtual host.
@@ -127,10 +303,11 @@ type Route struct {
 	// Conditions are a set of routing properties that is applied to an HTTPProxy in a namespace.
 	// +optional
 	Conditions []Condition `json:"conditions,omitempty"`
-	// Services are the services to proxy traffic.
-	// +kubebuilder:validation:MinItems=1
-	// +kubebuilder:validation:Required
-	Services []Service `json:"services"`
+	// Services are the services to proxy traffic. Services are
+	// required unless the route has a request redirect policy or
+	// a direct response policy.
+	// +optional
+	Services []Service `json:"services,omitempty"`
 	// Enables websocket support for the route.
 	// +optional
 	EnableWebsockets bool `json:"enableWebsockets,omitempty"`
@@ -161,6 +338,23 @@ type Route struct {
 	// The policy for managing response headers during proxying
 	// +optional
 	ResponseHeadersPolicy *HeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+	// The policy for rate limiting on the route.
+	// +optional
+	RateLimitPolicy *RateLimitPolicy `json:"rateLimitPolicy,omitempty"`
+	// The policy for authorizing requests to the route. Only
+	// applies if the virtual host has authorization configured.
+	// +optional
+	AuthPolicy *AuthorizationPolicy `json:"authPolicy,omitempty"`
+
+	// The policy for redirecting requests to the route. A route
+	// with a request redirect policy must not specify services.
+	// +optional
+	RequestRedirectPolicy *HTTPRequestRedirectPolicy `json:"requestRedirectPolicy,omitempty"`
+	// The policy for responding directly to requests to the route.
+	// A route with a direct response policy must not specify services
+	// or a request redirect policy.
+	// +optional
+	DirectResponsePolicy *HTTPDirectResponsePolicy `json:"directResponsePolicy,omitempty"`
 }
 
 func (r *Route) GetPrefixReplacements() []ReplacePrefix {
@@ -191,6 +385,27 @@ type TCPProxy struct {
 	// The health check policy for this tcp proxy
 	// +optional
 	HealthCheckPolicy *TCPHealthCheckPolicy `json:"healthCheckPolicy,omitempty"`
+	// The timeout policy for this tcp proxy
+	// +optional
+	TimeoutPolicy *TCPProxyTimeoutPolicy `json:"timeoutPolicy,omitempty"`
+}
+
+// TCPProxyTimeoutPolicy defines the timeouts applied to connections
+// through a tcp proxy.
+type TCPProxyTimeoutPolicy struct {
+	// TCPProxyTimeoutPolicy durations are expressed as per the format specified in the ParseDuration documentation: https://godoc.org/time#ParseDuration
+	// Example input values: "300ms", "5s", "1m". Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
+
+	// Timeout after which a connection with no bytes sent or received
+	// in either direction is closed. The string 'infinity' disables the
+	// idle timeout. If not supplied, the timeout is 9001 seconds.
+	// +optional
+	Idle string `json:"idle,omitempty"`
+
+	// Timeout for establishing a connection to the backend service.
+	// If not supplied, the timeout is 250 milliseconds.
+	// +optional
+	Connect string `json:"connect,omitempty"`
 }
 
 // TCPProxyInclude describes a target HTTPProxy document which contains the TCPProxy details.
@@ -229,6 +444,92 @@ type Service struct {
 	// The policy for managing response headers during proxying
 	// +optional
 	ResponseHeadersPolicy *HeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+	// The policy for passively ejecting endpoints of this service
+	// that fail requests.
+	// +optional
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+	// The circuit breaker thresholds for this service. Thresholds set
+	// here override those set by annotations on the Kubernetes Service.
+	// +optional
+	CircuitBreakers *CircuitBreakers `json:"circuitBreakers,omitempty"`
+	// ClientCertificate is the name of a Secret of type kubernetes.io/tls
+	// containing the client certificate and private key that Envoy
+	// presents when connecting to this service. The Secret may be in
+	// another namespace, written as namespace/name, if it has been
+	// delegated with a TLSCertificateDelegation. Only used when the
+	// protocol is tls or h2.
+	// +optional
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	// ProxyProtocol, if set, is the version of the PROXY protocol
+	// header Envoy sends when connecting to this service, so that it
+	// can learn the address of the downstream client. Values may be
+	// v1 or v2. Requires Envoy 1.15 or later, and upstream PROXY
+	// protocol to be enabled in the Contour configuration file.
+	// +kubebuilder:validation:Enum=v1;v2
+	// +optional
+	ProxyProtocol string `json:"proxyProtocol,omitempty"`
+}
+
+// CircuitBreakers defines the limits Envoy places on connections and
+// requests to the upstream service. Unset fields fall back to the
+// Service annotations, then to the Envoy defaults.
+type CircuitBreakers struct {
+	// MaxConnections is the maximum number of connections that
+	// Envoy will make to the upstream service.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxConnections int64 `json:"maxConnections,omitempty"`
+
+	// MaxPendingRequests is the maximum number of pending requests
+	// that Envoy will allow to the upstream service.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxPendingRequests int64 `json:"maxPendingRequests,omitempty"`
+
+	// MaxRequests is the maximum number of parallel requests that
+	// Envoy will make to the upstream service.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRequests int64 `json:"maxRequests,omitempty"`
+
+	// MaxRetries is the maximum number of parallel retries that
+	// Envoy will allow to the upstream service.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int64 `json:"maxRetries,omitempty"`
+}
+
+// OutlierDetection defines the policy for passive health checking of
+// the upstream service. Endpoints that fail requests are ejected from
+// the load balancing pool for a period of time. Unset fields take the
+// Envoy defaults.
+type OutlierDetection struct {
+	// ConsecutiveServerErrors is the number of consecutive 5xx
+	// responses after which an endpoint is ejected. Defaults to 5.
+	// +optional
+	ConsecutiveServerErrors uint32 `json:"consecutiveServerErrors,omitempty"`
+
+	// ConsecutiveGatewayErrors is the number of consecutive 502, 503
+	// or 504 responses after which an endpoint is ejected. When unset,
+	// endpoints are not ejected for gateway errors alone.
+	// +optional
+	ConsecutiveGatewayErrors uint32 `json:"consecutiveGatewayErrors,omitempty"`
+
+	// Interval is the time between ejection sweeps. Defaults to 10s.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// BaseEjectionTime is the base time an endpoint is ejected for.
+	// The actual time is multiplied by the number of times the endpoint
+	// has been ejected. Defaults to 30s.
+	// +optional
+	BaseEjectionTime string `json:"baseEjectionTime,omitempty"`
+
+	// MaxEjectionPercent is the maximum percentage of endpoints that
+	// may be ejected at once. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Maximum=100
+	MaxEjectionPercent uint32 `json:"maxEjectionPercent,omitempty"`
 }
 
 // HTTPHealthCheckPolicy defines health checks on the upstream service.
@@ -338,15 +639,143 @@ type PathRewritePolicy struct {
 	ReplacePrefix []ReplacePrefix `json:"replacePrefix,omitempty"`
 }
 
+// HTTPRequestRedirectPolicy defines how a request is redirected
+// rather than proxied to a Service. Only one of Path or Prefix
+// may be specified.
+type HTTPRequestRedirectPolicy struct {
+	// Scheme is the scheme to be used in the value of the `Location`
+	// header in the response. When empty, the scheme of the request
+	// is used.
+	// +optional
+	// +kubebuilder:validation:Enum=http;https
+	Scheme string `json:"scheme,omitempty"`
+
+	// Hostname is the precise hostname to be used in the value of
+	// the `Location` header in the response. When empty, the hostname
+	// of the request is used.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// Port is the port to be used in the value of the `Location`
+	// header in the response. When empty, the port of the request
+	// is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port,omitempty"`
+
+	// StatusCode is the HTTP status code to be used in the response.
+	// Defaults to 302.
+	// +optional
+	// +kubebuilder:validation:Enum=301;302;307;308
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Path replaces the whole path of the request in the value of
+	// the `Location` header in the response.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Prefix replaces the matched prefix of the request path in the
+	// value of the `Location` header in the response. Prefix may only
+	// be used with routes that have a prefix condition.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// HTTPDirectResponsePolicy defines a fixed response that is returned
+// to the client without proxying the request to a Service.
+type HTTPDirectResponsePolicy struct {
+	// StatusCode is the HTTP status code of the response.
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	StatusCode int `json:"statusCode"`
+
+	// Body is the content of the response body. When empty, the
+	// response has no body. The body must be no more than 4096 bytes.
+	// +optional
+	// +kubebuilder:validation:MaxLength=4096
+	Body string `json:"body,omitempty"`
+}
+
 // LoadBalancerPolicy defines the load balancing policy.
 type LoadBalancerPolicy struct {
 	// Strategy specifies the policy used to balance requests
 	// across the pool of backend pods. Valid policy names are
-	// `Random`, `RoundRobin`, `WeightedLeastRequest`, `Random`
-	// and `Cookie`. If an unknown strategy name is specified
-	// or no policy is supplied, the default `RoundRobin` policy
-	// is used.
+	// `Random`, `RoundRobin`, `WeightedLeastRequest`, `Random`,
+	// `Cookie` and `RequestHash`. If an unknown strategy name is
+	// specified or no policy is supplied, the default `RoundRobin`
+	// policy is used.
 	Strategy string `json:"strategy,omitempty"`
+
+	// RequestHashPolicies contains the list of hash policies used to
+	// compute the hash key of a request when the `RequestHash` strategy
+	// is chosen. Policies are evaluated in order and their results are
+	// combined, unless a terminal policy produces a hash key.
+	// +optional
+	RequestHashPolicies []RequestHashPolicy `json:"requestHashPolicies,omitempty"`
+}
+
+// RequestHashPolicy contains configuration for an individual hash policy
+// on a request attribute. Exactly one of HeaderHashOptions,
+// CookieHashOptions, QueryParameterHashOptions or HashSourceIP must be set.
+type RequestHashPolicy struct {
+	// Terminal specifies that, if the hash key of this policy can be
+	// computed, the remaining policies are skipped.
+	// +optional
+	Terminal bool `json:"terminal,omitempty"`
+
+	// HeaderHashOptions computes the hash key from the value of a
+	// request header.
+	// +optional
+	HeaderHashOptions *HeaderHashOptions `json:"headerHashOptions,omitempty"`
+
+	// CookieHashOptions computes the hash key from the value of a
+	// request cookie. If the cookie is absent and a TTL is set, Envoy
+	// generates the cookie.
+	// +optional
+	CookieHashOptions *CookieHashOptions `json:"cookieHashOptions,omitempty"`
+
+	// QueryParameterHashOptions computes the hash key from the value
+	// of a request query parameter.
+	// +optional
+	QueryParameterHashOptions *QueryParameterHashOptions `json:"queryParameterHashOptions,omitempty"`
+
+	// HashSourceIP computes the hash key from the source IP address
+	// of the downstream connection.
+	// +optional
+	HashSourceIP bool `json:"hashSourceIP,omitempty"`
+}
+
+// HeaderHashOptions contains options for hashing on a request header.
+type HeaderHashOptions struct {
+	// HeaderName is the name of the header to hash.
+	// +kubebuilder:validation:MinLength=1
+	HeaderName string `json:"headerName"`
+}
+
+// CookieHashOptions contains options for hashing on a request cookie.
+type CookieHashOptions struct {
+	// CookieName is the name of the cookie to hash.
+	// +kubebuilder:validation:MinLength=1
+	CookieName string `json:"cookieName"`
+
+	// TTL is the lifetime of the cookie generated by Envoy if the
+	// cookie is absent, and must not be negative. When unset, Envoy
+	// does not generate a cookie.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// Path is the path of the cookie generated by Envoy.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// QueryParameterHashOptions contains options for hashing on a request
+// query parameter.
+type QueryParameterHashOptions struct {
+	// ParameterName is the name of the query parameter to hash.
+	// +kubebuilder:validation:MinLength=1
+	ParameterName string `json:"parameterName"`
 }
 
 // HeadersPolicy defines how headers are managed during forwarding
@@ -371,6 +800,111 @@ type HeaderValue struct {
 	Value string `json:"value"`
 }
 
+// RateLimitPolicy defines rate limiting parameters.
+type RateLimitPolicy struct {
+	// Local defines local rate limiting parameters, i.e. parameters
+	// for rate limiting that occurs within each Envoy pod as requests
+	// are handled.
+	// +optional
+	Local *LocalRateLimitPolicy `json:"local,omitempty"`
+
+	// Global defines global rate limiting parameters, i.e. parameters
+	// defining descriptors that are sent to an external rate limit
+	// service (RLS) for a rate limit decision on each request.
+	// +optional
+	Global *GlobalRateLimitPolicy `json:"global,omitempty"`
+}
+
+// LocalRateLimitPolicy defines local rate limiting parameters.
+type LocalRateLimitPolicy struct {
+	// Requests defines how many requests per unit of time should
+	// be allowed before rate limiting occurs.
+	// +kubebuilder:validation:Minimum=1
+	Requests uint32 `json:"requests"`
+
+	// Unit defines the period of time within which requests
+	// over the limit will be rate limited. Valid values are
+	// "second", "minute" and "hour".
+	// +kubebuilder:validation:Enum=second;minute;hour
+	Unit string `json:"unit"`
+
+	// Burst defines the number of requests above the requests per
+	// unit that should be allowed within a short period of time.
+	// +optional
+	Burst uint32 `json:"burst,omitempty"`
+
+	// ResponseStatusCode is the HTTP status code to use for responses
+	// to rate-limited requests. Codes must be in the 400-599 range
+	// (inclusive). If not specified, the Envoy default of 429 (Too
+	// Many Requests) is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=400
+	// +kubebuilder:validation:Maximum=599
+	ResponseStatusCode uint32 `json:"responseStatusCode,omitempty"`
+}
+
+// GlobalRateLimitPolicy defines global rate limiting parameters.
+type GlobalRateLimitPolicy struct {
+	// Descriptors defines the list of descriptors that will
+	// be generated and sent to the rate limit service. Each
+	// descriptor contains 1+ key-value pair entries.
+	// +kubebuilder:validation:MinItems=1
+	Descriptors []RateLimitDescriptor `json:"descriptors"`
+}
+
+// RateLimitDescriptor defines a list of key-value pair generators.
+type RateLimitDescriptor struct {
+	// Entries is the list of key-value pair generators.
+	// +kubebuilder:validation:MinItems=1
+	Entries []RateLimitDescriptorEntry `json:"entries"`
+}
+
+// RateLimitDescriptorEntry is a key-value pair generator. Exactly
+// one field on this struct must be non-nil.
+type RateLimitDescriptorEntry struct {
+	// GenericKey defines a descriptor entry with a key of "generic_key"
+	// and a static value.
+	// +optional
+	GenericKey *GenericKeyDescriptor `json:"genericKey,omitempty"`
+
+	// RequestHeader defines a descriptor entry that's populated only if
+	// a given header is present on the request. The descriptor key is static,
+	// and the descriptor value is equal to the value of the header.
+	// +optional
+	RequestHeader *RequestHeaderDescriptor `json:"requestHeader,omitempty"`
+
+	// RemoteAddress defines a descriptor entry with a key of "remote_address"
+	// and a value equal to the client's IP address (from x-forwarded-for).
+	// +optional
+	RemoteAddress *RemoteAddressDescriptor `json:"remoteAddress,omitempty"`
+}
+
+// GenericKeyDescriptor defines a descriptor entry with a key of
+// "generic_key" and a static value.
+type GenericKeyDescriptor struct {
+	// Value defines the value of the descriptor entry.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// RequestHeaderDescriptor defines a descriptor entry that's populated only if
+// a given header is present on the request. The value of the descriptor entry
+// is equal to the value of the header (if present).
+type RequestHeaderDescriptor struct {
+	// HeaderName defines the name of the header to look for on the request.
+	// +kubebuilder:validation:MinLength=1
+	HeaderName string `json:"headerName"`
+
+	// DescriptorKey defines the key to use on the descriptor entry.
+	// +kubebuilder:validation:MinLength=1
+	DescriptorKey string `json:"descriptorKey"`
+}
+
+// RemoteAddressDescriptor defines a descriptor entry with a key of
+// "remote_address" and a value equal to the client's IP address
+// (from x-forwarded-for).
+type RemoteAddressDescriptor struct{}
+
 // UpstreamValidation defines how to verify the backend service's certificate
 type UpstreamValidation struct {
 	// Name of the Kubernetes secret be used to validate the certificate presented by the backend
@@ -379,6 +913,63 @@ type UpstreamValidation struct {
 	SubjectName string `json:"subjectName"`
 }
 
+// DownstreamValidation defines how to verify the client certificate.
+type DownstreamValidation struct {
+	// Name of a Kubernetes secret that contains a CA certificate bundle.
+	// The client certificate must validate against the certificates in the bundle.
+	// +kubebuilder:validation:MinLength=1
+	CACertificate string `json:"caSecret"`
+}
+
+// AuthorizationServer configures an external server to authorize
+// client requests. The server must implement the Envoy external
+// authorization gRPC protocol.
+type AuthorizationServer struct {
+	// ServiceRef references the Kubernetes Service that implements
+	// the authorization gRPC protocol.
+	ServiceRef ExtensionServiceReference `json:"serviceRef"`
+	// ResponseTimeout configures the maximum time to wait for a check
+	// response from the authorization server. Timeout durations are
+	// expressed in the Go [Duration format](https://godoc.org/time#ParseDuration).
+	// If not specified, the Envoy default of 200ms is used.
+	// +optional
+	ResponseTimeout string `json:"responseTimeout,omitempty"`
+	// If FailOpen is true, the client request is forwarded to the
+	// upstream service even if the authorization server fails to
+	// respond. This field should not be set in most cases.
+	// +optional
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// ExtensionServiceReference references a Kubernetes Service that
+// implements an Envoy extension protocol.
+type ExtensionServiceReference struct {
+	// Name of the Service.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace of the Service. Defaults to the namespace of the HTTPProxy,
+	// and must match it if set.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Port of the Service.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port"`
+}
+
+// AuthorizationPolicy modifies how client requests to a route are
+// authorized.
+type AuthorizationPolicy struct {
+	// When true, requests to this route are not sent to the
+	// authorization server.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Context is a set of key/value pairs that are sent to the
+	// authorization server in the check request.
+	// +optional
+	Context map[string]string `json:"context,omitempty"`
+}
+
 // Status reports the current state of the HTTPProxy.
 type Status struct {
 	// +optional
@@ -20,6 +20,7 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/projectcontour/contour/internal/envoy"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v2"
 )
 
 // registerBootstrap registers the bootstrap subcommand and flags
@@ -27,6 +28,24 @@ import (
 func registerBootstrap(app *kingpin.Application) (*kingpin.CmdClause, *bootstrapContext) {
 	var ctx bootstrapContext
 
+	// The bootstrap reads the tracing service name from the
+	// Contour configuration file, so that it is set in one place
+	// alongside the rest of the tracing configuration.
+	var configFile string
+	parseConfig := func(_ *kingpin.ParseContext) error {
+		f, err := os.Open(configFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		config := newServeContext()
+		if err := yaml.NewDecoder(f).Decode(config); err != nil {
+			return err
+		}
+		ctx.config.TracingServiceName = config.Tracing.ServiceName
+		return nil
+	}
+
 	bootstrap := app.Command("bootstrap", "Generate bootstrap configuration.")
 	bootstrap.Arg("path", "Configuration file ('-' for standard output).").Required().StringVar(&ctx.path)
 	bootstrap.Flag("admin-address", "Envoy admin interface address.").StringVar(&ctx.config.AdminAddress)
@@ -36,6 +55,10 @@ func registerBootstrap(app *kingpin.Application) (*kingpin.CmdClause, *bootstrap
 	bootstrap.Flag("envoy-cafile", "gRPC CA Filename for Envoy to load.").Envar("ENVOY_CAFILE").StringVar(&ctx.config.GrpcCABundle)
 	bootstrap.Flag("envoy-cert-file", "gRPC Client cert filename for Envoy to load.").Envar("ENVOY_CERT_FILE").StringVar(&ctx.config.GrpcClientCert)
 	bootstrap.Flag("envoy-key-file", "gRPC Client key filename for Envoy to load.").Envar("ENVOY_KEY_FILE").StringVar(&ctx.config.GrpcClientKey)
+	bootstrap.Flag("tracing-address", "Zipkin compatible trace collector address.").Envar("TRACING_ADDRESS").StringVar(&ctx.config.TracingAddress)
+	bootstrap.Flag("tracing-port", "Zipkin compatible trace collector port.").Envar("TRACING_PORT").IntVar(&ctx.config.TracingPort)
+	bootstrap.Flag("tracing-collector-endpoint", "Trace collector API endpoint spans are sent to.").Envar("TRACING_COLLECTOR_ENDPOINT").StringVar(&ctx.config.TracingCollectorEndpoint)
+	bootstrap.Flag("config-path", "Path to the Contour configuration file, used for the tracing service name.").Action(parseConfig).ExistingFileVar(&configFile)
 	bootstrap.Flag("namespace", "The namespace the Envoy container will run in.").Envar("CONTOUR_NAMESPACE").Default("projectcontour").StringVar(&ctx.config.Namespace)
 	return bootstrap, &ctx
 }
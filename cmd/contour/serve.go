@@ -22,6 +22,7 @@ import (
 	"syscall"
 	"time"
 
+	envoy_api_v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
@@ -112,6 +113,8 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	serve.Flag("envoy-service-http-port", "Kubernetes Service port for HTTP requests.").IntVar(&ctx.httpPort)
 	serve.Flag("envoy-service-https-port", "Kubernetes Service port for HTTPS requests.").IntVar(&ctx.httpsPort)
 	serve.Flag("use-proxy-protocol", "Use PROXY protocol for all listeners.").BoolVar(&ctx.useProxyProto)
+	serve.Flag("num-trusted-hops", "Number of proxy hops in front of Envoy trusted in the X-Forwarded-For header.").Uint32Var(&ctx.Network.XffNumTrustedHops)
+	serve.Flag("skip-xff-append", "Do not append the client address to the X-Forwarded-For header.").BoolVar(&ctx.Network.SkipXffAppend)
 
 	serve.Flag("accesslog-format", "Format for Envoy access logs.").StringVar(&ctx.AccessLogFormat)
 	serve.Flag("disable-leader-election", "Disable leader election mechanism.").BoolVar(&ctx.DisableLeaderElection)
@@ -156,6 +159,51 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		return err
 	}
 
+	rateLimitConfig, err := ctx.rateLimitConfig()
+	if err != nil {
+		return err
+	}
+
+	clientCert, err := ctx.clientCertificate()
+	if err != nil {
+		return err
+	}
+
+	fallbackCert, err := ctx.fallbackCertificate()
+	if err != nil {
+		return err
+	}
+
+	maxProtoVersion, err := ctx.maxProtoVersion()
+	if err != nil {
+		return err
+	}
+
+	cipherSuites, err := ctx.cipherSuites()
+	if err != nil {
+		return err
+	}
+
+	tcpListeners, err := ctx.tcpListeners()
+	if err != nil {
+		return err
+	}
+
+	tracingConfig, err := ctx.tracingConfig()
+	if err != nil {
+		return err
+	}
+
+	gzipConfig, err := ctx.gzipConfig()
+	if err != nil {
+		return err
+	}
+
+	var staticClusters []*envoy_api_v2.Cluster
+	if rateLimitConfig != nil {
+		staticClusters = append(staticClusters, rateLimitConfig.Cluster())
+	}
+
 	// step 3. build our mammoth Kubernetes event handler.
 	eventHandler := &contour.EventHandler{
 		CacheHandler: &contour.CacheHandler{
@@ -170,9 +218,18 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 				AccessLogType:          ctx.AccessLogFormat,
 				AccessLogFields:        ctx.AccessLogFields,
 				MinimumProtocolVersion: dag.MinProtoVersion(ctx.TLSConfig.MinimumProtocolVersion),
+				MaximumProtocolVersion: maxProtoVersion,
+				CipherSuites:           cipherSuites,
 				RequestTimeout:         ctx.RequestTimeout,
+				RateLimitConfig:        rateLimitConfig,
+				TracingConfig:          tracingConfig,
+				XffNumTrustedHops:      ctx.Network.XffNumTrustedHops,
+				SkipXffAppend:          ctx.Network.SkipXffAppend,
+				GzipConfig:             gzipConfig,
+				DisableGRPCWeb:         ctx.GRPCWeb.Disabled,
 			},
 			ListenerCache: contour.NewListenerCache(ctx.statsAddr, ctx.statsPort),
+			ClusterCache:  contour.NewClusterCache(staticClusters...),
 			FieldLogger:   log.WithField("context", "CacheHandler"),
 			Metrics:       metrics.NewMetrics(registry),
 		},
@@ -183,11 +240,19 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		},
 		Builder: dag.Builder{
 			Source: dag.KubernetesCache{
-				RootNamespaces: ctx.ingressRouteRootNamespaces(),
-				IngressClass:   ctx.ingressClass,
-				FieldLogger:    log.WithField("context", "KubernetesCache"),
+				RootNamespaces:      ctx.ingressRouteRootNamespaces(),
+				IngressClass:        ctx.ingressClass,
+				ClientCertificate:   clientCert,
+				FallbackCertificate: fallbackCert,
+				TCPListeners:        tcpListeners,
+				FieldLogger:         log.WithField("context", "KubernetesCache"),
 			},
-			DisablePermitInsecure: ctx.DisablePermitInsecure,
+			DisablePermitInsecure:       ctx.DisablePermitInsecure,
+			EnableGlobalRateLimit:       rateLimitConfig != nil,
+			EnableLocalRateLimit:        ctx.LocalRateLimit.Enabled,
+			EnableUpstreamProxyProtocol: ctx.UpstreamProxyProtocol.Enabled,
+			MinimumProtocolVersion:      ctx.TLSConfig.MinimumProtocolVersion,
+			DisableGzip:                 gzipConfig != nil && gzipConfig.Disabled,
 		},
 		FieldLogger: log.WithField("context", "contourEventHandler"),
 	}
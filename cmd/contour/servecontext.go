@@ -25,10 +25,14 @@ import (
 	"strings"
 	"time"
 
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type serveContext struct {
@@ -103,6 +107,35 @@ type serveContext struct {
 	// RequestTimeout sets the client request timeout globally for Contour.
 	RequestTimeout time.Duration `yaml:"request-timeout,omitempty"`
 
+	// RateLimitService configures the external rate limit service
+	// used for global rate limiting.
+	RateLimitService RateLimitServiceConfig `yaml:"rate-limit-service,omitempty"`
+
+	// LocalRateLimit configures local rate limiting by Envoy.
+	LocalRateLimit LocalRateLimitConfig `yaml:"local-rate-limit,omitempty"`
+
+	// UpstreamProxyProtocol configures sending PROXY protocol
+	// to upstream services.
+	UpstreamProxyProtocol UpstreamProxyProtocolConfig `yaml:"upstream-proxy-protocol,omitempty"`
+
+	// Network holds configuration of how Envoy determines
+	// the address of the downstream client.
+	Network NetworkConfig `yaml:"network,omitempty"`
+
+	// Tracing configures tracing of requests by Envoy.
+	Tracing TracingConfig `yaml:"tracing,omitempty"`
+
+	// Compression configures, or disables, compression
+	// of responses by Envoy's gzip filter.
+	Compression CompressionConfig `yaml:"compression,omitempty"`
+
+	// GRPCWeb configures Envoy's grpc-web filter.
+	GRPCWeb GRPCWebConfig `yaml:"grpc-web,omitempty"`
+
+	// TCPListeners configures additional Envoy listeners which
+	// forward plain TCP connections to a Kubernetes service.
+	TCPListeners []TCPListenerConfig `yaml:"tcp-listeners,omitempty"`
+
 	// Should Contour register to watch the new service-apis types?
 	// By default this value is false, meaning Contour will not do anything with any of the new
 	// types.
@@ -164,6 +197,14 @@ func newServeContext() *serveContext {
 			Namespace:     "projectcontour",
 			Name:          "leader-elect",
 		},
+		RateLimitService: RateLimitServiceConfig{
+			Domain: "contour",
+		},
+		Tracing: TracingConfig{
+			ClientSampling:  100,
+			RandomSampling:  100,
+			OverallSampling: 100,
+		},
 		UseExperimentalServiceAPITypes: false,
 	}
 }
@@ -171,6 +212,28 @@ func newServeContext() *serveContext {
 // TLSConfig holds configuration file TLS configuration details.
 type TLSConfig struct {
 	MinimumProtocolVersion string `yaml:"minimum-protocol-version"`
+
+	// MaximumProtocolVersion is the highest TLS version Envoy
+	// negotiates, unless overridden by an HTTPProxy.
+	MaximumProtocolVersion string `yaml:"maximum-protocol-version,omitempty"`
+
+	// CipherSuites are the TLS 1.2 cipher suites Envoy offers,
+	// unless overridden by an HTTPProxy.
+	CipherSuites []string `yaml:"cipher-suites,omitempty"`
+
+	// ClientCertificate names the Secret holding the client
+	// certificate and key Envoy presents to TLS upstreams.
+	ClientCertificate NamespacedName `yaml:"envoy-client-certificate,omitempty"`
+
+	// FallbackCertificate names the Secret holding the certificate
+	// and key Envoy serves to clients which do not send SNI.
+	FallbackCertificate NamespacedName `yaml:"fallback-certificate,omitempty"`
+}
+
+// NamespacedName names a Kubernetes object in the configuration file.
+type NamespacedName struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
 }
 
 // LeaderElectionConfig holds the config bits for leader election inside the
@@ -183,6 +246,151 @@ type LeaderElectionConfig struct {
 	Name          string        `yaml:"configmap-name,omitempty"`
 }
 
+// RateLimitServiceConfig holds the config bits for the external
+// rate limit service inside the configuration file.
+type RateLimitServiceConfig struct {
+	// Address is the DNS name or IP address of the rate limit service.
+	// If not set, global rate limiting is disabled.
+	Address string `yaml:"address,omitempty"`
+
+	// Port is the port of the rate limit service's gRPC endpoint.
+	Port int `yaml:"port,omitempty"`
+
+	// Domain is the rate limit domain passed to the rate limit service.
+	Domain string `yaml:"domain,omitempty"`
+
+	// Timeout is the timeout for requests to the rate limit service.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// FailOpen allows requests through when the rate limit service
+	// cannot be reached or returns an error.
+	FailOpen bool `yaml:"fail-open,omitempty"`
+}
+
+// NetworkConfig holds the config bits for X-Forwarded-For
+// handling inside the configuration file. Envoy's v2 HTTP
+// connection manager cannot be given internal address ranges,
+// so addresses in the RFC1918 ranges are always internal.
+type NetworkConfig struct {
+	// XffNumTrustedHops is the number of additional proxy hops, such
+	// as a cloud load balancer, in front of Envoy whose entries in
+	// the X-Forwarded-For header are trusted. Defaults to 0.
+	XffNumTrustedHops uint32 `yaml:"num-trusted-hops,omitempty"`
+
+	// SkipXffAppend stops Envoy appending the address of the
+	// downstream client to the X-Forwarded-For header.
+	SkipXffAppend bool `yaml:"skip-xff-append,omitempty"`
+}
+
+// LocalRateLimitConfig holds the config bits for local
+// rate limiting inside the configuration file.
+type LocalRateLimitConfig struct {
+	// Enabled permits HTTPProxies to use local rate limiting.
+	// Envoy's local rate limit filter requires Envoy 1.16 or
+	// later, so it is disabled by default.
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// UpstreamProxyProtocolConfig holds the config bits for sending
+// PROXY protocol to upstream services inside the configuration file.
+type UpstreamProxyProtocolConfig struct {
+	// Enabled permits HTTPProxy services to set proxyProtocol.
+	// Envoy's upstream PROXY protocol transport socket requires
+	// Envoy 1.15 or later, so it is disabled by default.
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// TracingConfig holds the config bits for request tracing
+// inside the configuration file. Spans are sent to the trace
+// collector set by the bootstrap --tracing-address flag.
+type TracingConfig struct {
+	// Enabled turns on tracing in Envoy's HTTP connection managers.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// ClientSampling is the percentage of requests carrying an
+	// x-client-trace-id header which are traced. Defaults to 100.
+	ClientSampling float64 `yaml:"client-sampling,omitempty"`
+
+	// RandomSampling is the percentage of requests which are
+	// randomly selected for tracing. Defaults to 100.
+	RandomSampling float64 `yaml:"random-sampling,omitempty"`
+
+	// OverallSampling is the percentage of requests which are traced
+	// after all other sampling checks are applied. Defaults to 100.
+	OverallSampling float64 `yaml:"overall-sampling,omitempty"`
+
+	// Verbose adds span annotations for stream events.
+	Verbose bool `yaml:"verbose,omitempty"`
+
+	// CustomTags are added to every span.
+	CustomTags []TracingCustomTagConfig `yaml:"custom-tags,omitempty"`
+
+	// ServiceName is the service name Envoy reports spans under.
+	// It is read by contour bootstrap --config-path, as Envoy
+	// takes it from the bootstrap configuration.
+	ServiceName string `yaml:"service-name,omitempty"`
+}
+
+// TracingCustomTagConfig holds the config bits for a custom span tag
+// inside the configuration file. Exactly one of Literal or
+// RequestHeader must be set.
+type TracingCustomTagConfig struct {
+	Name          string `yaml:"name"`
+	Literal       string `yaml:"literal,omitempty"`
+	RequestHeader string `yaml:"request-header,omitempty"`
+}
+
+// CompressionConfig holds the config bits for response
+// compression inside the configuration file.
+type CompressionConfig struct {
+	// Disabled removes the gzip filter from Envoy's listeners.
+	Disabled bool `yaml:"disabled,omitempty"`
+
+	// ContentTypes are the response content types which are
+	// compressed. If not set, Envoy's defaults are used.
+	ContentTypes []string `yaml:"content-types,omitempty"`
+
+	// MinContentLength is the minimum response size, in bytes, which
+	// is compressed. If not set, Envoy's default of 30 is used.
+	MinContentLength uint32 `yaml:"min-content-length,omitempty"`
+
+	// Level is the compression level, one of default, best or speed.
+	// If not set, defaults to default.
+	Level string `yaml:"level,omitempty"`
+}
+
+// GRPCWebConfig holds the config bits for the grpc-web
+// filter inside the configuration file.
+type GRPCWebConfig struct {
+	// Disabled removes the grpc-web filter from Envoy's listeners.
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+// TCPListenerConfig holds the config bits for an additional
+// TCP listener inside the configuration file.
+type TCPListenerConfig struct {
+	// Name is the name of the Envoy listener.
+	Name string `yaml:"name"`
+
+	// Address is the address Envoy listens on.
+	// If not set, defaults to 0.0.0.0.
+	Address string `yaml:"address,omitempty"`
+
+	// Port is the port Envoy listens on.
+	Port int `yaml:"port"`
+
+	// Service is the Kubernetes service connections are forwarded to.
+	Service TCPListenerServiceConfig `yaml:"service"`
+}
+
+// TCPListenerServiceConfig names the port of a Kubernetes service
+// inside the configuration file.
+type TCPListenerServiceConfig struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+	Port      int    `yaml:"port"`
+}
+
 // grpcOptions returns a slice of grpc.ServerOptions.
 // if ctx.PermitInsecureGRPC is false, the option set will
 // include TLS configuration.
@@ -286,3 +494,193 @@ func (ctx *serveContext) ingressRouteRootNamespaces() []string {
 	}
 	return ns
 }
+
+// rateLimitConfig returns the configuration of the external rate limit
+// service, or nil if global rate limiting is not enabled.
+func (ctx *serveContext) rateLimitConfig() (*contour.RateLimitConfig, error) {
+	rls := ctx.RateLimitService
+	if rls.Address == "" {
+		return nil, nil
+	}
+	if rls.Port < 1 || rls.Port > 65535 {
+		return nil, fmt.Errorf("invalid rate limit service port %d", rls.Port)
+	}
+	return &contour.RateLimitConfig{
+		Address:  rls.Address,
+		Port:     rls.Port,
+		Domain:   rls.Domain,
+		Timeout:  rls.Timeout,
+		FailOpen: rls.FailOpen,
+	}, nil
+}
+
+// clientCertificate returns the name of the Secret holding the client
+// certificate Envoy presents to TLS upstreams, or nil if none is configured.
+func (ctx *serveContext) clientCertificate() (*types.NamespacedName, error) {
+	return secretName("tls.envoy-client-certificate", ctx.TLSConfig.ClientCertificate)
+}
+
+// fallbackCertificate returns the name of the secret Envoy serves
+// to clients without SNI, or nil if none is configured.
+func (ctx *serveContext) fallbackCertificate() (*types.NamespacedName, error) {
+	return secretName("tls.fallback-certificate", ctx.TLSConfig.FallbackCertificate)
+}
+
+// maxProtoVersion returns the configured maximum TLS protocol
+// version, or TLS_AUTO if none is configured.
+func (ctx *serveContext) maxProtoVersion() (envoy_api_v2_auth.TlsParameters_TlsProtocol, error) {
+	version, err := dag.MaxProtoVersion(ctx.TLSConfig.MaximumProtocolVersion)
+	if err != nil {
+		return version, fmt.Errorf("tls.maximum-protocol-version: %v", err)
+	}
+	if version != envoy_api_v2_auth.TlsParameters_TLS_AUTO && version < dag.MinProtoVersion(ctx.TLSConfig.MinimumProtocolVersion) {
+		return version, fmt.Errorf("tls.maximum-protocol-version %q is lower than tls.minimum-protocol-version", ctx.TLSConfig.MaximumProtocolVersion)
+	}
+	return version, nil
+}
+
+// cipherSuites returns the configured TLS 1.2 cipher suites,
+// or nil if Contour's defaults should be used.
+func (ctx *serveContext) cipherSuites() ([]string, error) {
+	if err := dag.ValidateCipherSuites(ctx.TLSConfig.CipherSuites); err != nil {
+		return nil, fmt.Errorf("tls.cipher-suites: %v", err)
+	}
+	return ctx.TLSConfig.CipherSuites, nil
+}
+
+// secretName returns nn as a *types.NamespacedName, or nil if it is
+// empty. key is the configuration file key used in errors.
+func secretName(key string, nn NamespacedName) (*types.NamespacedName, error) {
+	if nn.Name == "" && nn.Namespace == "" {
+		return nil, nil
+	}
+	if nn.Name == "" || nn.Namespace == "" {
+		return nil, fmt.Errorf("%s must specify both name and namespace", key)
+	}
+	return &types.NamespacedName{
+		Name:      nn.Name,
+		Namespace: nn.Namespace,
+	}, nil
+}
+
+// tcpListeners returns the validated configuration of the
+// additional TCP listeners.
+func (ctx *serveContext) tcpListeners() ([]dag.TCPListenerConfig, error) {
+	names := map[string]bool{
+		contour.ENVOY_HTTP_LISTENER:  true,
+		contour.ENVOY_HTTPS_LISTENER: true,
+		"stats-health":               true,
+	}
+	ports := map[int]bool{
+		ctx.httpPort:  true,
+		ctx.httpsPort: true,
+		ctx.statsPort: true,
+	}
+
+	var listeners []dag.TCPListenerConfig
+	for _, l := range ctx.TCPListeners {
+		if l.Name == "" {
+			return nil, errors.New("tcp-listeners: name must be specified")
+		}
+		if names[l.Name] {
+			return nil, fmt.Errorf("tcp-listeners: duplicate listener name %q", l.Name)
+		}
+		names[l.Name] = true
+
+		if l.Port < 1 || l.Port > 65535 {
+			return nil, fmt.Errorf("tcp-listeners: %s: invalid port %d", l.Name, l.Port)
+		}
+		if ports[l.Port] {
+			return nil, fmt.Errorf("tcp-listeners: %s: port %d is already in use", l.Name, l.Port)
+		}
+		ports[l.Port] = true
+
+		if l.Service.Name == "" || l.Service.Namespace == "" {
+			return nil, fmt.Errorf("tcp-listeners: %s: service must specify both name and namespace", l.Name)
+		}
+		if l.Service.Port < 1 || l.Service.Port > 65535 {
+			return nil, fmt.Errorf("tcp-listeners: %s: invalid service port %d", l.Name, l.Service.Port)
+		}
+
+		address := l.Address
+		if address == "" {
+			address = contour.DEFAULT_HTTP_LISTENER_ADDRESS
+		}
+		listeners = append(listeners, dag.TCPListenerConfig{
+			Name:    l.Name,
+			Address: address,
+			Port:    l.Port,
+			Service: types.NamespacedName{
+				Name:      l.Service.Name,
+				Namespace: l.Service.Namespace,
+			},
+			ServicePort: l.Service.Port,
+		})
+	}
+	return listeners, nil
+}
+
+// gzipConfig returns the gzip filter configuration, or nil
+// if Envoy's default settings should be used.
+func (ctx *serveContext) gzipConfig() (*envoy.GzipConfig, error) {
+	cc := ctx.Compression
+	switch cc.Level {
+	case "", "default", "best", "speed":
+	default:
+		return nil, fmt.Errorf("compression: level %q must be one of default, best or speed", cc.Level)
+	}
+
+	if !cc.Disabled && len(cc.ContentTypes) == 0 && cc.MinContentLength == 0 && cc.Level == "" {
+		return nil, nil
+	}
+
+	return &envoy.GzipConfig{
+		Disabled:         cc.Disabled,
+		ContentTypes:     cc.ContentTypes,
+		MinContentLength: cc.MinContentLength,
+		CompressionLevel: cc.Level,
+	}, nil
+}
+
+// tracingConfig returns the validated tracing configuration,
+// or nil if tracing is not enabled.
+func (ctx *serveContext) tracingConfig() (*envoy.TracingConfig, error) {
+	tc := ctx.Tracing
+	if !tc.Enabled {
+		return nil, nil
+	}
+
+	for _, sampling := range []struct {
+		name  string
+		value float64
+	}{
+		{"client-sampling", tc.ClientSampling},
+		{"random-sampling", tc.RandomSampling},
+		{"overall-sampling", tc.OverallSampling},
+	} {
+		if sampling.value < 0 || sampling.value > 100 {
+			return nil, fmt.Errorf("tracing: %s %v must be between 0 and 100", sampling.name, sampling.value)
+		}
+	}
+
+	config := &envoy.TracingConfig{
+		ClientSampling:  tc.ClientSampling,
+		RandomSampling:  tc.RandomSampling,
+		OverallSampling: tc.OverallSampling,
+		Verbose:         tc.Verbose,
+	}
+	for _, ct := range tc.CustomTags {
+		if ct.Name == "" {
+			return nil, errors.New("tracing: custom tag name must be specified")
+		}
+		if (ct.Literal == "") == (ct.RequestHeader == "") {
+			return nil, fmt.Errorf("tracing: custom tag %s must specify exactly one of literal or request-header", ct.Name)
+		}
+		config.CustomTags = append(config.CustomTags, &envoy.TracingCustomTag{
+			Name:          ct.Name,
+			Literal:       ct.Literal,
+			RequestHeader: ct.RequestHeader,
+		})
+	}
+	return config, nil
+}
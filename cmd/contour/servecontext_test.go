@@ -25,10 +25,14 @@ import (
 	"testing"
 	"time"
 
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	"github.com/google/go-cmp/cmp"
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
 	"google.golang.org/grpc"
 	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestServeContextIngressRouteRootNamespaces(t *testing.T) {
@@ -114,6 +118,361 @@ func TestServeContextTLSParams(t *testing.T) {
 	}
 }
 
+func TestServeContextClientCertificate(t *testing.T) {
+	tests := map[string]struct {
+		cc          NamespacedName
+		want        *types.NamespacedName
+		expecterror bool
+	}{
+		"not configured": {
+			want: nil,
+		},
+		"name and namespace": {
+			cc: NamespacedName{Name: "envoy-client", Namespace: "projectcontour"},
+			want: &types.NamespacedName{
+				Name:      "envoy-client",
+				Namespace: "projectcontour",
+			},
+		},
+		"name only": {
+			cc:          NamespacedName{Name: "envoy-client"},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.TLSConfig.ClientCertificate = tc.cc
+			got, err := ctx.clientCertificate()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("client certificate: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServeContextFallbackCertificate(t *testing.T) {
+	tests := map[string]struct {
+		fc          NamespacedName
+		want        *types.NamespacedName
+		expecterror bool
+	}{
+		"not configured": {
+			want: nil,
+		},
+		"name and namespace": {
+			fc: NamespacedName{Name: "fallback", Namespace: "projectcontour"},
+			want: &types.NamespacedName{
+				Name:      "fallback",
+				Namespace: "projectcontour",
+			},
+		},
+		"namespace only": {
+			fc:          NamespacedName{Namespace: "projectcontour"},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.TLSConfig.FallbackCertificate = tc.fc
+			got, err := ctx.fallbackCertificate()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("fallback certificate: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServeContextMaxProtoVersion(t *testing.T) {
+	tests := map[string]struct {
+		min, max    string
+		want        envoy_api_v2_auth.TlsParameters_TlsProtocol
+		expecterror bool
+	}{
+		"not configured": {
+			want: envoy_api_v2_auth.TlsParameters_TLS_AUTO,
+		},
+		"1.2": {
+			max:  "1.2",
+			want: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+		},
+		"invalid version": {
+			max:         "1.4",
+			expecterror: true,
+		},
+		"lower than minimum": {
+			min:         "1.3",
+			max:         "1.2",
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.TLSConfig.MinimumProtocolVersion = tc.min
+			ctx.TLSConfig.MaximumProtocolVersion = tc.max
+			got, err := ctx.maxProtoVersion()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("maximum protocol version: %s", err)
+			}
+			if !tc.expecterror {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServeContextCipherSuites(t *testing.T) {
+	tests := map[string]struct {
+		ciphers     []string
+		want        []string
+		expecterror bool
+	}{
+		"not configured": {
+			want: nil,
+		},
+		"valid cipher suites": {
+			ciphers: []string{"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]", "ECDHE-RSA-AES256-GCM-SHA384"},
+			want:    []string{"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]", "ECDHE-RSA-AES256-GCM-SHA384"},
+		},
+		"unsupported cipher suite": {
+			ciphers:     []string{"RC4-MD5"},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.TLSConfig.CipherSuites = tc.ciphers
+			got, err := ctx.cipherSuites()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("cipher suites: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServeContextTCPListeners(t *testing.T) {
+	postgres := TCPListenerServiceConfig{Name: "postgres", Namespace: "default", Port: 5432}
+
+	tests := map[string]struct {
+		listeners   []TCPListenerConfig
+		want        []dag.TCPListenerConfig
+		expecterror bool
+	}{
+		"not configured": {
+			want: nil,
+		},
+		"default address": {
+			listeners: []TCPListenerConfig{{Name: "postgres", Port: 5432, Service: postgres}},
+			want: []dag.TCPListenerConfig{{
+				Name:        "postgres",
+				Address:     "0.0.0.0",
+				Port:        5432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "default"},
+				ServicePort: 5432,
+			}},
+		},
+		"explicit address": {
+			listeners: []TCPListenerConfig{{Name: "postgres", Address: "127.0.0.1", Port: 15432, Service: postgres}},
+			want: []dag.TCPListenerConfig{{
+				Name:        "postgres",
+				Address:     "127.0.0.1",
+				Port:        15432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "default"},
+				ServicePort: 5432,
+			}},
+		},
+		"missing name": {
+			listeners:   []TCPListenerConfig{{Port: 5432, Service: postgres}},
+			expecterror: true,
+		},
+		"reserved name": {
+			listeners:   []TCPListenerConfig{{Name: "ingress_https", Port: 5432, Service: postgres}},
+			expecterror: true,
+		},
+		"duplicate name": {
+			listeners: []TCPListenerConfig{
+				{Name: "postgres", Port: 5432, Service: postgres},
+				{Name: "postgres", Port: 5433, Service: postgres},
+			},
+			expecterror: true,
+		},
+		"invalid port": {
+			listeners:   []TCPListenerConfig{{Name: "postgres", Port: 70000, Service: postgres}},
+			expecterror: true,
+		},
+		"port used by the http listener": {
+			listeners:   []TCPListenerConfig{{Name: "postgres", Port: 8080, Service: postgres}},
+			expecterror: true,
+		},
+		"service without namespace": {
+			listeners: []TCPListenerConfig{{
+				Name:    "postgres",
+				Port:    5432,
+				Service: TCPListenerServiceConfig{Name: "postgres", Port: 5432},
+			}},
+			expecterror: true,
+		},
+		"service without port": {
+			listeners: []TCPListenerConfig{{
+				Name:    "postgres",
+				Port:    5432,
+				Service: TCPListenerServiceConfig{Name: "postgres", Namespace: "default"},
+			}},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.TCPListeners = tc.listeners
+			got, err := ctx.tcpListeners()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("tcp listeners: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServeContextTracingConfig(t *testing.T) {
+	tests := map[string]struct {
+		tracing     func(*TracingConfig)
+		want        *envoy.TracingConfig
+		expecterror bool
+	}{
+		"not enabled": {
+			tracing: func(*TracingConfig) {},
+			want:    nil,
+		},
+		"defaults": {
+			tracing: func(tc *TracingConfig) {
+				tc.Enabled = true
+			},
+			want: &envoy.TracingConfig{
+				ClientSampling:  100,
+				RandomSampling:  100,
+				OverallSampling: 100,
+			},
+		},
+		"custom tags": {
+			tracing: func(tc *TracingConfig) {
+				tc.Enabled = true
+				tc.RandomSampling = 10
+				tc.Verbose = true
+				tc.CustomTags = []TracingCustomTagConfig{
+					{Name: "cluster", Literal: "production"},
+					{Name: "user-agent", RequestHeader: "User-Agent"},
+				}
+			},
+			want: &envoy.TracingConfig{
+				ClientSampling:  100,
+				RandomSampling:  10,
+				OverallSampling: 100,
+				Verbose:         true,
+				CustomTags: []*envoy.TracingCustomTag{
+					{Name: "cluster", Literal: "production"},
+					{Name: "user-agent", RequestHeader: "User-Agent"},
+				},
+			},
+		},
+		"sampling out of range": {
+			tracing: func(tc *TracingConfig) {
+				tc.Enabled = true
+				tc.OverallSampling = 101
+			},
+			expecterror: true,
+		},
+		"custom tag without name": {
+			tracing: func(tc *TracingConfig) {
+				tc.Enabled = true
+				tc.CustomTags = []TracingCustomTagConfig{{Literal: "production"}}
+			},
+			expecterror: true,
+		},
+		"custom tag with literal and request header": {
+			tracing: func(tc *TracingConfig) {
+				tc.Enabled = true
+				tc.CustomTags = []TracingCustomTagConfig{{Name: "cluster", Literal: "production", RequestHeader: "X-Cluster"}}
+			},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			tc.tracing(&ctx.Tracing)
+			got, err := ctx.tracingConfig()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("tracing config: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServeContextGzipConfig(t *testing.T) {
+	tests := map[string]struct {
+		compression CompressionConfig
+		want        *envoy.GzipConfig
+		expecterror bool
+	}{
+		"defaults": {
+			compression: CompressionConfig{},
+			want:        nil,
+		},
+		"disabled": {
+			compression: CompressionConfig{
+				Disabled: true,
+			},
+			want: &envoy.GzipConfig{
+				Disabled: true,
+			},
+		},
+		"tuned": {
+			compression: CompressionConfig{
+				ContentTypes:     []string{"text/html"},
+				MinContentLength: 1024,
+				Level:            "speed",
+			},
+			want: &envoy.GzipConfig{
+				ContentTypes:     []string{"text/html"},
+				MinContentLength: 1024,
+				CompressionLevel: "speed",
+			},
+		},
+		"invalid level": {
+			compression: CompressionConfig{
+				Level: "fastest",
+			},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.Compression = tc.compression
+			got, err := ctx.gzipConfig()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("gzip config: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestConfigFileDefaultOverrideImport(t *testing.T) {
 	tests := map[string]struct {
 		yamlIn string
@@ -153,6 +512,52 @@ tls:
 				return ctx
 			},
 		},
+		"tls envoy client certificate": {
+			yamlIn: `
+tls:
+  envoy-client-certificate:
+    name: envoy-client
+    namespace: projectcontour
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.TLSConfig.ClientCertificate = NamespacedName{
+					Name:      "envoy-client",
+					Namespace: "projectcontour",
+				}
+				return ctx
+			},
+		},
+		"tls fallback certificate": {
+			yamlIn: `
+tls:
+  fallback-certificate:
+    name: fallback
+    namespace: projectcontour
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.TLSConfig.FallbackCertificate = NamespacedName{
+					Name:      "fallback",
+					Namespace: "projectcontour",
+				}
+				return ctx
+			},
+		},
+		"tls maximum protocol version and cipher suites": {
+			yamlIn: `
+tls:
+  maximum-protocol-version: "1.2"
+  cipher-suites:
+  - ECDHE-RSA-AES256-GCM-SHA384
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.TLSConfig.MaximumProtocolVersion = "1.2"
+				ctx.TLSConfig.CipherSuites = []string{"ECDHE-RSA-AES256-GCM-SHA384"}
+				return ctx
+			},
+		},
 		"leader election namespace and configmap only": {
 			yamlIn: `
 leaderelection:
@@ -185,6 +590,99 @@ leaderelection:
 				return ctx
 			},
 		},
+		"rate limit service": {
+			yamlIn: `
+rate-limit-service:
+  address: ratelimit.projectcontour
+  port: 8081
+  timeout: 100ms
+  fail-open: true
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.RateLimitService.Address = "ratelimit.projectcontour"
+				ctx.RateLimitService.Port = 8081
+				ctx.RateLimitService.Timeout = 100 * time.Millisecond
+				ctx.RateLimitService.FailOpen = true
+				return ctx
+			},
+		},
+		"network": {
+			yamlIn: `
+network:
+  num-trusted-hops: 1
+  skip-xff-append: true
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.Network.XffNumTrustedHops = 1
+				ctx.Network.SkipXffAppend = true
+				return ctx
+			},
+		},
+		"compression and grpc-web": {
+			yamlIn: `
+compression:
+  content-types:
+  - application/json
+  min-content-length: 512
+  level: best
+grpc-web:
+  disabled: true
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.Compression.ContentTypes = []string{"application/json"}
+				ctx.Compression.MinContentLength = 512
+				ctx.Compression.Level = "best"
+				ctx.GRPCWeb.Disabled = true
+				return ctx
+			},
+		},
+		"tracing": {
+			yamlIn: `
+tracing:
+  enabled: true
+  random-sampling: 25
+  custom-tags:
+  - name: cluster
+    literal: production
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.Tracing.Enabled = true
+				ctx.Tracing.RandomSampling = 25
+				ctx.Tracing.CustomTags = []TracingCustomTagConfig{{
+					Name:    "cluster",
+					Literal: "production",
+				}}
+				return ctx
+			},
+		},
+		"tcp listeners": {
+			yamlIn: `
+tcp-listeners:
+- name: postgres
+  port: 5432
+  service:
+    name: postgres
+    namespace: default
+    port: 5432
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.TCPListeners = []TCPListenerConfig{{
+					Name: "postgres",
+					Port: 5432,
+					Service: TCPListenerServiceConfig{
+						Name:      "postgres",
+						Namespace: "default",
+						Port:      5432,
+					},
+				}}
+				return ctx
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -26,11 +26,24 @@ import (
 
 // ClusterCache manages the contents of the gRPC CDS cache.
 type ClusterCache struct {
-	mu     sync.Mutex
-	values map[string]*envoy_api_v2.Cluster
+	mu           sync.Mutex
+	values       map[string]*envoy_api_v2.Cluster
+	staticValues map[string]*envoy_api_v2.Cluster
 	Cond
 }
 
+// NewClusterCache returns an instance of a ClusterCache which
+// always includes the supplied static clusters.
+func NewClusterCache(static ...*envoy_api_v2.Cluster) ClusterCache {
+	staticValues := make(map[string]*envoy_api_v2.Cluster, len(static))
+	for _, c := range static {
+		staticValues[c.Name] = c
+	}
+	return ClusterCache{
+		staticValues: staticValues,
+	}
+}
+
 // Update replaces the contents of the cache with the supplied map.
 func (c *ClusterCache) Update(v map[string]*envoy_api_v2.Cluster) {
 	c.mu.Lock()
@@ -48,6 +61,9 @@ func (c *ClusterCache) Contents() []proto.Message {
 	for _, v := range c.values {
 		values = append(values, v)
 	}
+	for _, v := range c.staticValues {
+		values = append(values, v)
+	}
 	sort.Stable(clusterByName(values))
 	return values
 }
@@ -62,9 +78,14 @@ func (c *ClusterCache) Query(names []string) []proto.Message {
 		// discovery type; DNS, EDS, etc. We cannot determine the
 		// correct value for this property from the cluster's name
 		// provided by the query so we must not return a blank cluster.
-		if v, ok := c.values[n]; ok {
-			values = append(values, v)
+		v, ok := c.values[n]
+		if !ok {
+			v, ok = c.staticValues[n]
+			if !ok {
+				continue
+			}
 		}
+		values = append(values, v)
 	}
 	sort.Stable(clusterByName(values))
 	return values
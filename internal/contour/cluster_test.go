@@ -270,6 +270,79 @@ func TestClusterVisit(t *testing.T) {
 				},
 			),
 		},
+		"httpproxy with authorization service": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName: "secret",
+							},
+							Authorization: &projcontour.AuthorizationServer{
+								ServiceRef: projcontour.ExtensionServiceReference{
+									Name: "auth",
+									Port: 9000,
+								},
+							},
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "kuard",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				service("default", "kuard",
+					v1.ServicePort{
+						Protocol: "TCP",
+						Name:     "http",
+						Port:     80,
+					},
+				),
+				service("default", "auth",
+					v1.ServicePort{
+						Protocol: "TCP",
+						Name:     "grpc",
+						Port:     9000,
+					},
+				),
+			},
+			want: clustermap(
+				&v2.Cluster{
+					Name:                 "default/kuard/80/da39a3ee5e",
+					AltStatName:          "default_kuard_80",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/kuard/http",
+					},
+				},
+				&v2.Cluster{
+					Name:                 "default/auth/9000/da39a3ee5e",
+					AltStatName:          "default_auth_9000",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/auth/grpc",
+					},
+					Http2ProtocolOptions: &envoy_api_v2_core.Http2ProtocolOptions{},
+				},
+			),
+		},
 		"long namespace and service name": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -553,6 +626,105 @@ func TestClusterVisit(t *testing.T) {
 				},
 			),
 		},
+		"httpproxy with RequestHash lb algorithm": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+								Strategy: "RequestHash",
+								RequestHashPolicies: []projcontour.RequestHashPolicy{{
+									HeaderHashOptions: &projcontour.HeaderHashOptions{
+										HeaderName: "X-Tenant-Id",
+									},
+								}},
+							},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				service("default", "backend", v1.ServicePort{
+					Name:       "http",
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: intstr.FromInt(6502),
+				}),
+			},
+			want: clustermap(
+				&v2.Cluster{
+					Name:                 "default/backend/80/1a2ffc1fef",
+					AltStatName:          "default_backend_80",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/backend/http",
+					},
+					LbPolicy: v2.Cluster_RING_HASH,
+				},
+			),
+		},
+		"httpproxy with outlier detection": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+								OutlierDetection: &projcontour.OutlierDetection{
+									ConsecutiveServerErrors: 5,
+									Interval:                "10s",
+								},
+							}},
+						}},
+					},
+				},
+				service("default", "backend", v1.ServicePort{
+					Name:       "http",
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: intstr.FromInt(6502),
+				}),
+			},
+			want: clustermap(
+				&v2.Cluster{
+					Name:                 "default/backend/80/06ad03abaf",
+					AltStatName:          "default_backend_80",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/backend/http",
+					},
+					OutlierDetection: &envoy_api_v2_cluster.OutlierDetection{
+						Consecutive_5Xx: protobuf.UInt32(5),
+						Interval:        protobuf.Duration(10 * time.Second),
+					},
+				},
+			),
+		},
 		"ingressroute with Random lb algorithm": {
 			objs: []interface{}{
 				&ingressroutev1.IngressRoute{
@@ -742,6 +914,63 @@ func TestClusterVisit(t *testing.T) {
 				},
 			),
 		},
+		"httpproxy with circuit breakers overriding annotations": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "kuard",
+								Port: 80,
+								CircuitBreakers: &projcontour.CircuitBreakers{
+									MaxConnections: 100,
+								},
+							}},
+						}},
+					},
+				},
+				serviceWithAnnotations(
+					"default",
+					"kuard",
+					map[string]string{
+						"projectcontour.io/max-connections": "9000",
+						"projectcontour.io/max-retries":     "7",
+					},
+					v1.ServicePort{
+						Protocol: "TCP",
+						Name:     "http",
+						Port:     80,
+					},
+				),
+			},
+			want: clustermap(
+				&v2.Cluster{
+					Name:                 "default/kuard/80/833e370c7f",
+					AltStatName:          "default_kuard_80",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/kuard/http",
+					},
+					CircuitBreakers: &envoy_api_v2_cluster.CircuitBreakers{
+						Thresholds: []*envoy_api_v2_cluster.CircuitBreakers_Thresholds{{
+							MaxConnections: protobuf.UInt32(100),
+							MaxRetries:     protobuf.UInt32(7),
+						}},
+					},
+				},
+			),
+		},
 		"projectcontour.io/num-retries annotation": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -27,6 +27,7 @@ import (
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
 )
 
 // EventHandler implements cache.ResourceEventHandler, filters k8s events towards
@@ -180,6 +181,8 @@ func (e *EventHandler) onUpdate(op interface{}) bool {
 		if cmp.Equal(op.oldObj, op.newObj,
 			cmpopts.IgnoreFields(ingressroutev1.IngressRoute{}, "Status"),
 			cmpopts.IgnoreFields(projcontour.HTTPProxy{}, "Status"),
+			cmpopts.IgnoreFields(serviceapis.Gateway{}, "Status"),
+			cmpopts.IgnoreFields(serviceapis.HTTPRoute{}, "Status"),
 			cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion")) {
 			e.WithField("op", "update").Debugf("%T skipping update, only status has changed", op.newObj)
 			return false
@@ -251,6 +254,26 @@ func (e *EventHandler) setStatus(statuses map[dag.Meta]dag.Status) {
 					WithField("namespace", obj.Namespace).
 					Error("failed to set status")
 			}
+		case *serviceapis.Gateway:
+			err := e.StatusClient.SetStatus(st.Status, st.Description, obj)
+			if err != nil {
+				e.WithError(err).
+					WithField("status", st.Status).
+					WithField("desc", st.Description).
+					WithField("name", obj.Name).
+					WithField("namespace", obj.Namespace).
+					Error("failed to set status")
+			}
+		case *serviceapis.HTTPRoute:
+			err := e.StatusClient.SetHTTPRouteStatus(st.Status, st.Description, st.Gateways, obj)
+			if err != nil {
+				e.WithError(err).
+					WithField("status", st.Status).
+					WithField("desc", st.Description).
+					WithField("name", obj.Name).
+					WithField("namespace", obj.Namespace).
+					Error("failed to set status")
+			}
 		default:
 			e.WithField("namespace", obj.GetObjectMeta().GetNamespace()).
 				WithField("name", obj.GetObjectMeta().GetName()).
@@ -21,6 +21,7 @@ import (
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_api_v2_listener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
 	envoy_api_v2_accesslog "github.com/envoyproxy/go-control-plane/envoy/config/filter/accesslog/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	"github.com/envoyproxy/go-control-plane/pkg/cache"
@@ -32,6 +33,7 @@ import (
 const (
 	ENVOY_HTTP_LISTENER            = "ingress_http"
 	ENVOY_HTTPS_LISTENER           = "ingress_https"
+	ENVOY_FALLBACK_ROUTECONFIG     = "ingress_fallbackcert"
 	DEFAULT_HTTP_ACCESS_LOG        = "/dev/stdout"
 	DEFAULT_HTTP_LISTENER_ADDRESS  = "0.0.0.0"
 	DEFAULT_HTTP_LISTENER_PORT     = 8080
@@ -39,8 +41,34 @@ const (
 	DEFAULT_HTTPS_LISTENER_ADDRESS = DEFAULT_HTTP_LISTENER_ADDRESS
 	DEFAULT_HTTPS_LISTENER_PORT    = 8443
 	DEFAULT_ACCESS_LOG_TYPE        = "envoy"
+	ENVOY_RATELIMIT_CLUSTER        = "ratelimit"
 )
 
+// RateLimitConfig holds the configuration parameters of the
+// external rate limit service used for global rate limiting.
+type RateLimitConfig struct {
+	// Address and Port of the rate limit service's gRPC endpoint.
+	Address string
+	Port    int
+
+	// Domain is the rate limit domain passed to the rate
+	// limit service with every request.
+	Domain string
+
+	// Timeout is the timeout for requests to the rate limit service.
+	// If not set, Envoy's default is used.
+	Timeout time.Duration
+
+	// FailOpen allows requests through when the rate limit
+	// service cannot be reached or returns an error.
+	FailOpen bool
+}
+
+// Cluster returns the Envoy cluster for the rate limit service.
+func (rlc *RateLimitConfig) Cluster() *v2.Cluster {
+	return envoy.GRPCServiceCluster(ENVOY_RATELIMIT_CLUSTER, rlc.Address, rlc.Port)
+}
+
 // ListenerVisitorConfig holds configuration parameters for visitListeners.
 type ListenerVisitorConfig struct {
 	// Envoy's HTTP (non TLS) listener address.
@@ -75,6 +103,14 @@ type ListenerVisitorConfig struct {
 	// MinimumProtocolVersion defines the min tls protocol version to be used
 	MinimumProtocolVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
 
+	// MaximumProtocolVersion defines the max tls protocol version to be used.
+	// If not set, defaults to TLS 1.3.
+	MaximumProtocolVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
+
+	// CipherSuites defines the TLS 1.2 cipher suites offered to clients.
+	// If not set, defaults to Envoy's default cipher suites chosen by Contour.
+	CipherSuites []string
+
 	// AccessLogType defines if Envoy logs should be output as Envoy's default or JSON.
 	// Valid values: 'envoy', 'json'
 	// If not set, defaults to 'envoy'
@@ -87,6 +123,34 @@ type ListenerVisitorConfig struct {
 
 	// RequestTimeout configures the request_timeout for all Connection Managers.
 	RequestTimeout time.Duration
+
+	// RateLimitConfig configures the external rate limit service.
+	// If not set, global rate limiting is disabled.
+	RateLimitConfig *RateLimitConfig
+
+	// TracingConfig enables tracing on all Connection Managers.
+	// If not set, requests are not traced.
+	TracingConfig *envoy.TracingConfig
+
+	// XffNumTrustedHops sets the number of additional proxy hops in
+	// front of Envoy whose X-Forwarded-For entries are trusted.
+	// If not set, defaults to 0.
+	XffNumTrustedHops uint32
+
+	// SkipXffAppend stops all Connection Managers appending the
+	// client address to the X-Forwarded-For header.
+	// If not set, defaults to false.
+	SkipXffAppend bool
+
+	// GzipConfig configures, or disables, the gzip filter of all
+	// Connection Managers.
+	// If not set, Envoy's default gzip settings are used.
+	GzipConfig *envoy.GzipConfig
+
+	// DisableGRPCWeb removes the grpc-web filter from all
+	// Connection Managers.
+	// If not set, defaults to false.
+	DisableGRPCWeb bool
 }
 
 // httpAddress returns the port for the HTTP (non TLS)
@@ -192,6 +256,33 @@ func (lvc *ListenerVisitorConfig) requestTimeout() time.Duration {
 	return lvc.RequestTimeout
 }
 
+// httpConnectionManager returns a HTTP Connection Manager filter for
+// the supplied route configuration and access logger, including the
+// global rate limit filter if a rate limit service is configured.
+func (lvc *ListenerVisitorConfig) httpConnectionManager(routename string, accesslogger []*envoy_api_v2_accesslog.AccessLog, filters ...*http.HttpFilter) *envoy_api_v2_listener.Filter {
+	b := envoy.HTTPConnectionManagerBuilder().
+		RouteConfigName(routename).
+		MetricsPrefix(routename).
+		AccessLoggers(accesslogger).
+		RequestTimeout(lvc.requestTimeout()).
+		Tracing(lvc.TracingConfig).
+		NumTrustedHops(lvc.XffNumTrustedHops).
+		SkipXffAppend(lvc.SkipXffAppend).
+		Gzip(lvc.GzipConfig).
+		DisableGRPCWeb(lvc.DisableGRPCWeb).
+		DefaultFilters()
+
+	for _, f := range filters {
+		b.AddFilter(f)
+	}
+
+	if rlc := lvc.RateLimitConfig; rlc != nil {
+		b.AddFilter(envoy.GlobalRateLimitFilter(ENVOY_RATELIMIT_CLUSTER, rlc.Domain, rlc.Timeout, rlc.FailOpen))
+	}
+
+	return b.Get()
+}
+
 // minProtocolVersion returns the requested minimum TLS protocol
 // version or envoy_api_v2_auth.TlsParameters_TLSv1_1 if not configured {
 func (lvc *ListenerVisitorConfig) minProtoVersion() envoy_api_v2_auth.TlsParameters_TlsProtocol {
@@ -201,6 +292,15 @@ func (lvc *ListenerVisitorConfig) minProtoVersion() envoy_api_v2_auth.TlsParamet
 	return envoy_api_v2_auth.TlsParameters_TLSv1_1
 }
 
+// maxProtoVersion returns the requested maximum TLS protocol
+// version or envoy_api_v2_auth.TlsParameters_TLSv1_3 if not configured.
+func (lvc *ListenerVisitorConfig) maxProtoVersion() envoy_api_v2_auth.TlsParameters_TlsProtocol {
+	if lvc.MaximumProtocolVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO {
+		return lvc.MaximumProtocolVersion
+	}
+	return envoy_api_v2_auth.TlsParameters_TLSv1_3
+}
+
 // ListenerCache manages the contents of the gRPC LDS cache.
 type ListenerCache struct {
 	mu           sync.Mutex
@@ -281,8 +381,12 @@ func (*ListenerCache) TypeURL() string { return cache.ListenerType }
 type listenerVisitor struct {
 	*ListenerVisitorConfig
 
-	listeners map[string]*v2.Listener
-	http      bool // at least one dag.VirtualHost encountered
+	listeners      map[string]*v2.Listener
+	http           bool // at least one dag.VirtualHost encountered
+	localRateLimit bool // at least one dag.VirtualHost uses local rate limiting
+
+	fallbackCertificate    *dag.Secret // served to TLS clients without SNI, if set
+	fallbackLocalRateLimit bool        // at least one fallback dag.SecureVirtualHost uses local rate limiting
 }
 
 func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.Listener {
@@ -300,11 +404,18 @@ func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.
 
 	// add a listener if there are vhosts bound to http.
 	if lv.http {
+		// The local rate limit filter is only added when it is in use,
+		// as older Envoy versions do not support it.
+		var httpFilters []*http.HttpFilter
+		if lv.localRateLimit {
+			httpFilters = append(httpFilters, envoy.LocalRateLimitFilter(ENVOY_HTTP_LISTENER))
+		}
+
 		lv.listeners[ENVOY_HTTP_LISTENER] = envoy.Listener(
 			ENVOY_HTTP_LISTENER,
 			lvc.httpAddress(), lvc.httpPort(),
 			proxyProtocol(lvc.UseProxyProto),
-			envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, lvc.newInsecureAccessLog(), lvc.requestTimeout()),
+			lvc.httpConnectionManager(ENVOY_HTTP_LISTENER, lvc.newInsecureAccessLog(), httpFilters...),
 		)
 
 	}
@@ -322,11 +433,50 @@ func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.
 				// on the first slice entry.
 				return lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains[i].FilterChainMatch.ServerNames[0] < lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains[j].FilterChainMatch.ServerNames[0]
 			})
+
+		// clients without SNI are served by a single filter chain
+		// routing to every virtual host with the fallback certificate
+		// enabled. It has no server name so is not sorted with the others.
+		if lv.fallbackCertificate != nil {
+			var httpFilters []*http.HttpFilter
+			if lv.fallbackLocalRateLimit {
+				httpFilters = append(httpFilters, envoy.LocalRateLimitFilter(ENVOY_FALLBACK_ROUTECONFIG))
+			}
+			downstreamTLS := envoy.DownstreamTLSContext(
+				envoy.Secretname(lv.fallbackCertificate),
+				lvc.minProtoVersion(),
+				lvc.maxProtoVersion(),
+				lvc.CipherSuites,
+				nil,
+				"h2", "http/1.1",
+			)
+			filters := envoy.Filters(
+				lvc.httpConnectionManager(ENVOY_FALLBACK_ROUTECONFIG, lvc.newSecureAccessLog(), httpFilters...),
+			)
+			lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains = append(lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains,
+				envoy.FilterChainTLSFallback(downstreamTLS, filters))
+		}
 	}
 
 	return lv.listeners
 }
 
+// hasLocalRateLimit returns true if the virtual host, or any
+// of its routes, has a local rate limit policy.
+func hasLocalRateLimit(vh *dag.VirtualHost) bool {
+	if vh.RateLimitPolicy != nil && vh.RateLimitPolicy.Local != nil {
+		return true
+	}
+
+	found := false
+	vh.Visit(func(v dag.Vertex) {
+		if r, ok := v.(*dag.Route); ok && r.RateLimitPolicy != nil && r.RateLimitPolicy.Local != nil {
+			found = true
+		}
+	})
+	return found
+}
+
 func proxyProtocol(useProxy bool) []*envoy_api_v2_listener.ListenerFilter {
 	if useProxy {
 		return envoy.ListenerFilters(
@@ -349,14 +499,45 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 	}
 
 	switch vh := vertex.(type) {
+	case *dag.Listener:
+		if vh.TCPProxy != nil {
+			// additional TCP listeners forward every connection to
+			// their proxy without TLS inspection or SNI matching.
+			address := vh.Address
+			if address == "" {
+				address = DEFAULT_HTTP_LISTENER_ADDRESS
+			}
+			v.listeners[vh.Name] = envoy.Listener(
+				vh.Name,
+				address, vh.Port,
+				proxyProtocol(v.UseProxyProto),
+				envoy.TCPProxy(vh.Name, vh.TCPProxy, v.ListenerVisitorConfig.newInsecureAccessLog()),
+			)
+		}
+		vh.Visit(v.visit)
 	case *dag.VirtualHost:
 		// we only create on http listener so record the fact
 		// that we need to then double back at the end and add
 		// the listener properly.
 		v.http = true
+		if hasLocalRateLimit(vh) {
+			v.localRateLimit = true
+		}
 	case *dag.SecureVirtualHost:
+		var httpFilters []*http.HttpFilter
+		if hasLocalRateLimit(&vh.VirtualHost) {
+			httpFilters = append(httpFilters, envoy.LocalRateLimitFilter(ENVOY_HTTPS_LISTENER))
+		}
+		if vh.AuthorizationService != nil {
+			httpFilters = append(httpFilters, envoy.ExternalAuthzFilter(
+				envoy.Clustername(vh.AuthorizationService),
+				vh.AuthorizationResponseTimeout,
+				vh.AuthorizationFailOpen,
+			))
+		}
+
 		filters := envoy.Filters(
-			envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, v.ListenerVisitorConfig.newSecureAccessLog(), v.ListenerVisitorConfig.requestTimeout()),
+			v.ListenerVisitorConfig.httpConnectionManager(ENVOY_HTTPS_LISTENER, v.ListenerVisitorConfig.newSecureAccessLog(), httpFilters...),
 		)
 		alpnProtos := []string{"h2", "http/1.1"}
 		if vh.TCPProxy != nil {
@@ -366,13 +547,44 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 			alpnProtos = nil // do not offer ALPN
 		}
 
-		fc := envoy.FilterChainTLS(
-			vh.VirtualHost.Name,
-			vh.Secret,
-			filters,
-			max(v.ListenerVisitorConfig.minProtoVersion(), vh.MinProtoVersion), // choose the higher of the configured or requested tls version
-			alpnProtos...,
-		)
+		// Secret is provided when TLS is terminated and nil when TLS passthrough is used.
+		var downstreamTLS *envoy_api_v2_auth.DownstreamTlsContext
+		if vh.Secret != nil {
+			// choose the higher of the configured or requested tls version
+			minVersion := max(v.ListenerVisitorConfig.minProtoVersion(), vh.MinProtoVersion)
+
+			// the requested maximum version overrides the configured
+			// one; the DAG builder rejects a requested maximum below
+			// the minimum. Otherwise a requested minimum may raise the
+			// configured maximum.
+			maxVersion := vh.MaxProtoVersion
+			if maxVersion == envoy_api_v2_auth.TlsParameters_TLS_AUTO {
+				maxVersion = max(minVersion, v.ListenerVisitorConfig.maxProtoVersion())
+			}
+
+			cipherSuites := v.ListenerVisitorConfig.CipherSuites
+			if len(vh.CipherSuites) > 0 {
+				cipherSuites = vh.CipherSuites
+			}
+
+			downstreamTLS = envoy.DownstreamTLSContext(
+				envoy.Secretname(vh.Secret),
+				minVersion,
+				maxVersion,
+				cipherSuites,
+				vh.DownstreamValidation,
+				alpnProtos...,
+			)
+		}
+
+		fc := envoy.FilterChainTLS(vh.VirtualHost.Name, downstreamTLS, filters)
+
+		if vh.FallbackCertificate != nil {
+			v.fallbackCertificate = vh.FallbackCertificate
+			if hasLocalRateLimit(&vh.VirtualHost) {
+				v.fallbackLocalRateLimit = true
+			}
+		}
 
 		v.listeners[ENVOY_HTTPS_LISTENER].FilterChains = append(v.listeners[ENVOY_HTTPS_LISTENER].FilterChains, fc)
 	default:
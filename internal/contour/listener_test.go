@@ -15,6 +15,7 @@ package contour
 
 import (
 	"testing"
+	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
@@ -24,10 +25,12 @@ import (
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestListenerCacheContents(t *testing.T) {
@@ -269,6 +272,71 @@ func TestListenerVisit(t *testing.T) {
 				}},
 			}),
 		},
+		"wildcard ingress with secret": {
+			objs: []interface{}{
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "wildcard",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						TLS: []v1beta1.IngressTLS{{
+							Hosts:      []string{"*.example.com"},
+							SecretName: "secret",
+						}},
+						Rules: []v1beta1.IngressRule{{
+							Host: "*.example.com",
+							IngressRuleValue: v1beta1.IngressRuleValue{
+								HTTP: &v1beta1.HTTPIngressRuleValue{
+									Paths: []v1beta1.HTTPIngressPath{{
+										Backend: *backend("kuard", 8080),
+									}},
+								},
+							},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     8080,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"*.example.com"},
+					},
+					TransportSocket: transportSocket(envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+					Filters:         envoy.Filters(envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+				}},
+			}),
+		},
 		"multiple tls ingress with secrets should be sorted": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -1055,20 +1123,839 @@ func TestListenerVisit(t *testing.T) {
 				),
 			}),
 		},
-	}
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			root := buildDAG(t, tc.objs...)
-			got := visitListeners(root, &tc.ListenerVisitorConfig)
-			assert.Equal(t, tc.want, got)
-		})
-	}
+		"httpproxy with maximum protocol version and cipher suites": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName:             "secret",
+								MaximumProtocolVersion: "1.2",
+								CipherSuites:           []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+							},
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: envoy.DownstreamTLSTransportSocket(
+						envoy.DownstreamTLSContext(
+							"default/secret/68621186db",
+							envoy_api_v2_auth.TlsParameters_TLSv1_1,
+							envoy_api_v2_auth.TlsParameters_TLSv1_2,
+							[]string{"ECDHE-RSA-AES256-GCM-SHA384"},
+							nil,
+							"h2", "http/1.1",
+						),
+					),
+					Filters: envoy.Filters(envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
+		"tls-max-protocol-version and cipher suites from config": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				MaximumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+				CipherSuites:           []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+			},
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: envoy.DownstreamTLSTransportSocket(
+						envoy.DownstreamTLSContext(
+							"default/secret/68621186db",
+							envoy_api_v2_auth.TlsParameters_TLSv1_1,
+							envoy_api_v2_auth.TlsParameters_TLSv1_2,
+							[]string{"ECDHE-RSA-AES128-GCM-SHA256"},
+							nil,
+							"h2", "http/1.1",
+						),
+					),
+					Filters: envoy.Filters(envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
+		"tls-min-protocol-version from httpproxy raises configured maximum": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				MaximumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+			},
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName:             "secret",
+								MinimumProtocolVersion: "1.3",
+							},
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: envoy.DownstreamTLSTransportSocket(
+						envoy.DownstreamTLSContext(
+							"default/secret/68621186db",
+							envoy_api_v2_auth.TlsParameters_TLSv1_3,
+							envoy_api_v2_auth.TlsParameters_TLSv1_3, // note, raised to the requested minimum
+							nil,
+							nil,
+							"h2", "http/1.1",
+						),
+					),
+					Filters: envoy.Filters(envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
+		"global rate limit service configured": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				RateLimitConfig: &RateLimitConfig{
+					Address:  "ratelimit.projectcontour",
+					Port:     8081,
+					Domain:   "contour",
+					Timeout:  100 * time.Millisecond,
+					FailOpen: true,
+				},
+			},
+			objs: []interface{}{
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						Backend: backend("kuard", 8080),
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     8080,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(
+					envoy.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+						DefaultFilters().
+						AddFilter(envoy.GlobalRateLimitFilter(ENVOY_RATELIMIT_CLUSTER, "contour", 100*time.Millisecond, true)).
+						Get(),
+				),
+			}),
+		},
+		"tracing configured": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				TracingConfig: &envoy.TracingConfig{
+					ClientSampling:  100,
+					RandomSampling:  10,
+					OverallSampling: 100,
+				},
+			},
+			objs: []interface{}{
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						Backend: backend("kuard", 8080),
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     8080,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(
+					envoy.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+						Tracing(&envoy.TracingConfig{
+							ClientSampling:  100,
+							RandomSampling:  10,
+							OverallSampling: 100,
+						}).
+						DefaultFilters().
+						Get(),
+				),
+			}),
+		},
+		"trusted hops configured": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				XffNumTrustedHops: 2,
+			},
+			objs: []interface{}{
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						Backend: backend("kuard", 8080),
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     8080,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(
+					envoy.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+						NumTrustedHops(2).
+						DefaultFilters().
+						Get(),
+				),
+			}),
+		},
+		"trusted hops and skip xff append configured with tls": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				XffNumTrustedHops: 1,
+				SkipXffAppend:     true,
+			},
+			objs: []interface{}{
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						TLS: []v1beta1.IngressTLS{{
+							Hosts:      []string{"whatever.example.com"},
+							SecretName: "secret",
+						}},
+						Rules: []v1beta1.IngressRule{{
+							Host: "whatever.example.com",
+							IngressRuleValue: v1beta1.IngressRuleValue{
+								HTTP: &v1beta1.HTTPIngressRuleValue{
+									Paths: []v1beta1.HTTPIngressPath{{
+										Backend: *backend("kuard", 8080),
+									}},
+								},
+							},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     8080,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(
+					envoy.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+						NumTrustedHops(1).
+						SkipXffAppend(true).
+						DefaultFilters().
+						Get(),
+				),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"whatever.example.com"},
+					},
+					TransportSocket: transportSocket(envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+					Filters: envoy.Filters(
+						envoy.HTTPConnectionManagerBuilder().
+							RouteConfigName(ENVOY_HTTPS_LISTENER).
+							MetricsPrefix(ENVOY_HTTPS_LISTENER).
+							AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTPS_ACCESS_LOG)).
+							NumTrustedHops(1).
+							SkipXffAppend(true).
+							DefaultFilters().
+							Get(),
+					),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
+		"httpproxy with local rate limit policy": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+							RateLimitPolicy: &projcontour.RateLimitPolicy{
+								Local: &projcontour.LocalRateLimitPolicy{
+									Requests: 100,
+									Unit:     "second",
+								},
+							},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(
+					envoy.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+						DefaultFilters().
+						AddFilter(envoy.LocalRateLimitFilter(ENVOY_HTTP_LISTENER)).
+						Get(),
+				),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocket(envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+					Filters: envoy.Filters(
+						envoy.HTTPConnectionManagerBuilder().
+							RouteConfigName(ENVOY_HTTPS_LISTENER).
+							MetricsPrefix(ENVOY_HTTPS_LISTENER).
+							AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+							DefaultFilters().
+							AddFilter(envoy.LocalRateLimitFilter(ENVOY_HTTPS_LISTENER)).
+							Get(),
+					),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
+		"httpproxy with authorization": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName: "secret",
+							},
+							Authorization: &projcontour.AuthorizationServer{
+								ServiceRef: projcontour.ExtensionServiceReference{
+									Name: "auth",
+									Port: 9000,
+								},
+								ResponseTimeout: "500ms",
+							},
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "auth",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "grpc",
+							Protocol: "TCP",
+							Port:     9000,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocket(envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+					Filters: envoy.Filters(
+						envoy.HTTPConnectionManagerBuilder().
+							RouteConfigName(ENVOY_HTTPS_LISTENER).
+							MetricsPrefix(ENVOY_HTTPS_LISTENER).
+							AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
+							DefaultFilters().
+							AddFilter(envoy.ExternalAuthzFilter("default/auth/9000/da39a3ee5e", 500*time.Millisecond, false)).
+							Get(),
+					),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := buildDAG(t, tc.objs...)
+			got := visitListeners(root, &tc.ListenerVisitorConfig)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestListenerVisitTCPListeners(t *testing.T) {
+	postgres := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "postgres",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "postgres",
+				Protocol: "TCP",
+				Port:     5432,
+			}},
+		},
+	}
+	tcpproxy := &dag.TCPProxy{
+		Clusters: []*dag.Cluster{{
+			Upstream: &dag.Service{
+				Name:        "postgres",
+				Namespace:   "default",
+				ServicePort: &postgres.Spec.Ports[0],
+			},
+		}},
+	}
+
+	tests := map[string]struct {
+		ListenerVisitorConfig
+		listeners []dag.TCPListenerConfig
+		objs      []interface{}
+		want      map[string]*v2.Listener
+	}{
+		"tcp listener": {
+			listeners: []dag.TCPListenerConfig{{
+				Name:        "postgres",
+				Port:        5432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "default"},
+				ServicePort: 5432,
+			}},
+			objs: []interface{}{postgres},
+			want: listenermap(&v2.Listener{
+				Name:         "postgres",
+				Address:      envoy.SocketAddress("0.0.0.0", 5432),
+				FilterChains: envoy.FilterChains(envoy.TCPProxy("postgres", tcpproxy, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG))),
+			}),
+		},
+		"tcp listener with address and proxy protocol": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				UseProxyProto: true,
+			},
+			listeners: []dag.TCPListenerConfig{{
+				Name:        "postgres",
+				Address:     "127.0.0.1",
+				Port:        15432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "default"},
+				ServicePort: 5432,
+			}},
+			objs: []interface{}{postgres},
+			want: listenermap(&v2.Listener{
+				Name:    "postgres",
+				Address: envoy.SocketAddress("127.0.0.1", 15432),
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.ProxyProtocol(),
+				),
+				FilterChains: envoy.FilterChains(envoy.TCPProxy("postgres", tcpproxy, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG))),
+			}),
+		},
+		"tcp listener missing service": {
+			listeners: []dag.TCPListenerConfig{{
+				Name:        "postgres",
+				Port:        5432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "default"},
+				ServicePort: 5432,
+			}},
+			want: map[string]*v2.Listener{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := dag.Builder{
+				Source: dag.KubernetesCache{
+					TCPListeners: tc.listeners,
+					FieldLogger:  testLogger(t),
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			got := visitListeners(builder.Build(), &tc.ListenerVisitorConfig)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestListenerVisitFallbackCertificate(t *testing.T) {
+	proxy := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "www.example.com",
+				TLS: &projcontour.TLS{
+					SecretName:                "secret",
+					EnableFallbackCertificate: true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		},
+	}
+	objs := []interface{}{
+		proxy,
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "default",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fallbacksecret",
+				Namespace: "projectcontour",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backend",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Protocol: "TCP",
+					Port:     80,
+				}},
+			},
+		},
+	}
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FallbackCertificate: &types.NamespacedName{
+				Name:      "fallbacksecret",
+				Namespace: "projectcontour",
+			},
+			FieldLogger: testLogger(t),
+		},
+	}
+	for _, o := range objs {
+		builder.Source.Insert(o)
+	}
+
+	got := visitListeners(builder.Build(), &ListenerVisitorConfig{})
+	want := listenermap(&v2.Listener{
+		Name:         ENVOY_HTTP_LISTENER,
+		Address:      envoy.SocketAddress("0.0.0.0", 8080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+	}, &v2.Listener{
+		Name:    ENVOY_HTTPS_LISTENER,
+		Address: envoy.SocketAddress("0.0.0.0", 8443),
+		ListenerFilters: envoy.ListenerFilters(
+			envoy.TLSInspector(),
+		),
+		FilterChains: []*envoy_api_v2_listener.FilterChain{{
+			FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+				ServerNames: []string{"www.example.com"},
+			},
+			TransportSocket: transportSocket(envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+			Filters:         envoy.Filters(envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+		}, {
+			FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+				TransportProtocol: "tls",
+			},
+			TransportSocket: envoy.DownstreamTLSTransportSocket(
+				envoy.DownstreamTLSContext("projectcontour/fallbacksecret/68621186db", envoy_api_v2_auth.TlsParameters_TLSv1_1, envoy_api_v2_auth.TlsParameters_TLSv1_3, nil, nil, "h2", "http/1.1"),
+			),
+			Filters: envoy.Filters(envoy.HTTPConnectionManager(ENVOY_FALLBACK_ROUTECONFIG, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+		}},
+	})
+	assert.Equal(t, want, got)
 }
 
 func transportSocket(tlsMinProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol, alpnprotos ...string) *envoy_api_v2_core.TransportSocket {
 	return envoy.DownstreamTLSTransportSocket(
-		envoy.DownstreamTLSContext("default/secret/68621186db", tlsMinProtoVersion, alpnprotos...),
+		envoy.DownstreamTLSContext("default/secret/68621186db", tlsMinProtoVersion, envoy_api_v2_auth.TlsParameters_TLSv1_3, nil, nil, alpnprotos...),
 	)
 }
 
@@ -21,7 +21,9 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 	"github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 )
@@ -130,8 +132,15 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 						})
 					} else {
 						rt := &envoy_api_v2_route.Route{
-							Match:  envoy.RouteMatch(route),
-							Action: envoy.RouteRoute(route),
+							Match: envoy.RouteMatch(route),
+						}
+						switch {
+						case route.Redirect != nil:
+							rt.Action = envoy.RouteRedirect(route.Redirect)
+						case route.DirectResponse != nil:
+							rt.Action = envoy.RouteDirectResponse(route.DirectResponse)
+						default:
+							rt.Action = envoy.RouteRoute(route)
 						}
 						if route.RequestHeadersPolicy != nil {
 							rt.RequestHeadersToAdd = envoy.HeaderValueList(route.RequestHeadersPolicy.Set, false)
@@ -141,6 +150,12 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 							rt.ResponseHeadersToAdd = envoy.HeaderValueList(route.ResponseHeadersPolicy.Set, false)
 							rt.ResponseHeadersToRemove = route.ResponseHeadersPolicy.Remove
 						}
+						if route.RateLimitPolicy != nil && route.RateLimitPolicy.Local != nil {
+							rt.TypedPerFilterConfig = map[string]*any.Any{
+								envoy.LocalRateLimitFilterName: envoy.LocalRateLimitConfig(route.RateLimitPolicy.Local, "vhost."+vh.Name),
+							}
+						}
+						rt.Tracing = envoy.RouteTracing(vh.TracingPolicy)
 						routes = append(routes, rt)
 					}
 				})
@@ -151,6 +166,18 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 
 				sortRoutes(routes)
 				vhost := envoy.VirtualHost(vh.Name, routes...)
+				if vh.RateLimitPolicy != nil && vh.RateLimitPolicy.Local != nil {
+					vhost.TypedPerFilterConfig = map[string]*any.Any{
+						envoy.LocalRateLimitFilterName: envoy.LocalRateLimitConfig(vh.RateLimitPolicy.Local, "vhost."+vh.Name),
+					}
+				}
+				if vh.RateLimitPolicy != nil && vh.RateLimitPolicy.Global != nil {
+					vhost.RateLimits = envoy.GlobalRateLimits(vh.RateLimitPolicy.Global.Descriptors)
+				}
+				vhost.Cors = envoy.CORSPolicy(vh.CORSPolicy)
+				if vh.DisableCompression {
+					vhost.ResponseHeadersToAdd = envoy.DisableCompressionHeaders()
+				}
 				v.routes["ingress_http"].VirtualHosts = append(v.routes["ingress_http"].VirtualHosts, vhost)
 			case *dag.SecureVirtualHost:
 				var routes []*envoy_api_v2_route.Route
@@ -161,8 +188,15 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 					}
 
 					rt := &envoy_api_v2_route.Route{
-						Match:  envoy.RouteMatch(route),
-						Action: envoy.RouteRoute(route),
+						Match: envoy.RouteMatch(route),
+					}
+					switch {
+					case route.Redirect != nil:
+						rt.Action = envoy.RouteRedirect(route.Redirect)
+					case route.DirectResponse != nil:
+						rt.Action = envoy.RouteDirectResponse(route.DirectResponse)
+					default:
+						rt.Action = envoy.RouteRoute(route)
 					}
 					if route.RequestHeadersPolicy != nil {
 						rt.RequestHeadersToAdd = envoy.HeaderValueList(route.RequestHeadersPolicy.Set, false)
@@ -172,6 +206,20 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 						rt.ResponseHeadersToAdd = envoy.HeaderValueList(route.ResponseHeadersPolicy.Set, false)
 						rt.ResponseHeadersToRemove = route.ResponseHeadersPolicy.Remove
 					}
+					if route.RateLimitPolicy != nil && route.RateLimitPolicy.Local != nil {
+						rt.TypedPerFilterConfig = map[string]*any.Any{
+							envoy.LocalRateLimitFilterName: envoy.LocalRateLimitConfig(route.RateLimitPolicy.Local, "vhost."+vh.VirtualHost.Name),
+						}
+					}
+					rt.Tracing = envoy.RouteTracing(vh.TracingPolicy)
+					if vh.AuthorizationService != nil {
+						if authz := envoy.ExternalAuthzConfig(route.AuthDisabled, route.AuthContext); authz != nil {
+							if rt.TypedPerFilterConfig == nil {
+								rt.TypedPerFilterConfig = map[string]*any.Any{}
+							}
+							rt.TypedPerFilterConfig[wellknown.HTTPExternalAuthorization] = authz
+						}
+					}
 					routes = append(routes, rt)
 				})
 				if len(routes) < 1 {
@@ -179,7 +227,27 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 				}
 				sortRoutes(routes)
 				vhost := envoy.VirtualHost(vh.VirtualHost.Name, routes...)
+				if vh.RateLimitPolicy != nil && vh.RateLimitPolicy.Local != nil {
+					vhost.TypedPerFilterConfig = map[string]*any.Any{
+						envoy.LocalRateLimitFilterName: envoy.LocalRateLimitConfig(vh.RateLimitPolicy.Local, "vhost."+vh.VirtualHost.Name),
+					}
+				}
+				if vh.RateLimitPolicy != nil && vh.RateLimitPolicy.Global != nil {
+					vhost.RateLimits = envoy.GlobalRateLimits(vh.RateLimitPolicy.Global.Descriptors)
+				}
+				vhost.Cors = envoy.CORSPolicy(vh.CORSPolicy)
+				if vh.DisableCompression {
+					vhost.ResponseHeadersToAdd = envoy.DisableCompressionHeaders()
+				}
 				v.routes["ingress_https"].VirtualHosts = append(v.routes["ingress_https"].VirtualHosts, vhost)
+				if vh.FallbackCertificate != nil {
+					// clients without SNI are routed to this
+					// virtual host using their Host header.
+					if _, ok := v.routes[ENVOY_FALLBACK_ROUTECONFIG]; !ok {
+						v.routes[ENVOY_FALLBACK_ROUTECONFIG] = envoy.RouteConfiguration(ENVOY_FALLBACK_ROUTECONFIG)
+					}
+					v.routes[ENVOY_FALLBACK_ROUTECONFIG].VirtualHosts = append(v.routes[ENVOY_FALLBACK_ROUTECONFIG].VirtualHosts, vhost)
+				}
 			default:
 				// recurse
 				vertex.Visit(v.visit)
@@ -231,7 +299,8 @@ func sortRoutes(routes []*envoy_api_v2_route.Route) {
 }
 
 // longestRouteByHeaders compares the HeaderMatcher slices for lhs and rhs and
-// returns true if lhs is longer.
+// returns true if lhs is longer. Routes with equivalent HeaderMatcher slices
+// are compared by the number of QueryParameterMatchers.
 func longestRouteByHeaders(lhs, rhs *envoy_api_v2_route.Route) bool {
 	if len(lhs.Match.Headers) == len(rhs.Match.Headers) {
 		pair := make([]*envoy_api_v2_route.HeaderMatcher, 2)
@@ -243,7 +312,14 @@ func longestRouteByHeaders(lhs, rhs *envoy_api_v2_route.Route) bool {
 			if headerMatcherByName(pair).Less(0, 1) {
 				return true
 			}
+			if headerMatcherByName(pair).Less(1, 0) {
+				return false
+			}
 		}
+
+		// The header matches are equivalent, so sort the
+		// route with more query parameter matches first.
+		return len(lhs.Match.QueryParameters) > len(rhs.Match.QueryParameters)
 	}
 
 	return len(lhs.Match.Headers) > len(rhs.Match.Headers)
@@ -255,6 +331,24 @@ func (l longestRouteFirst) Len() int      { return len(l) }
 func (l longestRouteFirst) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
 func (l longestRouteFirst) Less(i, j int) bool {
 	switch a := l[i].Match.PathSpecifier.(type) {
+	case *envoy_api_v2_route.RouteMatch_Path:
+		switch b := l[j].Match.PathSpecifier.(type) {
+		case *envoy_api_v2_route.RouteMatch_Path:
+			cmp := strings.Compare(a.Path, b.Path)
+			switch cmp {
+			case 1:
+				// Sort longest path first.
+				return true
+			case -1:
+				return false
+			default:
+				return longestRouteByHeaders(l[i], l[j])
+			}
+		case *envoy_api_v2_route.RouteMatch_SafeRegex, *envoy_api_v2_route.RouteMatch_Prefix:
+			// Exact paths are more specific than
+			// regex or prefix matches.
+			return true
+		}
 	case *envoy_api_v2_route.RouteMatch_Prefix:
 		switch b := l[j].Match.PathSpecifier.(type) {
 		case *envoy_api_v2_route.RouteMatch_Prefix:
@@ -23,7 +23,11 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -32,6 +36,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -1389,6 +1394,600 @@ func TestRouteVisit(t *testing.T) {
 				envoy.RouteConfiguration("ingress_https"),
 			),
 		},
+		"httpproxy with global rate limit policies": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							RateLimitPolicy: &projcontour.RateLimitPolicy{
+								Global: &projcontour.GlobalRateLimitPolicy{
+									Descriptors: []projcontour.RateLimitDescriptor{{
+										Entries: []projcontour.RateLimitDescriptorEntry{{
+											RemoteAddress: &projcontour.RemoteAddressDescriptor{},
+										}},
+									}},
+								},
+							},
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+							RateLimitPolicy: &projcontour.RateLimitPolicy{
+								Global: &projcontour.GlobalRateLimitPolicy{
+									Descriptors: []projcontour.RateLimitDescriptor{{
+										Entries: []projcontour.RateLimitDescriptorEntry{{
+											GenericKey: &projcontour.GenericKeyDescriptor{
+												Value: "backend",
+											},
+										}},
+									}},
+								},
+							},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					&envoy_api_v2_route.VirtualHost{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:*"},
+						Routes: []*envoy_api_v2_route.Route{{
+							Match: routePrefix("/"),
+							Action: withRateLimits(routecluster("default/backend/80/da39a3ee5e"), &envoy_api_v2_route.RateLimit_Action{
+								ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_GenericKey_{
+									GenericKey: &envoy_api_v2_route.RateLimit_Action_GenericKey{
+										DescriptorValue: "backend",
+									},
+								},
+							}),
+						}},
+						RateLimits: []*envoy_api_v2_route.RateLimit{{
+							Actions: []*envoy_api_v2_route.RateLimit_Action{{
+								ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_RemoteAddress_{
+									RemoteAddress: &envoy_api_v2_route.RateLimit_Action_RemoteAddress{},
+								},
+							}},
+						}},
+					},
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
+		"httpproxy with local rate limit policies": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							RateLimitPolicy: &projcontour.RateLimitPolicy{
+								Local: &projcontour.LocalRateLimitPolicy{
+									Requests: 100,
+									Unit:     "second",
+								},
+							},
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+							RateLimitPolicy: &projcontour.RateLimitPolicy{
+								Local: &projcontour.LocalRateLimitPolicy{
+									Requests:           10,
+									Unit:               "minute",
+									Burst:              5,
+									ResponseStatusCode: 503,
+								},
+							},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					&envoy_api_v2_route.VirtualHost{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:*"},
+						Routes: []*envoy_api_v2_route.Route{{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+							TypedPerFilterConfig: map[string]*any.Any{
+								envoy.LocalRateLimitFilterName: envoy.LocalRateLimitConfig(&dag.LocalRateLimitPolicy{
+									MaxTokens:          15,
+									TokensPerFill:      10,
+									FillInterval:       time.Minute,
+									ResponseStatusCode: 503,
+								}, "vhost.www.example.com"),
+							},
+						}},
+						TypedPerFilterConfig: map[string]*any.Any{
+							envoy.LocalRateLimitFilterName: envoy.LocalRateLimitConfig(&dag.LocalRateLimitPolicy{
+								MaxTokens:     100,
+								TokensPerFill: 100,
+								FillInterval:  time.Second,
+							}, "vhost.www.example.com"),
+						},
+					},
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
+		"httpproxy with authorization": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &projcontour.TLS{
+								SecretName: "secret",
+							},
+							Authorization: &projcontour.AuthorizationServer{
+								ServiceRef: projcontour.ExtensionServiceReference{
+									Name: "auth",
+									Port: 9000,
+								},
+							},
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+							AuthPolicy: &projcontour.AuthorizationPolicy{
+								Context: map[string]string{
+									"team": "a",
+								},
+							},
+						}, {
+							Conditions: []projcontour.Condition{{
+								Prefix: "/public",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+							PermitInsecure: true,
+							AuthPolicy: &projcontour.AuthorizationPolicy{
+								Disabled: true,
+							},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "auth",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       9000,
+							TargetPort: intstr.FromInt(9000),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					envoy.VirtualHost("www.example.com",
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/public"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						},
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/"),
+							Action: envoy.UpgradeHTTPS(),
+						},
+					),
+				),
+				envoy.RouteConfiguration("ingress_https",
+					envoy.VirtualHost("www.example.com",
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/public"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+							TypedPerFilterConfig: map[string]*any.Any{
+								wellknown.HTTPExternalAuthorization: envoy.ExternalAuthzConfig(true, nil),
+							},
+						},
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+							TypedPerFilterConfig: map[string]*any.Any{
+								wellknown.HTTPExternalAuthorization: envoy.ExternalAuthzConfig(false, map[string]string{"team": "a"}),
+							},
+						},
+					),
+				),
+			),
+		},
+		"httpproxy with cors policy": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							CORSPolicy: &projcontour.CORSPolicy{
+								AllowOrigin:  []string{"https://app.example.com"},
+								AllowMethods: []projcontour.CORSHeaderValue{"GET", "OPTIONS"},
+								AllowHeaders: []projcontour.CORSHeaderValue{"authorization"},
+								MaxAge:       "1h",
+							},
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					&envoy_api_v2_route.VirtualHost{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:*"},
+						Routes: []*envoy_api_v2_route.Route{{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}},
+						Cors: &envoy_api_v2_route.CorsPolicy{
+							AllowOriginStringMatch: []*matcher.StringMatcher{{
+								MatchPattern: &matcher.StringMatcher_Exact{
+									Exact: "https://app.example.com",
+								},
+							}},
+							AllowCredentials: protobuf.Bool(false),
+							AllowMethods:     "GET,OPTIONS",
+							AllowHeaders:     "authorization",
+							MaxAge:           "3600",
+						},
+					},
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
+		"httpproxy with tracing policy": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							Tracing: &projcontour.TracingPolicy{
+								RandomSampling: 10,
+							},
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					envoy.VirtualHost("www.example.com",
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+							Tracing: &envoy_api_v2_route.Tracing{
+								RandomSampling: &envoy_type.FractionalPercent{
+									Numerator:   10,
+									Denominator: envoy_type.FractionalPercent_HUNDRED,
+								},
+							},
+						},
+					),
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
+		"httpproxy with compression disabled": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							Compression: &projcontour.CompressionPolicy{
+								Disabled: true,
+							},
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					&envoy_api_v2_route.VirtualHost{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:*"},
+						Routes: []*envoy_api_v2_route.Route{{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}},
+						ResponseHeadersToAdd: envoy.DisableCompressionHeaders(),
+					},
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
+		"httpproxy with request redirect policy": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							RequestRedirectPolicy: &projcontour.HTTPRequestRedirectPolicy{
+								Scheme:     "https",
+								Hostname:   "new.example.com",
+								StatusCode: 301,
+							},
+						}, {
+							Conditions: []projcontour.Condition{{
+								Prefix: "/api",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					envoy.VirtualHost("www.example.com",
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/api"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						},
+						&envoy_api_v2_route.Route{
+							Match: routePrefix("/"),
+							Action: &envoy_api_v2_route.Route_Redirect{
+								Redirect: &envoy_api_v2_route.RedirectAction{
+									SchemeRewriteSpecifier: &envoy_api_v2_route.RedirectAction_SchemeRedirect{
+										SchemeRedirect: "https",
+									},
+									HostRedirect: "new.example.com",
+									ResponseCode: envoy_api_v2_route.RedirectAction_MOVED_PERMANENTLY,
+								},
+							},
+						},
+					),
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
+		"httpproxy with direct response policy": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Conditions: []projcontour.Condition{{
+								Exact: "/robots.txt",
+							}},
+							DirectResponsePolicy: &projcontour.HTTPDirectResponsePolicy{
+								StatusCode: 200,
+								Body:       "User-agent: *\nDisallow: /\n",
+							},
+						}, {
+							Conditions: []projcontour.Condition{{
+								Prefix: "/",
+							}},
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					envoy.VirtualHost("www.example.com",
+						&envoy_api_v2_route.Route{
+							Match: routeExact("/robots.txt"),
+							Action: &envoy_api_v2_route.Route_DirectResponse{
+								DirectResponse: &envoy_api_v2_route.DirectResponseAction{
+									Status: 200,
+									Body: &envoy_api_v2_core.DataSource{
+										Specifier: &envoy_api_v2_core.DataSource_InlineString{
+											InlineString: "User-agent: *\nDisallow: /\n",
+										},
+									},
+								},
+							},
+						},
+						&envoy_api_v2_route.Route{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						},
+					),
+				),
+				envoy.RouteConfiguration("ingress_https"),
+			),
+		},
 		"httpproxy with mirror policy": {
 			objs: []interface{}{
 				&projcontour.HTTPProxy{
@@ -2067,6 +2666,103 @@ func TestRouteVisit(t *testing.T) {
 	}
 }
 
+func TestRouteVisitFallbackCertificate(t *testing.T) {
+	objs := []interface{}{
+		&projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "simple",
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: "www.example.com",
+					TLS: &projcontour.TLS{
+						SecretName:                "secret",
+						EnableFallbackCertificate: true,
+					},
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "backend",
+						Port: 80,
+					}},
+				}},
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "default",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fallbacksecret",
+				Namespace: "projectcontour",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backend",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		},
+	}
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FallbackCertificate: &types.NamespacedName{
+				Name:      "fallbacksecret",
+				Namespace: "projectcontour",
+			},
+			FieldLogger: testLogger(t),
+		},
+	}
+	for _, o := range objs {
+		builder.Source.Insert(o)
+	}
+
+	got := visitRoutes(builder.Build())
+	want := routeConfigurations(
+		envoy.RouteConfiguration("ingress_http",
+			envoy.VirtualHost("www.example.com",
+				&envoy_api_v2_route.Route{
+					Match:  routePrefix("/"),
+					Action: envoy.UpgradeHTTPS(),
+				},
+			),
+		),
+		envoy.RouteConfiguration("ingress_https",
+			envoy.VirtualHost("www.example.com",
+				&envoy_api_v2_route.Route{
+					Match:  routePrefix("/"),
+					Action: routecluster("default/backend/80/da39a3ee5e"),
+				},
+			),
+		),
+		envoy.RouteConfiguration(ENVOY_FALLBACK_ROUTECONFIG,
+			envoy.VirtualHost("www.example.com",
+				&envoy_api_v2_route.Route{
+					Match:  routePrefix("/"),
+					Action: routecluster("default/backend/80/da39a3ee5e"),
+				},
+			),
+		),
+	)
+	assert.Equal(t, want, got)
+}
+
 func TestSortLongestRouteFirst(t *testing.T) {
 	tests := map[string]struct {
 		routes []*envoy_api_v2_route.Route
@@ -2112,6 +2808,26 @@ func TestSortLongestRouteFirst(t *testing.T) {
 				Match: routePrefix("/"),
 			}},
 		},
+		"exact sorts before regex and prefix": {
+			routes: []*envoy_api_v2_route.Route{{
+				Match: routePrefix("/"),
+			}, {
+				Match: routeRegex("/api/.*"),
+			}, {
+				Match: routeExact("/api"),
+			}, {
+				Match: routeExact("/api/v1"),
+			}},
+			want: []*envoy_api_v2_route.Route{{
+				Match: routeExact("/api/v1"),
+			}, {
+				Match: routeExact("/api"),
+			}, {
+				Match: routeRegex("/api/.*"),
+			}, {
+				Match: routePrefix("/"),
+			}},
+		},
 		"more headers sort before less": {
 			routes: []*envoy_api_v2_route.Route{{
 				Match: routePrefix("/"),
@@ -2131,6 +2847,61 @@ func TestSortLongestRouteFirst(t *testing.T) {
 			}},
 		},
 
+		"header matchers with the same name sort stably by match": {
+			routes: []*envoy_api_v2_route.Route{{
+				Match: routePrefix("/",
+					dag.HeaderCondition{Name: "user-agent", Value: "bot", MatchType: "suffix"},
+				),
+			}, {
+				Match: routePrefix("/",
+					dag.HeaderCondition{Name: "user-agent", Value: ".*Chrome.*", MatchType: "regex"},
+				),
+			}, {
+				Match: routePrefix("/",
+					dag.HeaderCondition{Name: "user-agent", Value: "Mozilla", MatchType: "prefix"},
+				),
+			}},
+			want: []*envoy_api_v2_route.Route{{
+				Match: routePrefix("/",
+					dag.HeaderCondition{Name: "user-agent", Value: "Mozilla", MatchType: "prefix"},
+				),
+			}, {
+				Match: routePrefix("/",
+					dag.HeaderCondition{Name: "user-agent", Value: ".*Chrome.*", MatchType: "regex"},
+				),
+			}, {
+				Match: routePrefix("/",
+					dag.HeaderCondition{Name: "user-agent", Value: "bot", MatchType: "suffix"},
+				),
+			}},
+		},
+		"more query parameters sort before less": {
+			routes: []*envoy_api_v2_route.Route{{
+				Match: routePrefix("/"),
+			}, {
+				Match: envoy.RouteMatch(&dag.Route{
+					PathCondition: &dag.PrefixCondition{Prefix: "/"},
+					QueryParamConditions: []dag.QueryParamCondition{{
+						Name:      "version",
+						Value:     "v1",
+						MatchType: "exact",
+					}},
+				}),
+			}},
+			want: []*envoy_api_v2_route.Route{{
+				Match: envoy.RouteMatch(&dag.Route{
+					PathCondition: &dag.PrefixCondition{Prefix: "/"},
+					QueryParamConditions: []dag.QueryParamCondition{{
+						Name:      "version",
+						Value:     "v1",
+						MatchType: "exact",
+					}},
+				}),
+			}, {
+				Match: routePrefix("/"),
+			}},
+		},
+
 		// Verify that longest path sorts before longest
 		// headers. We used to sort by longest header list
 		// first, which does end up with the same net result,
@@ -2287,6 +3058,15 @@ func routeRegex(regex string, headers ...dag.HeaderCondition) *envoy_api_v2_rout
 	})
 }
 
+func routeExact(path string, headers ...dag.HeaderCondition) *envoy_api_v2_route.RouteMatch {
+	return envoy.RouteMatch(&dag.Route{
+		PathCondition: &dag.ExactCondition{
+			Path: path,
+		},
+		HeaderConditions: headers,
+	})
+}
+
 func routePrefix(prefix string, headers ...dag.HeaderCondition) *envoy_api_v2_route.RouteMatch {
 	return envoy.RouteMatch(&dag.Route{
 		PathCondition: &dag.PrefixCondition{
@@ -2321,3 +3101,10 @@ func withMirrorPolicy(route *envoy_api_v2_route.Route_Route, mirror string) *env
 	}}
 	return route
 }
+
+func withRateLimits(route *envoy_api_v2_route.Route_Route, actions ...*envoy_api_v2_route.RateLimit_Action) *envoy_api_v2_route.Route_Route {
+	route.Route.RateLimits = append(route.Route.RateLimits, &envoy_api_v2_route.RateLimit{
+		Actions: actions,
+	})
+	return route
+}
@@ -92,16 +92,27 @@ func visitSecrets(root dag.Vertex) map[string]*envoy_api_v2_auth.Secret {
 }
 
 func (v *secretVisitor) visit(vertex dag.Vertex) {
-	switch svh := vertex.(type) {
+	switch obj := vertex.(type) {
 	case *dag.SecureVirtualHost:
-		if svh.Secret != nil {
-			name := envoy.Secretname(svh.Secret)
-			if _, ok := v.secrets[name]; !ok {
-				s := envoy.Secret(svh.Secret)
-				v.secrets[s.Name] = s
-			}
-		}
+		v.addSecret(obj.Secret)
+		v.addSecret(obj.FallbackCertificate)
+		obj.Visit(v.visit)
+	case *dag.Cluster:
+		v.addSecret(obj.ClientCertificate)
 	default:
 		vertex.Visit(v.visit)
 	}
 }
+
+// addSecret adds the supplied secret to the map of secrets,
+// if it is not already present.
+func (v *secretVisitor) addSecret(secret *dag.Secret) {
+	if secret == nil {
+		return
+	}
+	name := envoy.Secretname(secret)
+	if _, ok := v.secrets[name]; !ok {
+		s := envoy.Secret(secret)
+		v.secrets[s.Name] = s
+	}
+}
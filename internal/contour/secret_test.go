@@ -103,6 +103,8 @@ func TestSecretCacheQuery(t *testing.T) {
 }
 
 func TestSecretVisit(t *testing.T) {
+	tlsProtocol := "tls"
+
 	tests := map[string]struct {
 		objs []interface{}
 		want map[string]*envoy_api_v2_auth.Secret
@@ -464,6 +466,47 @@ func TestSecretVisit(t *testing.T) {
 				secret("default/secret-b/5397c67313", secretdata(CERTIFICATE_2, RSA_PRIVATE_KEY_2)),
 			),
 		},
+		"httpproxy with upstream client certificate": {
+			objs: []interface{}{
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:       "https",
+							Protocol:   "TCP",
+							Port:       443,
+							TargetPort: intstr.FromInt(8443),
+						}},
+					},
+				},
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name:              "backend",
+								Port:              443,
+								Protocol:          &tlsProtocol,
+								ClientCertificate: "clientcert",
+							}},
+						}},
+					},
+				},
+				tlssecret("default", "clientcert", secretdata(CERTIFICATE, RSA_PRIVATE_KEY)),
+			},
+			want: secretmap(
+				secret("default/clientcert/68621186db", secretdata(CERTIFICATE, RSA_PRIVATE_KEY)),
+			),
+		},
 	}
 
 	for name, tc := range tests {
@@ -481,6 +524,8 @@ func buildDAG(t *testing.T, objs ...interface{}) *dag.DAG {
 		Source: dag.KubernetesCache{
 			FieldLogger: testLogger(t),
 		},
+		EnableGlobalRateLimit: true,
+		EnableLocalRateLimit:  true,
 	}
 
 	for _, o := range objs {
@@ -215,6 +215,59 @@ func MinProtoVersion(version string) envoy_api_v2_auth.TlsParameters_TlsProtocol
 	}
 }
 
+// MaxProtoVersion returns the TLS protocol version named by version,
+// or TLS_AUTO if version is empty.
+func MaxProtoVersion(version string) (envoy_api_v2_auth.TlsParameters_TlsProtocol, error) {
+	switch version {
+	case "":
+		return envoy_api_v2_auth.TlsParameters_TLS_AUTO, nil
+	case "1.3":
+		return envoy_api_v2_auth.TlsParameters_TLSv1_3, nil
+	case "1.2":
+		return envoy_api_v2_auth.TlsParameters_TLSv1_2, nil
+	case "1.1":
+		return envoy_api_v2_auth.TlsParameters_TLSv1_1, nil
+	default:
+		return envoy_api_v2_auth.TlsParameters_TLS_AUTO, fmt.Errorf("invalid maximum protocol version %q", version)
+	}
+}
+
+// cipherSuites are the TLS 1.2 cipher suites Envoy can offer.
+var cipherSuites = map[string]bool{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": true,
+	"ECDHE-RSA-AES128-GCM-SHA256":   true,
+	"ECDHE-ECDSA-CHACHA20-POLY1305": true,
+	"ECDHE-RSA-CHACHA20-POLY1305":   true,
+	"ECDHE-ECDSA-AES128-SHA":        true,
+	"ECDHE-RSA-AES128-SHA":          true,
+	"AES128-GCM-SHA256":             true,
+	"AES128-SHA":                    true,
+	"ECDHE-ECDSA-AES256-GCM-SHA384": true,
+	"ECDHE-RSA-AES256-GCM-SHA384":   true,
+	"ECDHE-ECDSA-AES256-SHA":        true,
+	"ECDHE-RSA-AES256-SHA":          true,
+	"AES256-GCM-SHA384":             true,
+	"AES256-SHA":                    true,
+}
+
+// ValidateCipherSuites returns an error if any of the supplied cipher
+// suites is not supported by Envoy. An entry may be a group of cipher
+// suites of equal preference, written as [A|B].
+func ValidateCipherSuites(ciphers []string) error {
+	for _, c := range ciphers {
+		names := []string{c}
+		if strings.HasPrefix(c, "[") && strings.HasSuffix(c, "]") {
+			names = strings.Split(c[1:len(c)-1], "|")
+		}
+		for _, name := range names {
+			if !cipherSuites[name] {
+				return fmt.Errorf("invalid cipher suite %q", c)
+			}
+		}
+	}
+	return nil
+}
+
 // maxConnections returns the value of the first matching max-connections
 // annotation for the following annotations:
 // 1. projectcontour.io/max-connections
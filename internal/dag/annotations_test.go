@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"testing"
 
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	"github.com/projectcontour/contour/internal/k8s"
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
@@ -461,3 +462,69 @@ func TestAnnotationKindValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxProtoVersion(t *testing.T) {
+	tests := map[string]struct {
+		version string
+		want    envoy_api_v2_auth.TlsParameters_TlsProtocol
+		wantErr bool
+	}{
+		"blank": {
+			version: "",
+			want:    envoy_api_v2_auth.TlsParameters_TLS_AUTO,
+		},
+		"1.2": {
+			version: "1.2",
+			want:    envoy_api_v2_auth.TlsParameters_TLSv1_2,
+		},
+		"1.3": {
+			version: "1.3",
+			want:    envoy_api_v2_auth.TlsParameters_TLSv1_3,
+		},
+		"invalid": {
+			version: "1.4",
+			want:    envoy_api_v2_auth.TlsParameters_TLS_AUTO,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := MaxProtoVersion(tc.version)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestValidateCipherSuites(t *testing.T) {
+	tests := map[string]struct {
+		ciphers []string
+		wantErr bool
+	}{
+		"empty": {
+			ciphers: nil,
+		},
+		"single cipher suites": {
+			ciphers: []string{"ECDHE-RSA-AES256-GCM-SHA384", "ECDHE-RSA-AES128-GCM-SHA256"},
+		},
+		"equal preference group": {
+			ciphers: []string{"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]"},
+		},
+		"unknown cipher suite": {
+			ciphers: []string{"ECDHE-RSA-AES256-GCM-SHA384", "RC4-MD5"},
+			wantErr: true,
+		},
+		"unknown cipher suite in group": {
+			ciphers: []string{"[ECDHE-RSA-AES128-GCM-SHA256|DES-CBC3-SHA]"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCipherSuites(tc.ciphers)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
@@ -19,11 +19,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	"github.com/google/go-cmp/cmp"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -41,6 +43,30 @@ type Builder struct {
 	// permitInsecure field in IngressRoute.
 	DisablePermitInsecure bool
 
+	// EnableGlobalRateLimit permits HTTPProxies to use global
+	// rate limiting. It is set when an external rate limit
+	// service is configured.
+	EnableGlobalRateLimit bool
+
+	// EnableLocalRateLimit permits HTTPProxies to use local
+	// rate limiting, which requires Envoy 1.16 or later.
+	EnableLocalRateLimit bool
+
+	// EnableUpstreamProxyProtocol permits HTTPProxy services to
+	// send PROXY protocol upstream, which requires Envoy 1.15
+	// or later.
+	EnableUpstreamProxyProtocol bool
+
+	// MinimumProtocolVersion is the minimum TLS protocol
+	// version set in the Contour configuration file, which
+	// applies to every secure virtual host.
+	MinimumProtocolVersion string
+
+	// DisableGzip indicates the gzip filter is removed from
+	// Envoy's HTTP connection managers, so there is no
+	// compression for a virtual host to turn off.
+	DisableGzip bool
+
 	services map[servicemeta]*Service
 	secrets  map[Meta]*Secret
 
@@ -67,6 +93,8 @@ func (b *Builder) Build() *DAG {
 
 	b.computeHTTPProxies()
 
+	b.computeGateways()
+
 	return b.buildDAG()
 }
 
@@ -227,39 +255,66 @@ func (b *Builder) validIngressRoutes() []*ingressroutev1.IngressRoute {
 // invalid HTTPProxy objects are excluded from the slice and their status
 // updated accordingly.
 func (b *Builder) validHTTPProxies() []*projcontour.HTTPProxy {
-	// ensure that a given fqdn is only referenced in a single HTTPProxy resource
-	var valid []*projcontour.HTTPProxy
+	// ensure that a given fqdn, or alias, is only referenced in a single HTTPProxy resource
 	fqdnHTTPProxies := make(map[string][]*projcontour.HTTPProxy)
 	for _, proxy := range b.Source.httpproxies {
 		if proxy.Spec.VirtualHost == nil {
-			valid = append(valid, proxy)
 			continue
 		}
-		fqdnHTTPProxies[proxy.Spec.VirtualHost.Fqdn] = append(fqdnHTTPProxies[proxy.Spec.VirtualHost.Fqdn], proxy)
+		for _, fqdn := range hostnames(proxy.Spec.VirtualHost) {
+			fqdnHTTPProxies[fqdn] = append(fqdnHTTPProxies[fqdn], proxy)
+		}
 	}
 
-	for fqdn, proxies := range fqdnHTTPProxies {
-		switch len(proxies) {
-		case 1:
-			valid = append(valid, proxies[0])
-		default:
-			// multiple irs use the same fqdn. mark them as invalid.
-			var conflicting []string
-			for _, proxy := range proxies {
-				conflicting = append(conflicting, proxy.Namespace+"/"+proxy.Name)
-			}
-			sort.Strings(conflicting) // sort for test stability
-			msg := fmt.Sprintf("fqdn %q is used in multiple HTTPProxies: %s", fqdn, strings.Join(conflicting, ", "))
-			for _, proxy := range proxies {
-				sw, commit := b.WithObject(proxy)
-				sw.WithValue("vhost", fqdn).SetInvalid(msg)
-				commit()
-			}
+	var fqdns []string
+	for fqdn := range fqdnHTTPProxies {
+		fqdns = append(fqdns, fqdn)
+	}
+	sort.Strings(fqdns) // sort for test stability
+
+	invalid := make(map[*projcontour.HTTPProxy]bool)
+	for _, fqdn := range fqdns {
+		proxies := fqdnHTTPProxies[fqdn]
+		if len(proxies) == 1 {
+			continue
+		}
+		// multiple irs use the same fqdn. mark them as invalid.
+		var conflicting []string
+		for _, proxy := range proxies {
+			conflicting = append(conflicting, proxy.Namespace+"/"+proxy.Name)
+		}
+		sort.Strings(conflicting) // sort for test stability
+		msg := fmt.Sprintf("fqdn %q is used in multiple HTTPProxies: %s", fqdn, strings.Join(conflicting, ", "))
+		for _, proxy := range proxies {
+			invalid[proxy] = true
+			sw, commit := b.WithObject(proxy)
+			sw.WithValue("vhost", proxy.Spec.VirtualHost.Fqdn).SetInvalid(msg)
+			commit()
+		}
+	}
+
+	var valid []*projcontour.HTTPProxy
+	for _, proxy := range b.Source.httpproxies {
+		if !invalid[proxy] {
+			valid = append(valid, proxy)
 		}
 	}
 	return valid
 }
 
+// hostnames returns the unique fqdn and aliases of the supplied virtual host.
+func hostnames(vh *projcontour.VirtualHost) []string {
+	seen := map[string]bool{vh.Fqdn: true}
+	names := []string{vh.Fqdn}
+	for _, alias := range vh.Aliases {
+		if !seen[alias] {
+			seen[alias] = true
+			names = append(names, alias)
+		}
+	}
+	return names
+}
+
 // computeSecureVirtualhosts populates tls parameters of
 // secure virtual hosts.
 func (b *Builder) computeSecureVirtualhosts() {
@@ -339,8 +394,9 @@ func (b *Builder) computeIngresses() {
 
 func (b *Builder) computeIngressRule(ing *v1beta1.Ingress, rule v1beta1.IngressRule) {
 	host := rule.Host
-	if strings.Contains(host, "*") {
-		// reject hosts with wildcard characters.
+	if strings.Contains(host, "*") && !isWildcard(host) {
+		// reject hosts with wildcard characters other than
+		// a leading *. label.
 		return
 	}
 	if host == "" {
@@ -357,6 +413,9 @@ func (b *Builder) computeIngressRule(ing *v1beta1.Ingress, rule v1beta1.IngressR
 		}
 
 		r := route(ing, path, s)
+		if s.Protocol == "tls" || s.Protocol == "h2" {
+			r.Clusters[0].ClientCertificate = b.globalClientCertificate()
+		}
 
 		// should we create port 80 routes for this ingress
 		if tlsRequired(ing) || httpAllowed(ing) {
@@ -466,17 +525,60 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 		return
 	}
 	sw = sw.WithValue("vhost", host)
-	if strings.Contains(host, "*") {
-		sw.SetInvalid("Spec.VirtualHost.Fqdn %q cannot use wildcards", host)
+	if strings.Contains(host, "*") && !isWildcard(host) {
+		sw.SetInvalid("Spec.VirtualHost.Fqdn %q can only use a wildcard as its leftmost label", host)
 		return
 	}
 
+	hosts := []string{host}
+	for _, alias := range proxy.Spec.VirtualHost.Aliases {
+		switch {
+		case isBlank(alias):
+			sw.SetInvalid("Spec.VirtualHost.Aliases must not contain blank entries")
+			return
+		case strings.Contains(alias, "*") && !isWildcard(alias):
+			sw.SetInvalid("Spec.VirtualHost.Aliases %q can only use a wildcard as its leftmost label", alias)
+			return
+		}
+		for _, h := range hosts {
+			if h == alias {
+				sw.SetInvalid("Spec.VirtualHost.Aliases %q is duplicated", alias)
+				return
+			}
+		}
+		hosts = append(hosts, alias)
+	}
+
+	// exact virtual hosts take precedence over wildcards, record
+	// those which this HTTPProxy will not receive requests for.
+	defer b.setWildcardPrecedence(sw, hosts)
+
 	var tlsValid bool
+	var fallback *Secret
 	if tls := proxy.Spec.VirtualHost.TLS; tls != nil {
 
 		// tls is valid if passthrough == true XOR secretName != ""
 		tlsValid = tls.Passthrough != !isBlank(tls.SecretName)
 
+		minVersion := MinProtoVersion(tls.MinimumProtocolVersion)
+		maxVersion, err := MaxProtoVersion(tls.MaximumProtocolVersion)
+		if err != nil {
+			sw.SetInvalid("Spec.VirtualHost.TLS is invalid: %s", err)
+			return
+		}
+		if maxVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO && maxVersion < minVersion {
+			sw.SetInvalid("Spec.VirtualHost.TLS maximumProtocolVersion %q is lower than the minimum protocol version", tls.MaximumProtocolVersion)
+			return
+		}
+		if maxVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO && maxVersion < MinProtoVersion(b.MinimumProtocolVersion) {
+			sw.SetInvalid("Spec.VirtualHost.TLS maximumProtocolVersion %q is lower than the minimum protocol version %q in the Contour configuration file", tls.MaximumProtocolVersion, b.MinimumProtocolVersion)
+			return
+		}
+		if err := ValidateCipherSuites(tls.CipherSuites); err != nil {
+			sw.SetInvalid("Spec.VirtualHost.TLS is invalid: %s", err)
+			return
+		}
+
 		// attach secrets to TLS enabled vhosts
 		m := splitSecret(tls.SecretName, proxy.Namespace)
 		sec := b.lookupSecret(m, validSecret)
@@ -485,15 +587,77 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 				sw.SetInvalid("%s: certificate delegation not permitted", tls.SecretName)
 				return
 			}
-			svhost := b.lookupSecureVirtualHost(host)
-			svhost.Secret = sec
-			svhost.MinProtoVersion = MinProtoVersion(proxy.Spec.VirtualHost.TLS.MinimumProtocolVersion)
+			// Fill in DownstreamValidation when external client validation is enabled.
+			var dv *PeerValidationContext
+			if tls.ClientValidation != nil {
+				var err error
+				dv, err = b.lookupDownstreamValidation(tls.ClientValidation, proxy.Namespace)
+				if err != nil {
+					sw.SetInvalid("Spec.VirtualHost.TLS client validation is invalid: %s", err)
+					return
+				}
+			}
+
+			for _, host := range hosts {
+				svhost := b.lookupSecureVirtualHost(host)
+				svhost.Secret = sec
+				svhost.MinProtoVersion = minVersion
+				svhost.MaxProtoVersion = maxVersion
+				svhost.CipherSuites = tls.CipherSuites
+				svhost.DownstreamValidation = dv
+			}
 		}
 
 		if sec == nil && !tls.Passthrough {
 			sw.SetInvalid("TLS Secret [%s] not found or is malformed", tls.SecretName)
 			return
 		}
+
+		if tls.Passthrough && tls.ClientValidation != nil {
+			sw.SetInvalid("Spec.VirtualHost.TLS passthrough cannot be combined with tls.clientValidation")
+			return
+		}
+
+		if tls.EnableFallbackCertificate {
+			// clients without SNI share a single filter chain, so
+			// per virtual host TLS settings cannot apply to them.
+			switch {
+			case tls.Passthrough || proxy.Spec.TCPProxy != nil:
+				sw.SetInvalid("Spec.VirtualHost.TLS enableFallbackCertificate requires TLS termination and cannot be combined with tcpproxy")
+				return
+			case tls.ClientValidation != nil:
+				sw.SetInvalid("Spec.VirtualHost.TLS enableFallbackCertificate cannot be combined with tls.clientValidation")
+				return
+			case proxy.Spec.VirtualHost.Authorization != nil:
+				sw.SetInvalid("Spec.VirtualHost.TLS enableFallbackCertificate cannot be combined with authorization")
+				return
+			case tls.MaximumProtocolVersion != "" || len(tls.CipherSuites) > 0:
+				sw.SetInvalid("Spec.VirtualHost.TLS enableFallbackCertificate cannot be combined with tls.maximumProtocolVersion or tls.cipherSuites")
+				return
+			case minVersion > MinProtoVersion(b.MinimumProtocolVersion):
+				sw.SetInvalid("Spec.VirtualHost.TLS enableFallbackCertificate cannot be combined with a tls.minimumProtocolVersion higher than the minimum protocol version in the Contour configuration file")
+				return
+			}
+
+			fc := b.Source.FallbackCertificate
+			if fc == nil {
+				sw.SetInvalid("Spec.VirtualHost.TLS enableFallbackCertificate requires a fallback certificate in the Contour configuration file")
+				return
+			}
+			fallback = b.lookupSecret(Meta{name: fc.Name, namespace: fc.Namespace}, validSecret)
+			if fallback == nil {
+				sw.SetInvalid("Spec.VirtualHost.TLS fallback certificate %s/%s not found or is malformed", fc.Namespace, fc.Name)
+				return
+			}
+		}
+	}
+
+	auth := proxy.Spec.VirtualHost.Authorization
+	if auth != nil {
+		if tls := proxy.Spec.VirtualHost.TLS; tls == nil || tls.Passthrough || proxy.Spec.TCPProxy != nil {
+			sw.SetInvalid("Spec.VirtualHost.Authorization requires TLS termination and cannot be combined with tcpproxy")
+			return
+		}
 	}
 
 	if proxy.Spec.TCPProxy != nil {
@@ -504,17 +668,94 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 		if !b.processHTTPProxyTCPProxy(sw, proxy, nil, host) {
 			return
 		}
+		// aliases share the tcpproxy of the fqdn.
+		for _, alias := range hosts[1:] {
+			b.lookupSecureVirtualHost(alias).TCPProxy = b.lookupSecureVirtualHost(host).TCPProxy
+		}
+	}
+
+	rlp, err := rateLimitPolicy(proxy.Spec.VirtualHost.RateLimitPolicy)
+	if err != nil {
+		sw.SetInvalid("Spec.VirtualHost.RateLimitPolicy is invalid: %s", err)
+		return
+	}
+	if rlp != nil && rlp.Global != nil && !b.EnableGlobalRateLimit {
+		sw.SetInvalid("Spec.VirtualHost.RateLimitPolicy.Global requires a rate limit service to be configured")
+		return
+	}
+	if rlp != nil && rlp.Local != nil && !b.EnableLocalRateLimit {
+		sw.SetInvalid("Spec.VirtualHost.RateLimitPolicy.Local requires local rate limiting to be enabled")
+		return
+	}
+
+	cp, err := corsPolicy(proxy.Spec.VirtualHost.CORSPolicy)
+	if err != nil {
+		sw.SetInvalid("Spec.VirtualHost.CORSPolicy is invalid: %s", err)
+		return
+	}
+
+	tp, err := tracingPolicy(proxy.Spec.VirtualHost.Tracing)
+	if err != nil {
+		sw.SetInvalid("Spec.VirtualHost.Tracing is invalid: %s", err)
+		return
+	}
+
+	var authService *Cluster
+	var authTimeout time.Duration
+	if auth != nil {
+		authService, err = b.lookupAuthorizationService(auth.ServiceRef, proxy.Namespace)
+		if err != nil {
+			sw.SetInvalid("Spec.VirtualHost.Authorization is invalid: %s", err)
+			return
+		}
+
+		if auth.ResponseTimeout != "" {
+			authTimeout, err = time.ParseDuration(auth.ResponseTimeout)
+			if err != nil {
+				sw.SetInvalid("Spec.VirtualHost.Authorization.ResponseTimeout is invalid: %s", err)
+				return
+			}
+		}
 	}
 
 	routes := b.computeRoutes(sw, proxy, nil, nil, tlsValid)
-	insecure := b.lookupVirtualHost(host)
-	addRoutes(insecure, routes)
 
-	// if TLS is enabled for this virtual host and there is no tcp proxy defined,
-	// then add routes to the secure virtualhost definition.
-	if tlsValid && proxy.Spec.TCPProxy == nil {
-		secure := b.lookupSecureVirtualHost(host)
-		addRoutes(secure, routes)
+	// Routes that permit insecure requests would bypass
+	// authorization, so they must explicitly disable it.
+	if auth != nil {
+		for _, r := range routes {
+			if !r.HTTPSUpgrade && !r.AuthDisabled {
+				sw.SetInvalid("Spec.VirtualHost.Authorization cannot be combined with permitInsecure routes unless route authPolicy is disabled")
+				return
+			}
+		}
+	}
+
+	disableCompression := !b.DisableGzip && proxy.Spec.VirtualHost.Compression != nil && proxy.Spec.VirtualHost.Compression.Disabled
+
+	// aliases share the routes and policies of the fqdn.
+	for _, host := range hosts {
+		insecure := b.lookupVirtualHost(host)
+		insecure.RateLimitPolicy = rlp
+		insecure.CORSPolicy = cp
+		insecure.TracingPolicy = tp
+		insecure.DisableCompression = disableCompression
+		addRoutes(insecure, routes)
+
+		// if TLS is enabled for this virtual host and there is no tcp proxy defined,
+		// then add routes to the secure virtualhost definition.
+		if tlsValid && proxy.Spec.TCPProxy == nil {
+			secure := b.lookupSecureVirtualHost(host)
+			secure.RateLimitPolicy = rlp
+			secure.CORSPolicy = cp
+			secure.TracingPolicy = tp
+			secure.DisableCompression = disableCompression
+			secure.FallbackCertificate = fallback
+			secure.AuthorizationService = authService
+			secure.AuthorizationResponseTimeout = authTimeout
+			secure.AuthorizationFailOpen = auth != nil && auth.FailOpen
+			addRoutes(secure, routes)
+		}
 	}
 }
 
@@ -554,6 +795,7 @@ func expandPrefixMatches(routes []*Route) []*Route {
 		// If there is no path prefix, we won't do any expansion, so skip it.
 		if !r.HasPathPrefix() {
 			expandedRoutes = append(expandedRoutes, r)
+			continue
 		}
 
 		routingPrefix := r.PathCondition.(*PrefixCondition).Prefix
@@ -620,6 +862,38 @@ func expandPrefixMatches(routes []*Route) []*Route {
 	return expandedRoutes
 }
 
+// lookupAuthorizationService returns a Cluster for the Service that
+// implements the external authorization gRPC protocol.
+func (b *Builder) lookupAuthorizationService(ref projcontour.ExtensionServiceReference, namespace string) (*Cluster, error) {
+	// The extension service must live in the namespace of the
+	// HTTPProxy, otherwise any proxy could send its requests to
+	// another team's service.
+	if ref.Namespace != "" && ref.Namespace != namespace {
+		return nil, fmt.Errorf("service %q: namespace %q must match the HTTPProxy namespace %q", ref.Name, ref.Namespace, namespace)
+	}
+	if ref.Port < 1 || ref.Port > 65535 {
+		return nil, fmt.Errorf("service %q: port must be in the range 1-65535", ref.Name)
+	}
+
+	s := b.lookupService(Meta{name: ref.Name, namespace: namespace}, intstr.FromInt(ref.Port))
+	if s == nil {
+		return nil, fmt.Errorf("service [%s/%s:%d] is invalid or missing", namespace, ref.Name, ref.Port)
+	}
+
+	// The authorization protocol is gRPC, so the cluster must speak
+	// HTTP/2, over TLS if the Service is annotated as such.
+	protocol := "h2c"
+	switch s.Protocol {
+	case "h2", "tls":
+		protocol = "h2"
+	}
+
+	return &Cluster{
+		Upstream: s,
+		Protocol: protocol,
+	}, nil
+}
+
 func getProtocol(service projcontour.Service, s *Service) (string, error) {
 	// Determine the protocol to use to speak to this Cluster.
 	var protocol string
@@ -677,7 +951,17 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 			return nil
 		}
 
-		if err := pathConditionsValid(include.Conditions); err != nil {
+		if err := includeConditionsValid(include.Conditions); err != nil {
+			sw.SetInvalid("include: %s", err)
+			return nil
+		}
+
+		if err := queryParamConditionsValid(include.Conditions); err != nil {
+			sw.SetInvalid("include: %s", err)
+			return nil
+		}
+
+		if err := headerConditionRegexesValid(include.Conditions); err != nil {
 			sw.SetInvalid("include: %s", err)
 			return nil
 		}
@@ -696,6 +980,16 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 			return nil
 		}
 
+		if err := queryParamConditionsValid(route.Conditions); err != nil {
+			sw.SetInvalid("route: %s", err)
+			return nil
+		}
+
+		if err := headerConditionRegexesValid(route.Conditions); err != nil {
+			sw.SetInvalid("route: %s", err)
+			return nil
+		}
+
 		conds := append(conditions, route.Conditions...)
 
 		// Look for duplicate exact match headers on this route
@@ -716,20 +1010,82 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 			return nil
 		}
 
-		if len(route.Services) < 1 {
+		if route.RequestRedirectPolicy != nil && len(route.Services) > 0 {
+			sw.SetInvalid("route.services and route.requestRedirectPolicy cannot both be specified")
+			return nil
+		}
+
+		if route.DirectResponsePolicy != nil && len(route.Services) > 0 {
+			sw.SetInvalid("route.services and route.directResponsePolicy cannot both be specified")
+			return nil
+		}
+
+		if route.DirectResponsePolicy != nil && route.RequestRedirectPolicy != nil {
+			sw.SetInvalid("route.requestRedirectPolicy and route.directResponsePolicy cannot both be specified")
+			return nil
+		}
+
+		if route.RequestRedirectPolicy == nil && route.DirectResponsePolicy == nil && len(route.Services) < 1 {
 			sw.SetInvalid("route.services must have at least one entry")
 			return nil
 		}
 
+		redirect, err := redirectPolicy(route.RequestRedirectPolicy)
+		if err != nil {
+			sw.SetInvalid("route.requestRedirectPolicy is invalid: %s", err)
+			return nil
+		}
+
+		directResponse, err := directResponsePolicy(route.DirectResponsePolicy)
+		if err != nil {
+			sw.SetInvalid("route.directResponsePolicy is invalid: %s", err)
+			return nil
+		}
+
+		rlp, err := rateLimitPolicy(route.RateLimitPolicy)
+		if err != nil {
+			sw.SetInvalid("route.rateLimitPolicy is invalid: %s", err)
+			return nil
+		}
+		if rlp != nil && rlp.Global != nil && !b.EnableGlobalRateLimit {
+			sw.SetInvalid("route.rateLimitPolicy.global requires a rate limit service to be configured")
+			return nil
+		}
+		if rlp != nil && rlp.Local != nil && !b.EnableLocalRateLimit {
+			sw.SetInvalid("route.rateLimitPolicy.local requires local rate limiting to be enabled")
+			return nil
+		}
+
+		rhp, err := requestHashPolicies(route.LoadBalancerPolicy)
+		if err != nil {
+			sw.SetInvalid("route.loadBalancerPolicy is invalid: %s", err)
+			return nil
+		}
+
 		r := &Route{
 			PathCondition:         mergePathConditions(conds),
 			HeaderConditions:      mergeHeaderConditions(conds),
+			QueryParamConditions:  mergeQueryParamConditions(conds),
 			Websocket:             route.EnableWebsockets,
 			HTTPSUpgrade:          routeEnforceTLS(enforceTLS, route.PermitInsecure && !b.DisablePermitInsecure),
 			TimeoutPolicy:         timeoutPolicy(route.TimeoutPolicy),
 			RetryPolicy:           retryPolicy(route.RetryPolicy),
 			RequestHeadersPolicy:  reqHP,
 			ResponseHeadersPolicy: respHP,
+			RateLimitPolicy:       rlp,
+			RequestHashPolicies:   rhp,
+			Redirect:              redirect,
+			DirectResponse:        directResponse,
+		}
+
+		if redirect != nil && redirect.PrefixRewrite != "" && !r.HasPathPrefix() {
+			sw.SetInvalid("route.requestRedirectPolicy is invalid: prefix requires a prefix condition")
+			return nil
+		}
+
+		if route.AuthPolicy != nil {
+			r.AuthDisabled = route.AuthPolicy.Disabled
+			r.AuthContext = route.AuthPolicy.Context
 		}
 
 		if len(route.GetPrefixReplacements()) > 0 {
@@ -789,7 +1145,7 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 				return nil
 			}
 
-			var uv *UpstreamValidation
+			var uv *PeerValidationContext
 			if protocol == "tls" {
 				// we can only validate TLS connections to services that talk TLS
 				uv, err = b.lookupUpstreamValidation(service.UpstreamValidation, proxy.Namespace)
@@ -800,6 +1156,19 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 				}
 			}
 
+			var cc *Secret
+			switch {
+			case protocol == "tls" || protocol == "h2":
+				cc, err = b.lookupClientCertificate(service.ClientCertificate, proxy.Namespace)
+				if err != nil {
+					sw.SetInvalid("Service [%s:%d] client certificate %s", service.Name, service.Port, err)
+					return nil
+				}
+			case service.ClientCertificate != "":
+				sw.SetInvalid("Service [%s:%d] client certificate requires the tls or h2 protocol", service.Name, service.Port)
+				return nil
+			}
+
 			reqHP, err := headersPolicy(service.RequestHeadersPolicy, true /* allow Host */)
 			if err != nil {
 				sw.SetInvalid(err.Error())
@@ -812,15 +1181,39 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 				return nil
 			}
 
+			od, err := outlierDetectionPolicy(service.OutlierDetection)
+			if err != nil {
+				sw.SetInvalid("Service [%s:%d] outlier detection policy error: %s",
+					service.Name, service.Port, err)
+				return nil
+			}
+
+			cb, err := circuitBreakerPolicy(s, service.CircuitBreakers)
+			if err != nil {
+				sw.SetInvalid("Service [%s:%d] circuit breaker policy error: %s",
+					service.Name, service.Port, err)
+				return nil
+			}
+
+			pp, err := b.upstreamProxyProtocol(service)
+			if err != nil {
+				sw.SetInvalid("Service [%s:%d] %s", service.Name, service.Port, err)
+				return nil
+			}
+
 			c := &Cluster{
-				Upstream:              s,
-				LoadBalancerPolicy:    loadBalancerPolicy(route.LoadBalancerPolicy),
-				Weight:                uint32(service.Weight),
-				HTTPHealthCheckPolicy: httpHealthCheckPolicy(route.HealthCheckPolicy),
-				UpstreamValidation:    uv,
-				RequestHeadersPolicy:  reqHP,
-				ResponseHeadersPolicy: respHP,
-				Protocol:              protocol,
+				Upstream:               s,
+				LoadBalancerPolicy:     loadBalancerPolicy(route.LoadBalancerPolicy),
+				Weight:                 uint32(service.Weight),
+				HTTPHealthCheckPolicy:  httpHealthCheckPolicy(route.HealthCheckPolicy),
+				OutlierDetectionPolicy: od,
+				CircuitBreakerPolicy:   cb,
+				UpstreamValidation:     uv,
+				ClientCertificate:      cc,
+				RequestHeadersPolicy:   reqHP,
+				ResponseHeadersPolicy:  respHP,
+				Protocol:               protocol,
+				ProxyProtocol:          pp,
 			}
 			if service.Mirror && r.MirrorPolicy != nil {
 				sw.SetInvalid("only one service per route may be nominated as mirror")
@@ -855,7 +1248,7 @@ func includeConditionsIdentical(includes []projcontour.Include) bool {
 		// Now compare each include's set of conditions
 		for _, cA := range includes[i].Conditions {
 			for _, cB := range includes[j].Conditions {
-				if (cA.Prefix == cB.Prefix) && cmp.Equal(cA.Header, cB.Header) {
+				if (cA.Prefix == cB.Prefix) && cmp.Equal(cA.Header, cB.Header) && cmp.Equal(cA.QueryParameter, cB.QueryParameter) {
 					return true
 				}
 			}
@@ -879,6 +1272,10 @@ func (b *Builder) buildDAG() *DAG {
 		dag.roots = append(dag.roots, https)
 	}
 
+	for _, l := range b.buildTCPListeners() {
+		dag.roots = append(dag.roots, l)
+	}
+
 	for meta := range b.orphaned {
 		ir, ok := b.Source.ingressroutes[meta]
 		if ok {
@@ -938,6 +1335,36 @@ func (b *Builder) buildHTTPSListener() *Listener {
 	}
 }
 
+// buildTCPListeners builds a *dag.Listener for each additional TCP listener
+// whose service could be found. Each listener forwards all connections to its
+// service without inspecting TLS or SNI.
+func (b *Builder) buildTCPListeners() []*Listener {
+	var listeners []*Listener
+	for _, l := range b.Source.TCPListeners {
+		m := Meta{name: l.Service.Name, namespace: l.Service.Namespace}
+		s := b.lookupService(m, intstr.FromInt(l.ServicePort))
+		if s == nil {
+			continue
+		}
+		c := &Cluster{
+			Upstream: s,
+			Protocol: s.Protocol,
+		}
+		if s.Protocol == "tls" || s.Protocol == "h2" {
+			c.ClientCertificate = b.globalClientCertificate()
+		}
+		listeners = append(listeners, &Listener{
+			Name:    l.Name,
+			Address: l.Address,
+			Port:    l.Port,
+			TCPProxy: &TCPProxy{
+				Clusters: []*Cluster{c},
+			},
+		})
+	}
+	return listeners
+}
+
 // setOrphaned records an IngressRoute/HTTPProxy resource as orphaned.
 func (b *Builder) setOrphaned(obj Object) {
 	m := Meta{
@@ -999,7 +1426,7 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 					return
 				}
 
-				var uv *UpstreamValidation
+				var uv *PeerValidationContext
 				var err error
 				if s.Protocol == "tls" {
 					// we can only validate TLS connections to services that talk TLS
@@ -1011,12 +1438,18 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 					}
 				}
 
+				var cc *Secret
+				if s.Protocol == "tls" || s.Protocol == "h2" {
+					cc = b.globalClientCertificate()
+				}
+
 				r.Clusters = append(r.Clusters, &Cluster{
 					Upstream:              s,
 					LoadBalancerPolicy:    service.Strategy,
 					Weight:                uint32(service.Weight),
 					HTTPHealthCheckPolicy: ingressrouteHealthCheckPolicy(service.HealthCheck),
 					UpstreamValidation:    uv,
+					ClientCertificate:     cc,
 					Protocol:              s.Protocol,
 				})
 			}
@@ -1070,7 +1503,7 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 	sw.SetValid()
 }
 
-func (b *Builder) lookupUpstreamValidation(uv *projcontour.UpstreamValidation, namespace string) (*UpstreamValidation, error) {
+func (b *Builder) lookupUpstreamValidation(uv *projcontour.UpstreamValidation, namespace string) (*PeerValidationContext, error) {
 	if uv == nil {
 		// no upstream validation requested, nothing to do
 		return nil, nil
@@ -1087,12 +1520,70 @@ func (b *Builder) lookupUpstreamValidation(uv *projcontour.UpstreamValidation, n
 		return nil, errors.New("missing subject alternative name")
 	}
 
-	return &UpstreamValidation{
+	return &PeerValidationContext{
 		CACertificate: cacert,
 		SubjectName:   uv.SubjectName,
 	}, nil
 }
 
+// lookupClientCertificate returns the client certificate Envoy presents
+// to a TLS upstream. A secret named by the service takes precedence over
+// the global client certificate. If neither is configured nil is returned.
+func (b *Builder) lookupClientCertificate(secretName, namespace string) (*Secret, error) {
+	if secretName == "" {
+		return b.globalClientCertificate(), nil
+	}
+
+	m := splitSecret(secretName, namespace)
+	sec := b.lookupSecret(m, validSecret)
+	if sec == nil {
+		return nil, fmt.Errorf("%q not found or misconfigured", secretName)
+	}
+	if !b.delegationPermitted(m, namespace) {
+		return nil, fmt.Errorf("%q: certificate delegation not permitted", secretName)
+	}
+	return sec, nil
+}
+
+// upstreamProxyProtocol returns the version of the PROXY protocol
+// header Envoy sends to the service, or an error if the service
+// asks for one and upstream PROXY protocol is not enabled.
+func (b *Builder) upstreamProxyProtocol(service projcontour.Service) (string, error) {
+	switch service.ProxyProtocol {
+	case "":
+		return "", nil
+	case "v1", "v2":
+		if !b.EnableUpstreamProxyProtocol {
+			return "", errors.New("proxyProtocol requires upstream PROXY protocol to be enabled")
+		}
+		return service.ProxyProtocol, nil
+	default:
+		return "", fmt.Errorf("proxyProtocol %q is invalid", service.ProxyProtocol)
+	}
+}
+
+// globalClientCertificate returns the client certificate configured for
+// all TLS upstreams, or nil if none is configured or the secret is missing.
+func (b *Builder) globalClientCertificate() *Secret {
+	cc := b.Source.ClientCertificate
+	if cc == nil {
+		return nil
+	}
+	return b.lookupSecret(Meta{name: cc.Name, namespace: cc.Namespace}, validSecret)
+}
+
+func (b *Builder) lookupDownstreamValidation(vc *projcontour.DownstreamValidation, namespace string) (*PeerValidationContext, error) {
+	cacert := b.lookupSecret(Meta{name: vc.CACertificate, namespace: namespace}, validCA)
+	if cacert == nil {
+		// CA cert is missing or not configured
+		return nil, errors.New("secret not found or misconfigured")
+	}
+
+	return &PeerValidationContext{
+		CACertificate: cacert,
+	}, nil
+}
+
 func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingressroutev1.IngressRoute, visited []*ingressroutev1.IngressRoute, host string) {
 	visited = append(visited, ir)
 
@@ -1186,7 +1677,15 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 	}
 
 	if len(tcpproxy.Services) > 0 {
-		var proxy TCPProxy
+		tp, err := tcpProxyTimeoutPolicy(tcpproxy.TimeoutPolicy)
+		if err != nil {
+			sw.SetInvalid("tcpproxy.timeoutPolicy is invalid: %s", err)
+			return false
+		}
+
+		proxy := TCPProxy{
+			TimeoutPolicy: tp,
+		}
 		for _, service := range httpproxy.Spec.TCPProxy.Services {
 			m := Meta{name: service.Name, namespace: httpproxy.Namespace}
 			s := b.lookupService(m, intstr.FromInt(service.Port))
@@ -1194,11 +1693,24 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 				sw.SetInvalid("tcpproxy: service %s/%s/%d: not found", httpproxy.Namespace, service.Name, service.Port)
 				return false
 			}
+			cb, err := circuitBreakerPolicy(s, service.CircuitBreakers)
+			if err != nil {
+				sw.SetInvalid("tcpproxy: service %s/%s/%d: circuit breaker policy error: %s", httpproxy.Namespace, service.Name, service.Port, err)
+				return false
+			}
+			pp, err := b.upstreamProxyProtocol(service)
+			if err != nil {
+				sw.SetInvalid("tcpproxy: service %s/%s/%d: %s", httpproxy.Namespace, service.Name, service.Port, err)
+				return false
+			}
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
 				Upstream:             s,
 				Protocol:             s.Protocol,
 				LoadBalancerPolicy:   loadBalancerPolicy(tcpproxy.LoadBalancerPolicy),
 				TCPHealthCheckPolicy: tcpHealthCheckPolicy(tcpproxy.HealthCheckPolicy),
+				CircuitBreakerPolicy: cb,
+				ConnectTimeout:       tp.GetConnectTimeout(),
+				ProxyProtocol:        pp,
 			})
 		}
 		b.lookupSecureVirtualHost(host).TCPProxy = &proxy
@@ -1286,6 +1798,71 @@ func route(ingress *v1beta1.Ingress, path string, service *Service) *Route {
 	return r
 }
 
+// setWildcardPrecedence appends to the status of a valid HTTPProxy
+// whose fqdn or aliases include a wildcard the exact hosts, other than
+// its own, which take precedence over it.
+func (b *Builder) setWildcardPrecedence(sw *ObjectStatusWriter, own []string) {
+	if sw.values["status"] != k8s.StatusValid {
+		return
+	}
+
+	var suffixes []string
+	for _, host := range own {
+		if isWildcard(host) {
+			suffixes = append(suffixes, host[1:])
+		}
+	}
+	if len(suffixes) == 0 {
+		return
+	}
+
+	hosts := make(map[string]bool)
+	for _, proxy := range b.Source.httpproxies {
+		if proxy.Spec.VirtualHost != nil {
+			for _, host := range hostnames(proxy.Spec.VirtualHost) {
+				hosts[host] = true
+			}
+		}
+	}
+	for _, ir := range b.Source.ingressroutes {
+		if ir.Spec.VirtualHost != nil {
+			hosts[ir.Spec.VirtualHost.Fqdn] = true
+		}
+	}
+	for _, ing := range b.Source.ingresses {
+		for _, rule := range ing.Spec.Rules {
+			hosts[rule.Host] = true
+		}
+	}
+	for _, host := range own {
+		delete(hosts, host)
+	}
+
+	var exact []string
+	for host := range hosts {
+		if strings.Contains(host, "*") {
+			continue
+		}
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(host, suffix) {
+				exact = append(exact, host)
+				break
+			}
+		}
+	}
+	if len(exact) == 0 {
+		return
+	}
+	sort.Strings(exact) // sort for test stability
+	sw.WithValue("description", fmt.Sprintf("%s; exact hosts take precedence: %s", sw.values["description"], strings.Join(exact, ", ")))
+}
+
+// isWildcard indicates if host is a wildcard hostname, such as *.example.com,
+// where the only wildcard is the leftmost label.
+func isWildcard(host string) bool {
+	return strings.HasPrefix(host, "*.") && len(host) > 2 && !strings.Contains(host[2:], "*")
+}
+
 // isBlank indicates if a string contains nothing but blank characters.
 func isBlank(s string) bool {
 	return len(strings.TrimSpace(s)) == 0
@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -1910,6 +1911,32 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// proxy1g tcp forwards traffic to default/kuard:8080 with a timeout policy.
+	proxy1g := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard-tcp",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+				TimeoutPolicy: &projcontour.TCPProxyTimeoutPolicy{
+					Idle:    "2h",
+					Connect: "1s",
+				},
+			},
+		},
+	}
+
 	proxy1b := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
@@ -2113,46 +2140,43 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
-	proxy2c := &projcontour.HTTPProxy{
+	// proxy2d includes proxy2e under a prefix, which in turn
+	// matches on exact and regex path conditions
+	proxy2d := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
-			Namespace: "default",
+			Namespace: "kubesystem",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
 				Fqdn: "example.com",
 			},
-			Routes: []projcontour.Route{{
+			Includes: []projcontour.Include{{
 				Conditions: []projcontour.Condition{{
-					Prefix: "/",
-				}},
-				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
-					Path: "/healthz",
-				},
-				Services: []projcontour.Service{{
-					Name: "kuard",
-					Port: 8080,
+					Prefix: "/api",
 				}},
+				Name:      "kuard",
+				Namespace: "default",
 			}},
 		},
 	}
-
-	// proxy6 has TLS and does not specify min tls version
-	proxy6 := &projcontour.HTTPProxy{
+	proxy2e := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "example-com",
+			Name:      "kuard",
 			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
-			VirtualHost: &projcontour.VirtualHost{
-				Fqdn: "foo.com",
-				TLS: &projcontour.TLS{
-					SecretName: sec1.Name,
-				},
-			},
 			Routes: []projcontour.Route{{
 				Conditions: []projcontour.Condition{{
-					Prefix: "/",
+					Exact: "/healthz",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Regex: "/v[12]/.*",
 				}},
 				Services: []projcontour.Service{{
 					Name: "kuard",
@@ -2162,44 +2186,41 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
-	proxy17 := &projcontour.HTTPProxy{
+	// proxy2f includes proxy2g for requests with a feature query
+	// parameter, proxy2g routes by the version query parameter
+	proxy2f := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
-			Namespace: "default",
+			Namespace: "kubesystem",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
 				Fqdn: "example.com",
 			},
-			Routes: []projcontour.Route{{
+			Includes: []projcontour.Include{{
 				Conditions: []projcontour.Condition{{
-					Prefix: "/",
-				}},
-				Services: []projcontour.Service{{
-					Name: "kuard",
-					Port: 8080,
-					UpstreamValidation: &projcontour.UpstreamValidation{
-						CACertificate: cert1.Name,
-						SubjectName:   "example.com",
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name:    "feature",
+						Present: true,
 					},
 				}},
+				Name:      "kuard",
+				Namespace: "default",
 			}},
 		},
 	}
-
-	// proxy10 has a websocket route
-	proxy10 := &projcontour.HTTPProxy{
+	proxy2g := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "example-com",
+			Name:      "kuard",
 			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
-			VirtualHost: &projcontour.VirtualHost{
-				Fqdn: "example.com",
-			},
 			Routes: []projcontour.Route{{
 				Conditions: []projcontour.Condition{{
-					Prefix: "/",
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name:  "version",
+						Exact: "v1",
+					},
 				}},
 				Services: []projcontour.Service{{
 					Name: "kuard",
@@ -2207,9 +2228,11 @@ func TestDAGInsert(t *testing.T) {
 				}},
 			}, {
 				Conditions: []projcontour.Condition{{
-					Prefix: "/websocket",
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name:  "version",
+						Exact: "v2",
+					},
 				}},
-				EnableWebsockets: true,
 				Services: []projcontour.Service{{
 					Name: "kuard",
 					Port: 8080,
@@ -2218,8 +2241,8 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
-	// proxy10b has a websocket route w/multiple upstreams
-	proxy10b := &projcontour.HTTPProxy{
+	// proxy2h routes canary traffic by cookie and user-agent
+	proxy2h := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
 			Namespace: "default",
@@ -2230,7 +2253,10 @@ func TestDAGInsert(t *testing.T) {
 			},
 			Routes: []projcontour.Route{{
 				Conditions: []projcontour.Condition{{
-					Prefix: "/",
+					Header: &projcontour.HeaderCondition{
+						Name:  "cookie",
+						Regex: ".*session=canary.*",
+					},
 				}},
 				Services: []projcontour.Service{{
 					Name: "kuard",
@@ -2238,9 +2264,22 @@ func TestDAGInsert(t *testing.T) {
 				}},
 			}, {
 				Conditions: []projcontour.Condition{{
-					Prefix: "/websocket",
+					Header: &projcontour.HeaderCondition{
+						Name:   "user-agent",
+						Prefix: "Canary/",
+					},
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Header: &projcontour.HeaderCondition{
+						Name:      "user-agent",
+						NotSuffix: "bot",
+					},
 				}},
-				EnableWebsockets: true,
 				Services: []projcontour.Service{{
 					Name: "kuard",
 					Port: 8080,
@@ -2249,10 +2288,49 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
-	proxy12 := &projcontour.HTTPProxy{
+	// proxy2i redirects all requests to another domain
+	proxy2i := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
-			Namespace: s1.Namespace,
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				RequestRedirectPolicy: &projcontour.HTTPRequestRedirectPolicy{
+					Hostname:   "example.org",
+					StatusCode: 301,
+				},
+			}},
+		},
+	}
+
+	// proxy2j returns a maintenance page for all requests
+	proxy2j := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				DirectResponsePolicy: &projcontour.HTTPDirectResponsePolicy{
+					StatusCode: 503,
+					Body:       "down for maintenance",
+				},
+			}},
+		},
+	}
+
+	// proxy2k presents a client certificate to a tls service
+	proxy2k := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
@@ -2263,21 +2341,19 @@ func TestDAGInsert(t *testing.T) {
 					Prefix: "/",
 				}},
 				Services: []projcontour.Service{{
-					Name: s1.Name,
-					Port: 8080,
-				}, {
-					Name:   s2.Name,
-					Port:   8080,
-					Mirror: true,
+					Name:              "kuard",
+					Port:              8080,
+					ClientCertificate: sec1.Name,
 				}},
 			}},
 		},
 	}
 
-	proxy13 := &projcontour.HTTPProxy{
+	// proxy2l routes to a tls service without a client certificate
+	proxy2l := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
-			Namespace: s1.Namespace,
+			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
@@ -2288,107 +2364,375 @@ func TestDAGInsert(t *testing.T) {
 					Prefix: "/",
 				}},
 				Services: []projcontour.Service{{
-					Name: s1.Name,
+					Name: "kuard",
 					Port: 8080,
-				}, {
-					Name:   s2.Name,
-					Port:   8080,
-					Mirror: true,
-				}, {
-					// it is legal to mention a service more that
-					// once, however it is not legal for more than one
-					// service to be marked as mirror.
-					Name:   s2.Name,
-					Port:   8080,
-					Mirror: true,
 				}},
 			}},
 		},
 	}
 
-	// invalid because tcpproxy both includes another and
-	// has a list of services.
-	proxy37 := &projcontour.HTTPProxy{
+	proxy2c := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "simple",
-			Namespace: "roots",
+			Name:      "example-com",
+			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
-				Fqdn: "passthrough.example.com",
-				TLS: &projcontour.TLS{
-					Passthrough: true,
-				},
+				Fqdn: "example.com",
 			},
-			TCPProxy: &projcontour.TCPProxy{
-				Include: &projcontour.TCPProxyInclude{
-					Name:      "foo",
-					Namespace: "roots",
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
+					Path: "/healthz",
 				},
 				Services: []projcontour.Service{{
-					Name: s1.Name,
+					Name: "kuard",
 					Port: 8080,
 				}},
-			},
+			}},
 		},
 	}
 
-	// Invalid because tcpproxy neither includes another httpproxy
-	// nor has a list of services.
-	proxy37a := &projcontour.HTTPProxy{
+	// proxy6 has TLS and does not specify min tls version
+	proxy6 := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "simple",
-			Namespace: "roots",
+			Name:      "example-com",
+			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
-				Fqdn: "passthrough.example.com",
+				Fqdn: "foo.com",
 				TLS: &projcontour.TLS{
-					Passthrough: true,
+					SecretName: sec1.Name,
 				},
 			},
-			TCPProxy: &projcontour.TCPProxy{},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	// proxy38 is invalid when combined with proxy39
-	// as the latter is a root.
-	proxy38 := &projcontour.HTTPProxy{
+	proxy17 := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "simple",
-			Namespace: "roots",
+			Name:      "example-com",
+			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
-				Fqdn: "passthrough.example.com",
-				TLS: &projcontour.TLS{
-					Passthrough: true,
-				},
-			},
-			TCPProxy: &projcontour.TCPProxy{
-				Include: &projcontour.TCPProxyInclude{
-					Name:      "foo",
-					Namespace: s1.Namespace,
-				},
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+					UpstreamValidation: &projcontour.UpstreamValidation{
+						CACertificate: cert1.Name,
+						SubjectName:   "example.com",
+					},
+				}},
+			}},
 		},
 	}
 
-	proxy39 := &projcontour.HTTPProxy{
+	// proxy18 requires client certificate validation
+	proxy18 := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "foo",
-			Namespace: s1.Namespace,
+			Name:      "example-com",
+			Namespace: "default",
 		},
 		Spec: projcontour.HTTPProxySpec{
 			VirtualHost: &projcontour.VirtualHost{
-				Fqdn: "www.example.com",
+				Fqdn: "example.com",
 				TLS: &projcontour.TLS{
-					Passthrough: true,
+					SecretName: sec1.Name,
+					ClientValidation: &projcontour.DownstreamValidation{
+						CACertificate: cert1.Name,
+					},
 				},
 			},
-			TCPProxy: &projcontour.TCPProxy{
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
 				Services: []projcontour.Service{{
-					Name: s1.Name,
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy18a requires authorization by an extension service
+	proxy18a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName: sec1.Name,
+				},
+				Authorization: &projcontour.AuthorizationServer{
+					ServiceRef: projcontour.ExtensionServiceReference{
+						Name: s2.Name,
+						Port: 8080,
+					},
+					ResponseTimeout: "1s",
+					FailOpen:        true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+				AuthPolicy: &projcontour.AuthorizationPolicy{
+					Context: map[string]string{
+						"team": "a",
+					},
+				},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Prefix: "/public",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+				PermitInsecure: true,
+				AuthPolicy: &projcontour.AuthorizationPolicy{
+					Disabled: true,
+				},
+			}},
+		},
+	}
+
+	// proxy18b requires authorization by an extension service in another namespace
+	proxy18b := proxy18a.DeepCopy()
+	proxy18b.Spec.VirtualHost.Authorization.ServiceRef = projcontour.ExtensionServiceReference{
+		Name:      s13.Name,
+		Namespace: s13.Namespace,
+		Port:      8080,
+	}
+
+	// proxy10 has a websocket route
+	proxy10 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Prefix: "/websocket",
+				}},
+				EnableWebsockets: true,
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy10b has a websocket route w/multiple upstreams
+	proxy10b := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Prefix: "/websocket",
+				}},
+				EnableWebsockets: true,
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy12 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}, {
+					Name:   s2.Name,
+					Port:   8080,
+					Mirror: true,
+				}},
+			}},
+		},
+	}
+
+	proxy13 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}, {
+					Name:   s2.Name,
+					Port:   8080,
+					Mirror: true,
+				}, {
+					// it is legal to mention a service more that
+					// once, however it is not legal for more than one
+					// service to be marked as mirror.
+					Name:   s2.Name,
+					Port:   8080,
+					Mirror: true,
+				}},
+			}},
+		},
+	}
+
+	// invalid because tcpproxy both includes another and
+	// has a list of services.
+	proxy37 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Include: &projcontour.TCPProxyInclude{
+					Name:      "foo",
+					Namespace: "roots",
+				},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// Invalid because tcpproxy neither includes another httpproxy
+	// nor has a list of services.
+	proxy37a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{},
+		},
+	}
+
+	// proxy38 is invalid when combined with proxy39
+	// as the latter is a root.
+	proxy38 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Include: &projcontour.TCPProxyInclude{
+					Name:      "foo",
+					Namespace: s1.Namespace,
+				},
+			},
+		},
+	}
+
+	proxy39 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "www.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
 					Port: 8080,
 				}},
 			},
@@ -3214,18 +3558,182 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
-	tests := map[string]struct {
-		objs                  []interface{}
-		disablePermitInsecure bool
-		want                  []Vertex
-	}{
-		"insert ingress w/ default backend w/o matching service": {
-			objs: []interface{}{
-				i1,
-			},
-			want: listeners(),
+	// proxy113 is like proxy6 but with a wildcard fqdn
+	proxy113 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wildcard-example-com",
+			Namespace: "default",
 		},
-		"insert ingress w/ default backend": {
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "*.example.com",
+				TLS: &projcontour.TLS{
+					SecretName: sec1.Name,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy114 is like proxy6 but with an alias
+	proxy114 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn:    "foo.com",
+				Aliases: []string{"www.foo.com"},
+				TLS: &projcontour.TLS{
+					SecretName: sec1.Name,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// fallbackSecret is the certificate served to clients without SNI
+	fallbackSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fallbacksecret",
+			Namespace: "admin",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	// proxy115 is like proxy6 but enables the fallback certificate
+	proxy115 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "foo.com",
+				TLS: &projcontour.TLS{
+					SecretName:                sec1.Name,
+					EnableFallbackCertificate: true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy116 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "foo.com",
+				TLS: &projcontour.TLS{
+					SecretName:             sec1.Name,
+					MinimumProtocolVersion: "1.2",
+					MaximumProtocolVersion: "1.2",
+					CipherSuites:           []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/",
+				}},
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy117 sends PROXY protocol to its tcpproxy service
+	proxy117 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "passthrough",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name:          s1.Name,
+					Port:          8080,
+					ProxyProtocol: "v2",
+				}},
+			},
+		},
+	}
+
+	// proxy118 turns off compression for its virtual host
+	proxy118 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				Compression: &projcontour.CompressionPolicy{
+					Disabled: true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs                        []interface{}
+		disablePermitInsecure       bool
+		enableUpstreamProxyProtocol bool
+		disableGzip                 bool
+		clientCertificate           *types.NamespacedName
+		fallbackCertificate         *types.NamespacedName
+		tcpListeners                []TCPListenerConfig
+		want                        []Vertex
+	}{
+		"insert ingress w/ default backend w/o matching service": {
+			objs: []interface{}{
+				i1,
+			},
+			want: listeners(),
+		},
+		"insert ingress w/ default backend": {
 			objs: []interface{}{
 				i1,
 				s1,
@@ -4309,7 +4817,7 @@ func TestDAGInsert(t *testing.T) {
 										Protocol:    "tls",
 									},
 									Protocol: "tls",
-									UpstreamValidation: &UpstreamValidation{
+									UpstreamValidation: &PeerValidationContext{
 										CACertificate: secret(cert1),
 										SubjectName:   "example.com",
 									},
@@ -4544,6 +5052,22 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert ingress with wildcard hostnames and services": {
+			objs: []interface{}{
+				s1,
+				s2,
+				i16,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("*", prefixroute("/", service(s1))),
+						virtualhost("*.example.com", prefixroute("/", service(s2))),
+					),
+				},
+			),
+		},
 		"insert ingress overlay": {
 			objs: []interface{}{
 				i13a, i13b, sec13, s13a, s13b,
@@ -4989,151 +5513,536 @@ func TestDAGInsert(t *testing.T) {
 						}},
 					},
 				},
-				s8,
+				s8,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("www.containersteve.com", prefixroute("/", service(s8))),
+					),
+				},
+			),
+		},
+		// issue 1399
+		"service shared across ingress and ingressroute tcpproxy": {
+			objs: []interface{}{
+				sec1,
+				s9,
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "nginx",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						TLS: []v1beta1.IngressTLS{{
+							Hosts:      []string{"example.com"},
+							SecretName: s1.Name,
+						}},
+						Rules: []v1beta1.IngressRule{{
+							Host:             "example.com",
+							IngressRuleValue: ingressrulevalue(backend(s9.Name, intstr.FromInt(80))),
+						}},
+					},
+				},
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "nginx",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "example.com",
+							TLS: &projcontour.TLS{
+								SecretName: sec1.Name,
+							},
+						},
+						TCPProxy: &ingressroutev1.TCPProxy{
+							Services: []ingressroutev1.Service{{
+								Name: s9.Name,
+								Port: 80,
+							}},
+						},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", prefixroute("/", service(s9))),
+					),
+				},
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "example.com",
+							},
+							MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_1,
+							Secret:          secret(sec1),
+							TCPProxy: &TCPProxy{
+								Clusters: clusters(service(s9)),
+							},
+						},
+					),
+				},
+			),
+		},
+		"insert httproxy": {
+			objs: []interface{}{
+				proxy1, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", prefixroute("/", service(s1))),
+					),
+				},
+			),
+		},
+		"insert httproxy w/o condition": {
+			objs: []interface{}{
+				proxy1b, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", prefixroute("/", service(s1))),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ conditions": {
+			objs: []interface{}{
+				proxy1c, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: &PrefixCondition{Prefix: "/kuard"},
+							HeaderConditions: []HeaderCondition{
+								{Name: "x-request-id", MatchType: "present"},
+								{Name: "e-tag", Value: "abcdef", MatchType: "contains"},
+								{Name: "x-timeout", Value: "infinity", MatchType: "contains", Invert: true},
+								{Name: "digest-auth", Value: "scott", MatchType: "exact"},
+								{Name: "digest-password", Value: "tiger", MatchType: "exact", Invert: true},
+							},
+							Clusters: clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ included conditions": {
+			objs: []interface{}{
+				proxy2a, proxy2b, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: &PrefixCondition{Prefix: "/kuard"},
+							HeaderConditions: []HeaderCondition{
+								{Name: "x-request-id", MatchType: "present"},
+								{Name: "x-timeout", Value: "infinity", MatchType: "contains", Invert: true},
+								{Name: "digest-auth", Value: "scott", MatchType: "exact"},
+								{Name: "e-tag", Value: "abcdef", MatchType: "contains"},
+								{Name: "digest-password", Value: "tiger", MatchType: "exact", Invert: true},
+							},
+							Clusters: clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ included exact and regex conditions": {
+			objs: []interface{}{
+				proxy2d, proxy2e, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: exact("/api/healthz"),
+							Clusters:      clusters(service(s1)),
+						}, &Route{
+							PathCondition: regex("/api/v[12]/.*"),
+							Clusters:      clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ included query parameter conditions": {
+			objs: []interface{}{
+				proxy2f, proxy2g, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: prefix("/"),
+							QueryParamConditions: []QueryParamCondition{
+								{Name: "feature", MatchType: "present"},
+								{Name: "version", Value: "v1", MatchType: "exact"},
+							},
+							Clusters: clusters(service(s1)),
+						}, &Route{
+							PathCondition: prefix("/"),
+							QueryParamConditions: []QueryParamCondition{
+								{Name: "feature", MatchType: "present"},
+								{Name: "version", Value: "v2", MatchType: "exact"},
+							},
+							Clusters: clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ regex, prefix and suffix header conditions": {
+			objs: []interface{}{
+				proxy2h, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: prefix("/"),
+							HeaderConditions: []HeaderCondition{
+								{Name: "cookie", Value: ".*session=canary.*", MatchType: "regex"},
+							},
+							Clusters: clusters(service(s1)),
+						}, &Route{
+							PathCondition: prefix("/"),
+							HeaderConditions: []HeaderCondition{
+								{Name: "user-agent", Value: "Canary/", MatchType: "prefix"},
+							},
+							Clusters: clusters(service(s1)),
+						}, &Route{
+							PathCondition: prefix("/"),
+							HeaderConditions: []HeaderCondition{
+								{Name: "user-agent", Value: "bot", MatchType: "suffix", Invert: true},
+							},
+							Clusters: clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ request redirect policy": {
+			objs: []interface{}{
+				proxy2i,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: prefix("/"),
+							Redirect: &Redirect{
+								Hostname:   "example.org",
+								StatusCode: 301,
+							},
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ direct response policy": {
+			objs: []interface{}{
+				proxy2j,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathCondition: prefix("/"),
+							DirectResponse: &DirectResponse{
+								StatusCode: 503,
+								Body:       "down for maintenance",
+							},
+						}),
+					),
+				},
+			),
+		},
+		"insert httpproxy with upstream client certificate": {
+			objs: []interface{}{
+				sec1, proxy2k, s1a,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com",
+							routeCluster("/",
+								&Cluster{
+									Upstream: &Service{
+										Name:        s1a.Name,
+										Namespace:   s1a.Namespace,
+										ServicePort: &s1a.Spec.Ports[0],
+										Protocol:    "tls",
+									},
+									Protocol:          "tls",
+									ClientCertificate: secret(sec1),
+								},
+							),
+						),
+					),
+				},
+			),
+		},
+		"insert httpproxy with global upstream client certificate": {
+			objs: []interface{}{
+				sec1, proxy2l, s1a,
+			},
+			clientCertificate: &types.NamespacedName{
+				Name:      sec1.Name,
+				Namespace: sec1.Namespace,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com",
+							routeCluster("/",
+								&Cluster{
+									Upstream: &Service{
+										Name:        s1a.Name,
+										Namespace:   s1a.Namespace,
+										ServicePort: &s1a.Spec.Ports[0],
+										Protocol:    "tls",
+									},
+									Protocol:          "tls",
+									ClientCertificate: secret(sec1),
+								},
+							),
+						),
+					),
+				},
+			),
+		},
+		"insert httpproxy with global client certificate and plaintext service": {
+			objs: []interface{}{
+				sec1, proxy2l, s1,
+			},
+			clientCertificate: &types.NamespacedName{
+				Name:      sec1.Name,
+				Namespace: sec1.Namespace,
 			},
 			want: listeners(
 				&Listener{
 					Port: 80,
 					VirtualHosts: virtualhosts(
-						virtualhost("www.containersteve.com", prefixroute("/", service(s8))),
+						virtualhost("example.com", prefixroute("/", service(s1))),
 					),
 				},
 			),
 		},
-		// issue 1399
-		"service shared across ingress and ingressroute tcpproxy": {
+		"insert tcp listener": {
 			objs: []interface{}{
-				sec1,
-				s9,
-				&v1beta1.Ingress{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "nginx",
-						Namespace: "default",
-					},
-					Spec: v1beta1.IngressSpec{
-						TLS: []v1beta1.IngressTLS{{
-							Hosts:      []string{"example.com"},
-							SecretName: s1.Name,
-						}},
-						Rules: []v1beta1.IngressRule{{
-							Host:             "example.com",
-							IngressRuleValue: ingressrulevalue(backend(s9.Name, intstr.FromInt(80))),
-						}},
+				s1,
+			},
+			tcpListeners: []TCPListenerConfig{{
+				Name:        "kuard",
+				Address:     "0.0.0.0",
+				Port:        9000,
+				Service:     types.NamespacedName{Name: s1.Name, Namespace: s1.Namespace},
+				ServicePort: 8080,
+			}},
+			want: listeners(
+				&Listener{
+					Name:    "kuard",
+					Address: "0.0.0.0",
+					Port:    9000,
+					TCPProxy: &TCPProxy{
+						Clusters: clustermap(s1),
 					},
 				},
-				&ingressroutev1.IngressRoute{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "nginx",
-						Namespace: "default",
-					},
-					Spec: ingressroutev1.IngressRouteSpec{
-						VirtualHost: &projcontour.VirtualHost{
-							Fqdn: "example.com",
-							TLS: &projcontour.TLS{
-								SecretName: sec1.Name,
-							},
-						},
-						TCPProxy: &ingressroutev1.TCPProxy{
-							Services: []ingressroutev1.Service{{
-								Name: s9.Name,
-								Port: 80,
-							}},
-						},
-					},
+			),
+		},
+		"insert tcp listener w/o matching service": {
+			tcpListeners: []TCPListenerConfig{{
+				Name:        "kuard",
+				Port:        9000,
+				Service:     types.NamespacedName{Name: s1.Name, Namespace: s1.Namespace},
+				ServicePort: 8080,
+			}},
+			want: listeners(),
+		},
+		"insert tcp listener w/ wrong service port": {
+			objs: []interface{}{
+				s1,
+			},
+			tcpListeners: []TCPListenerConfig{{
+				Name:        "kuard",
+				Port:        9000,
+				Service:     types.NamespacedName{Name: s1.Name, Namespace: s1.Namespace},
+				ServicePort: 9999,
+			}},
+			want: listeners(),
+		},
+		"insert httpproxy with wildcard fqdn and tls": {
+			objs: []interface{}{
+				proxy113, s1, sec1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("*.example.com", routeUpgrade("/", service(s1))),
+					),
+				}, &Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						securevirtualhost("*.example.com", sec1, routeUpgrade("/", service(s1))),
+					),
 				},
+			),
+		},
+		"insert httpproxy with alias and tls": {
+			objs: []interface{}{
+				proxy114, s1, sec1,
 			},
 			want: listeners(
 				&Listener{
 					Port: 80,
 					VirtualHosts: virtualhosts(
-						virtualhost("example.com", prefixroute("/", service(s9))),
+						virtualhost("foo.com", routeUpgrade("/", service(s1))),
+						virtualhost("www.foo.com", routeUpgrade("/", service(s1))),
+					),
+				}, &Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						securevirtualhost("foo.com", sec1, routeUpgrade("/", service(s1))),
+						securevirtualhost("www.foo.com", sec1, routeUpgrade("/", service(s1))),
 					),
 				},
+			),
+		},
+		"insert httpproxy with fallback certificate": {
+			objs: []interface{}{
+				proxy115, s1, sec1, fallbackSecret,
+			},
+			fallbackCertificate: &types.NamespacedName{
+				Name:      fallbackSecret.Name,
+				Namespace: fallbackSecret.Namespace,
+			},
+			want: listeners(
 				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("foo.com", routeUpgrade("/", service(s1))),
+					),
+				}, &Listener{
 					Port: 443,
 					VirtualHosts: virtualhosts(
 						&SecureVirtualHost{
 							VirtualHost: VirtualHost{
-								Name: "example.com",
-							},
-							MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_1,
-							Secret:          secret(sec1),
-							TCPProxy: &TCPProxy{
-								Clusters: clusters(service(s9)),
+								Name:   "foo.com",
+								routes: routes(routeUpgrade("/", service(s1))),
 							},
+							MinProtoVersion:     envoy_api_v2_auth.TlsParameters_TLSv1_1,
+							Secret:              secret(sec1),
+							FallbackCertificate: secret(fallbackSecret),
 						},
 					),
 				},
 			),
 		},
-		"insert httproxy": {
+		"insert httpproxy with fallback certificate missing": {
 			objs: []interface{}{
-				proxy1, s1,
+				proxy115, s1, sec1,
+			},
+			fallbackCertificate: &types.NamespacedName{
+				Name:      fallbackSecret.Name,
+				Namespace: fallbackSecret.Namespace,
+			},
+			want: listeners(),
+		},
+		"insert httpproxy with maximum protocol version and cipher suites": {
+			objs: []interface{}{
+				proxy116, s1, sec1,
 			},
 			want: listeners(
 				&Listener{
 					Port: 80,
 					VirtualHosts: virtualhosts(
-						virtualhost("example.com", prefixroute("/", service(s1))),
+						virtualhost("foo.com", routeUpgrade("/", service(s1))),
+					),
+				}, &Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:   "foo.com",
+								routes: routes(routeUpgrade("/", service(s1))),
+							},
+							MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+							MaxProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+							CipherSuites:    []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+							Secret:          secret(sec1),
+						},
 					),
 				},
 			),
 		},
-		"insert httproxy w/o condition": {
-			objs: []interface{}{
-				proxy1b, s1,
-			},
+		"insert httpproxy w/ tcpproxy w/ proxy protocol": {
+			objs:                        []interface{}{proxy117, s1},
+			enableUpstreamProxyProtocol: true,
 			want: listeners(
 				&Listener{
-					Port: 80,
+					Port: 443,
 					VirtualHosts: virtualhosts(
-						virtualhost("example.com", prefixroute("/", service(s1))),
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "passthrough.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: []*Cluster{{
+									Upstream:      service(s1),
+									ProxyProtocol: "v2",
+								}},
+							},
+						},
 					),
 				},
 			),
 		},
-		"insert httproxy w/ conditions": {
-			objs: []interface{}{
-				proxy1c, s1,
-			},
+		"insert httpproxy w/ compression disabled": {
+			objs: []interface{}{proxy118, s1},
 			want: listeners(
 				&Listener{
 					Port: 80,
 					VirtualHosts: virtualhosts(
-						virtualhost("example.com", &Route{
-							PathCondition: &PrefixCondition{Prefix: "/kuard"},
-							HeaderConditions: []HeaderCondition{
-								{Name: "x-request-id", MatchType: "present"},
-								{Name: "e-tag", Value: "abcdef", MatchType: "contains"},
-								{Name: "x-timeout", Value: "infinity", MatchType: "contains", Invert: true},
-								{Name: "digest-auth", Value: "scott", MatchType: "exact"},
-								{Name: "digest-password", Value: "tiger", MatchType: "exact", Invert: true},
-							},
-							Clusters: clusters(service(s1)),
-						}),
+						&VirtualHost{
+							Name:               "example.com",
+							DisableCompression: true,
+							routes:             routes(prefixroute("/", service(s1))),
+						},
 					),
 				},
 			),
 		},
-		"insert httproxy w/ included conditions": {
-			objs: []interface{}{
-				proxy2a, proxy2b, s1,
-			},
+		"insert httpproxy w/ compression disabled, gzip filter removed": {
+			objs:        []interface{}{proxy118, s1},
+			disableGzip: true,
 			want: listeners(
 				&Listener{
 					Port: 80,
 					VirtualHosts: virtualhosts(
-						virtualhost("example.com", &Route{
-							PathCondition: &PrefixCondition{Prefix: "/kuard"},
-							HeaderConditions: []HeaderCondition{
-								{Name: "x-request-id", MatchType: "present"},
-								{Name: "x-timeout", Value: "infinity", MatchType: "contains", Invert: true},
-								{Name: "digest-auth", Value: "scott", MatchType: "exact"},
-								{Name: "e-tag", Value: "abcdef", MatchType: "contains"},
-								{Name: "digest-password", Value: "tiger", MatchType: "exact", Invert: true},
-							},
-							Clusters: clusters(service(s1)),
-						}),
+						virtualhost("example.com", prefixroute("/", service(s1))),
 					),
 				},
 			),
@@ -5277,7 +6186,7 @@ func TestDAGInsert(t *testing.T) {
 										Protocol:    "tls",
 									},
 									Protocol: "tls",
-									UpstreamValidation: &UpstreamValidation{
+									UpstreamValidation: &PeerValidationContext{
 										CACertificate: secret(cert1),
 										SubjectName:   "example.com",
 									},
@@ -5288,6 +6197,109 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httpproxy with downstream verification": {
+			objs: []interface{}{
+				cert1, proxy18, s1, sec1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", routeUpgrade("/", service(s1))),
+					),
+				},
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:   "example.com",
+								routes: routes(routeUpgrade("/", service(s1))),
+							},
+							MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_1,
+							Secret:          secret(sec1),
+							DownstreamValidation: &PeerValidationContext{
+								CACertificate: secret(cert1),
+							},
+						},
+					),
+				},
+			),
+		},
+		"insert httpproxy with authorization": {
+			objs: []interface{}{
+				proxy18a, s1, s2, sec1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com",
+							&Route{
+								PathCondition: prefix("/"),
+								Clusters:      clustermap(s1),
+								HTTPSUpgrade:  true,
+								AuthContext:   map[string]string{"team": "a"},
+							},
+							&Route{
+								PathCondition: prefix("/public"),
+								Clusters:      clustermap(s1),
+								AuthDisabled:  true,
+							},
+						),
+					),
+				},
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "example.com",
+								routes: routes(
+									&Route{
+										PathCondition: prefix("/"),
+										Clusters:      clustermap(s1),
+										HTTPSUpgrade:  true,
+										AuthContext:   map[string]string{"team": "a"},
+									},
+									&Route{
+										PathCondition: prefix("/public"),
+										Clusters:      clustermap(s1),
+										AuthDisabled:  true,
+									},
+								),
+							},
+							MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_1,
+							Secret:          secret(sec1),
+							AuthorizationService: &Cluster{
+								Upstream: service(s2),
+								Protocol: "h2c",
+							},
+							AuthorizationResponseTimeout: time.Second,
+							AuthorizationFailOpen:        true,
+						},
+					),
+				},
+			),
+		},
+		"insert httpproxy with authorization, missing extension service": {
+			objs: []interface{}{
+				proxy18a, s1, sec1,
+			},
+			want: listeners(),
+		},
+		"insert httpproxy with authorization, extension service in another namespace": {
+			objs: []interface{}{
+				proxy18b, s1, s13, sec1,
+			},
+			want: listeners(),
+		},
+		"insert httpproxy with downstream verification, missing ca secret": {
+			objs: []interface{}{
+				proxy18, s1, sec1,
+			},
+			want: listeners(),
+		},
 		"insert httpproxy with invalid tcpproxy": {
 			objs: []interface{}{proxy37, s1},
 			want: listeners(),
@@ -5702,6 +6714,33 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert proxy with tcp forward with timeout policy": {
+			objs: []interface{}{
+				proxy1g, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "kuard.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: []*Cluster{{
+									Upstream:       service(s1),
+									ConnectTimeout: time.Second,
+								}},
+								TimeoutPolicy: &TCPProxyTimeoutPolicy{
+									IdleTimeout:    2 * time.Hour,
+									ConnectTimeout: time.Second,
+								},
+							},
+						},
+					),
+				},
+			),
+		},
 		// issue 1952
 		"insert proxy with tcp forward without TLS termination w/ passthrough and 301 upgrade of port 80": {
 			objs: []interface{}{
@@ -6142,9 +7181,14 @@ func TestDAGInsert(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			builder := Builder{
-				DisablePermitInsecure: tc.disablePermitInsecure,
+				DisablePermitInsecure:       tc.disablePermitInsecure,
+				EnableUpstreamProxyProtocol: tc.enableUpstreamProxyProtocol,
+				DisableGzip:                 tc.disableGzip,
 				Source: KubernetesCache{
-					FieldLogger: testLogger(t),
+					ClientCertificate:   tc.clientCertificate,
+					FallbackCertificate: tc.fallbackCertificate,
+					TCPListeners:        tc.tcpListeners,
+					FieldLogger:         testLogger(t),
 				},
 			}
 			for _, o := range tc.objs {
@@ -6958,6 +8002,7 @@ func listeners(ls ...*Listener) []Vertex {
 
 func prefix(prefix string) Condition { return &PrefixCondition{Prefix: prefix} }
 func regex(regex string) Condition   { return &RegexCondition{Regex: regex} }
+func exact(path string) Condition    { return &ExactCondition{Path: path} }
 
 func withMirror(r *Route, mirror *Service) *Route {
 	r.MirrorPolicy = &MirrorPolicy{
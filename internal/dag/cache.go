@@ -18,6 +18,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
@@ -40,6 +41,20 @@ type KubernetesCache struct {
 	// If not set, defaults to DEFAULT_INGRESS_CLASS.
 	IngressClass string
 
+	// ClientCertificate is the optional identifier of the TLS secret
+	// containing the client certificate and private key Envoy presents
+	// to upstreams that speak TLS, unless overridden per service.
+	ClientCertificate *types.NamespacedName
+
+	// FallbackCertificate is the optional identifier of the TLS secret
+	// Envoy serves to clients which do not send SNI, for HTTPProxies
+	// which enable it.
+	FallbackCertificate *types.NamespacedName
+
+	// TCPListeners describes additional listeners which forward
+	// plain TCP connections directly to a Kubernetes service.
+	TCPListeners []TCPListenerConfig
+
 	ingresses            map[Meta]*v1beta1.Ingress
 	ingressroutes        map[Meta]*ingressroutev1.IngressRoute
 	httpproxies          map[Meta]*projectcontour.HTTPProxy
@@ -197,9 +212,6 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		if kc.gatewayclasses == nil {
 			kc.gatewayclasses = make(map[Meta]*serviceapis.GatewayClass)
 		}
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being added to the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Adding GatewayClass")
 		kc.gatewayclasses[m] = obj
 		return true
 	case *serviceapis.Gateway:
@@ -207,9 +219,6 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		if kc.gateways == nil {
 			kc.gateways = make(map[Meta]*serviceapis.Gateway)
 		}
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being added to the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Adding Gateway")
 		kc.gateways[m] = obj
 		return true
 	case *serviceapis.HTTPRoute:
@@ -217,9 +226,6 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		if kc.httproutes == nil {
 			kc.httproutes = make(map[Meta]*serviceapis.HTTPRoute)
 		}
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being added to the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Adding HTTPRoute")
 		kc.httproutes[m] = obj
 		return true
 	case *serviceapis.TcpRoute:
@@ -227,9 +233,6 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		if kc.tcproutes == nil {
 			kc.tcproutes = make(map[Meta]*serviceapis.TcpRoute)
 		}
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being added to the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Adding TcpRoute")
 		kc.tcproutes[m] = obj
 		return true
 
@@ -293,33 +296,21 @@ func (kc *KubernetesCache) remove(obj interface{}) bool {
 	case *serviceapis.GatewayClass:
 		m := toMeta(obj)
 		_, ok := kc.gatewayclasses[m]
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being removed from the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Removing GatewayClass")
 		delete(kc.gatewayclasses, m)
 		return ok
 	case *serviceapis.Gateway:
 		m := toMeta(obj)
 		_, ok := kc.gateways[m]
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being removed from the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Removing Gateway")
 		delete(kc.gateways, m)
 		return ok
 	case *serviceapis.HTTPRoute:
 		m := toMeta(obj)
 		_, ok := kc.httproutes[m]
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being removed from the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Removing HTTPRoute")
 		delete(kc.httproutes, m)
 		return ok
 	case *serviceapis.TcpRoute:
 		m := toMeta(obj)
 		_, ok := kc.tcproutes[m]
-		// TODO(youngnick): Remove this once service-apis actually have behavior
-		// other than being removed from the cache.
-		kc.WithField("experimental", "service-apis").WithField("name", m.name).WithField("namespace", m.namespace).Debug("Removing TcpRoute")
 		delete(kc.tcproutes, m)
 		return ok
 
@@ -380,6 +371,12 @@ func (kc *KubernetesCache) serviceTriggersRebuild(service *v1.Service) bool {
 		if ir.Namespace != service.Namespace {
 			continue
 		}
+		if vhost := ir.Spec.VirtualHost; vhost != nil && vhost.Authorization != nil {
+			ref := vhost.Authorization.ServiceRef
+			if ref.Name == service.Name && stringOrDefault(ref.Namespace, ir.Namespace) == service.Namespace {
+				return true
+			}
+		}
 		for _, route := range ir.Spec.Routes {
 			for _, s := range route.Services {
 				if s.Name == service.Name {
@@ -396,6 +393,32 @@ func (kc *KubernetesCache) serviceTriggersRebuild(service *v1.Service) bool {
 		}
 	}
 
+	for _, route := range kc.httproutes {
+		if route.Namespace != service.Namespace {
+			continue
+		}
+		hosts := append([]serviceapis.HTTPRouteHost{}, route.Spec.Hosts...)
+		if route.Spec.Default != nil {
+			hosts = append(hosts, *route.Spec.Default)
+		}
+		for _, host := range hosts {
+			for _, rule := range host.Rules {
+				if rule.Action == nil || rule.Action.ForwardTo == nil {
+					continue
+				}
+				if fwd := rule.Action.ForwardTo; fwd.Kind == "Service" && fwd.Name == service.Name {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, l := range kc.TCPListeners {
+		if l.Service.Namespace == service.Namespace && l.Service.Name == service.Name {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -412,6 +435,18 @@ func (kc *KubernetesCache) secretTriggersRebuild(secret *v1.Secret) bool {
 		return true
 	}
 
+	if cc := kc.ClientCertificate; cc != nil {
+		if cc.Namespace == secret.Namespace && cc.Name == secret.Name {
+			return true
+		}
+	}
+
+	if fc := kc.FallbackCertificate; fc != nil {
+		if fc.Namespace == secret.Namespace && fc.Name == secret.Name {
+			return true
+		}
+	}
+
 	delegations := make(map[string]bool) // targetnamespace/secretname to bool
 
 	// merge ingressroute.TLSCertificateDelegation and projectcontour.TLSCertificateDelegation.
@@ -509,5 +544,34 @@ func (kc *KubernetesCache) secretTriggersRebuild(secret *v1.Secret) bool {
 		}
 	}
 
+	for _, proxy := range kc.httpproxies {
+		for _, route := range proxy.Spec.Routes {
+			for _, service := range route.Services {
+				if service.ClientCertificate == "" {
+					continue
+				}
+				if splitSecret(service.ClientCertificate, proxy.Namespace) == toMeta(secret) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, gw := range kc.gateways {
+		if gw.Namespace != secret.Namespace {
+			continue
+		}
+		for _, l := range gw.Spec.Listeners {
+			if l.TLS == nil {
+				continue
+			}
+			for _, cert := range l.TLS.Certificates {
+				if cert.Kind == "Secret" && cert.Name == secret.Name {
+					return true
+				}
+			}
+		}
+	}
+
 	return false
 }
@@ -22,14 +22,18 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
 )
 
 func TestKubernetesCacheInsert(t *testing.T) {
 	tests := map[string]struct {
-		pre  []interface{}
-		obj  interface{}
-		want bool
+		pre                 []interface{}
+		clientCertificate   *types.NamespacedName
+		fallbackCertificate *types.NamespacedName
+		tcpListeners        []TCPListenerConfig
+		obj                 interface{}
+		want                bool
 	}{
 		"insert secret": {
 			obj: &v1.Secret{
@@ -453,6 +457,64 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert secret referenced by httpproxy service client certificate": {
+			pre: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name:              "backend",
+								Port:              443,
+								ClientCertificate: "clientcert",
+							}},
+						}},
+					},
+				},
+			},
+			obj: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "clientcert",
+					Namespace: "default",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+			},
+			want: true,
+		},
+		"insert secret referenced as global client certificate": {
+			clientCertificate: &types.NamespacedName{
+				Name:      "envoy-client",
+				Namespace: "projectcontour",
+			},
+			obj: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "envoy-client",
+					Namespace: "projectcontour",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+			},
+			want: true,
+		},
+		"insert secret referenced as fallback certificate": {
+			fallbackCertificate: &types.NamespacedName{
+				Name:      "fallback",
+				Namespace: "projectcontour",
+			},
+			obj: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fallback",
+					Namespace: "projectcontour",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+			},
+			want: true,
+		},
 		"insert certificate secret": {
 			obj: &v1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
@@ -897,6 +959,93 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert service referenced by httpproxy authorization": {
+			pre: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							Authorization: &projcontour.AuthorizationServer{
+								ServiceRef: projcontour.ExtensionServiceReference{
+									Name: "auth",
+									Port: 9000,
+								},
+							},
+						},
+					},
+				},
+			},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "auth",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"insert service referenced by httpproxy authorization in different namespace": {
+			pre: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+							Authorization: &projcontour.AuthorizationServer{
+								ServiceRef: projcontour.ExtensionServiceReference{
+									Name:      "auth",
+									Namespace: "auth",
+									Port:      9000,
+								},
+							},
+						},
+					},
+				},
+			},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "auth",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
+		"insert service referenced by tcp listener": {
+			tcpListeners: []TCPListenerConfig{{
+				Name:        "postgres",
+				Port:        5432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "default"},
+				ServicePort: 5432,
+			}},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "postgres",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"insert service referenced by tcp listener in different namespace": {
+			tcpListeners: []TCPListenerConfig{{
+				Name:        "postgres",
+				Port:        5432,
+				Service:     types.NamespacedName{Name: "postgres", Namespace: "database"},
+				ServicePort: 5432,
+			}},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "postgres",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
 		"insert service-apis Gatewayclass": {
 			obj: &serviceapis.GatewayClass{
 				ObjectMeta: metav1.ObjectMeta{
@@ -938,7 +1087,10 @@ func TestKubernetesCacheInsert(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			cache := KubernetesCache{
-				FieldLogger: testLogger(t),
+				ClientCertificate:   tc.clientCertificate,
+				FallbackCertificate: tc.fallbackCertificate,
+				TCPListeners:        tc.tcpListeners,
+				FieldLogger:         testLogger(t),
 			}
 			for _, p := range tc.pre {
 				cache.Insert(p)
@@ -17,15 +17,47 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"regexp/syntax"
 	"strings"
 
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 )
 
-// mergePathConditions merges the given slice of prefix Conditions into a single
-// prefix Condition.
-// pathConditionsValid guarantees that if a prefix is present, it will start with a
-// / character, so we can simply concatenate.
+// MaxRegexProgramSize is the default value for the Envoy regex max
+// program size tunable. There's no way to really know what a good
+// value for this is, except that the RE2 maintainer thinks that 100
+// is low. As a rule of thumb, each '.*' expression costs about 15
+// units of program size. AFAIK, there's no obvious correlation
+// between regex size and execution time.
+//
+// See also https://github.com/envoyproxy/envoy/pull/9171#discussion_r351974033
+// and https://github.com/projectcontour/contour/issues/2240
+const MaxRegexProgramSize = 1 << 20
+
+// validRegex checks that re compiles and that its program fits
+// within MaxRegexProgramSize. Go's regexp package accepts the same
+// syntax as Envoy's RE2 engine, but counts program size differently,
+// so a regex close to the limit may still be rejected by Envoy.
+func validRegex(re string) error {
+	parsed, err := syntax.Parse(re, syntax.Perl)
+	if err != nil {
+		return err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return err
+	}
+	if len(prog.Inst) > MaxRegexProgramSize {
+		return fmt.Errorf("program size %d exceeds the maximum of %d", len(prog.Inst), MaxRegexProgramSize)
+	}
+	return nil
+}
+
+// mergePathConditions merges the given slice of path Conditions into a single
+// path Condition.
+// pathConditionsValid guarantees that if a prefix or exact path is present, it
+// will start with a / character, so we can simply concatenate. Any prefixes are
+// prepended to an exact or regex condition, if one is present.
 func mergePathConditions(conds []projcontour.Condition) Condition {
 	prefix := ""
 	for _, cond := range conds {
@@ -35,6 +67,24 @@ func mergePathConditions(conds []projcontour.Condition) Condition {
 	re := regexp.MustCompile(`//+`)
 	prefix = re.ReplaceAllString(prefix, `/`)
 
+	for _, cond := range conds {
+		switch {
+		case cond.Exact != "":
+			return &ExactCondition{
+				Path: re.ReplaceAllString(prefix+cond.Exact, `/`),
+			}
+		case cond.Regex != "":
+			// Avoid a doubled slash when the regex begins
+			// where the prefix ends.
+			if strings.HasSuffix(prefix, "/") && strings.HasPrefix(cond.Regex, "/") {
+				prefix = strings.TrimSuffix(prefix, "/")
+			}
+			return &RegexCondition{
+				Regex: regexp.QuoteMeta(prefix) + cond.Regex,
+			}
+		}
+	}
+
 	// After the merge operation is done, if the string is still empty, then
 	// we need to set the prefix to /.
 	// Remember that this step is done AFTER all the includes have happened.
@@ -50,25 +100,61 @@ func mergePathConditions(conds []projcontour.Condition) Condition {
 }
 
 // pathConditionsValid validates a slice of Conditions can be correctly merged.
-// It encodes the business rules about what is allowed for prefix Conditions.
+// It encodes the business rules about what is allowed for path Conditions.
 func pathConditionsValid(conds []projcontour.Condition) error {
 	prefixCount := 0
+	pathCount := 0
 
 	for _, cond := range conds {
+		set := 0
 		if cond.Prefix != "" {
+			set++
 			prefixCount++
 			if cond.Prefix[0] != '/' {
 				return fmt.Errorf("prefix conditions must start with /, %s was supplied", cond.Prefix)
 			}
 		}
+		if cond.Exact != "" {
+			set++
+			if cond.Exact[0] != '/' {
+				return fmt.Errorf("exact conditions must start with /, %s was supplied", cond.Exact)
+			}
+		}
+		if cond.Regex != "" {
+			set++
+			if err := validRegex(cond.Regex); err != nil {
+				return fmt.Errorf("regex condition %q is not a valid regular expression: %v", cond.Regex, err)
+			}
+		}
+		if set > 1 {
+			return errors.New("a condition may only set one of prefix, exact or regex")
+		}
+		pathCount += set
+
 		if prefixCount > 1 {
 			return errors.New("more than one prefix is not allowed in a condition block")
 		}
+		if pathCount > 1 {
+			return errors.New("more than one path condition is not allowed in a condition block")
+		}
 	}
 
 	return nil
 }
 
+// includeConditionsValid validates the Conditions of an include. As
+// included routes may add their own path conditions, includes may
+// only match on a path prefix.
+func includeConditionsValid(conds []projcontour.Condition) error {
+	for _, cond := range conds {
+		if cond.Exact != "" || cond.Regex != "" {
+			return errors.New("exact and regex conditions are not allowed on includes")
+		}
+	}
+
+	return pathConditionsValid(conds)
+}
+
 func mergeHeaderConditions(conds []projcontour.Condition) []HeaderCondition {
 	var hc []HeaderCondition
 	for _, cond := range conds {
@@ -106,11 +192,141 @@ func mergeHeaderConditions(conds []projcontour.Condition) []HeaderCondition {
 				MatchType: "exact",
 				Invert:    true,
 			})
+		case cond.Header.Prefix != "":
+			hc = append(hc, HeaderCondition{
+				Name:      cond.Header.Name,
+				Value:     cond.Header.Prefix,
+				MatchType: "prefix",
+			})
+		case cond.Header.NotPrefix != "":
+			hc = append(hc, HeaderCondition{
+				Name:      cond.Header.Name,
+				Value:     cond.Header.NotPrefix,
+				MatchType: "prefix",
+				Invert:    true,
+			})
+		case cond.Header.Suffix != "":
+			hc = append(hc, HeaderCondition{
+				Name:      cond.Header.Name,
+				Value:     cond.Header.Suffix,
+				MatchType: "suffix",
+			})
+		case cond.Header.NotSuffix != "":
+			hc = append(hc, HeaderCondition{
+				Name:      cond.Header.Name,
+				Value:     cond.Header.NotSuffix,
+				MatchType: "suffix",
+				Invert:    true,
+			})
+		case cond.Header.Regex != "":
+			hc = append(hc, HeaderCondition{
+				Name:      cond.Header.Name,
+				Value:     cond.Header.Regex,
+				MatchType: "regex",
+			})
+		case cond.Header.NotRegex != "":
+			hc = append(hc, HeaderCondition{
+				Name:      cond.Header.Name,
+				Value:     cond.Header.NotRegex,
+				MatchType: "regex",
+				Invert:    true,
+			})
 		}
 	}
 	return hc
 }
 
+func mergeQueryParamConditions(conds []projcontour.Condition) []QueryParamCondition {
+	var qc []QueryParamCondition
+	for _, cond := range conds {
+		switch {
+		case cond.QueryParameter == nil:
+			// skip it
+		case cond.QueryParameter.Present:
+			qc = append(qc, QueryParamCondition{
+				Name:      cond.QueryParameter.Name,
+				MatchType: "present",
+			})
+		case cond.QueryParameter.Exact != "":
+			qc = append(qc, QueryParamCondition{
+				Name:      cond.QueryParameter.Name,
+				Value:     cond.QueryParameter.Exact,
+				MatchType: "exact",
+			})
+		case cond.QueryParameter.Prefix != "":
+			qc = append(qc, QueryParamCondition{
+				Name:      cond.QueryParameter.Name,
+				Value:     cond.QueryParameter.Prefix,
+				MatchType: "prefix",
+			})
+		case cond.QueryParameter.Regex != "":
+			qc = append(qc, QueryParamCondition{
+				Name:      cond.QueryParameter.Name,
+				Value:     cond.QueryParameter.Regex,
+				MatchType: "regex",
+			})
+		}
+	}
+	return qc
+}
+
+// queryParamConditionsValid validates the query parameter Conditions
+// in the supplied slice. Each condition must name a query parameter
+// and set exactly one matcher.
+func queryParamConditionsValid(conds []projcontour.Condition) error {
+	for _, cond := range conds {
+		qp := cond.QueryParameter
+		if qp == nil {
+			continue
+		}
+		if qp.Name == "" {
+			return errors.New("queryParameter conditions must specify a name")
+		}
+
+		set := 0
+		if qp.Present {
+			set++
+		}
+		if qp.Exact != "" {
+			set++
+		}
+		if qp.Prefix != "" {
+			set++
+		}
+		if qp.Regex != "" {
+			set++
+			if err := validRegex(qp.Regex); err != nil {
+				return fmt.Errorf("queryParameter %q: regex %q is not a valid regular expression: %v", qp.Name, qp.Regex, err)
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("queryParameter %q: exactly one of exact, prefix, regex or present must be set", qp.Name)
+		}
+	}
+
+	return nil
+}
+
+// headerConditionRegexesValid checks that any regex header
+// Conditions in the supplied slice are valid regular expressions.
+func headerConditionRegexesValid(conds []projcontour.Condition) error {
+	for _, cond := range conds {
+		if cond.Header == nil {
+			continue
+		}
+		for _, re := range []string{cond.Header.Regex, cond.Header.NotRegex} {
+			if re == "" {
+				continue
+			}
+			if err := validRegex(re); err != nil {
+				return fmt.Errorf("header %q: regex %q is not a valid regular expression: %v", cond.Header.Name, re, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func headerConditionsAreValid(conditions []projcontour.Condition) bool {
 	// Look for duplicate "exact match" headers on conditions
 	// if found, set error condition on HTTPProxy
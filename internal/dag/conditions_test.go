@@ -14,6 +14,7 @@
 package dag
 
 import (
+	"strings"
 	"testing"
 
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -82,6 +83,42 @@ func TestPathCondition(t *testing.T) {
 			}},
 			want: &PrefixCondition{Prefix: "/"},
 		},
+		"exact condition": {
+			conditions: []projcontour.Condition{{
+				Exact: "/healthz",
+			}},
+			want: &ExactCondition{Path: "/healthz"},
+		},
+		"exact condition after prefix": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/api/",
+			}, {
+				Exact: "/healthz",
+			}},
+			want: &ExactCondition{Path: "/api/healthz"},
+		},
+		"regex condition": {
+			conditions: []projcontour.Condition{{
+				Regex: "/v[0-9]+/.*",
+			}},
+			want: &RegexCondition{Regex: "/v[0-9]+/.*"},
+		},
+		"regex condition after prefix": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/api.example/",
+			}, {
+				Regex: "/v[0-9]+/.*",
+			}},
+			want: &RegexCondition{Regex: `/api\.example/v[0-9]+/.*`},
+		},
+		"regex condition without leading slash after prefix": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/static/",
+			}, {
+				Regex: `.*\.png`,
+			}},
+			want: &RegexCondition{Regex: `/static/.*\.png`},
+		},
 	}
 
 	for name, tc := range tests {
@@ -189,6 +226,87 @@ func TestHeaderConditions(t *testing.T) {
 				Invert:    true,
 			}},
 		},
+		"header prefix": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:   "user-agent",
+					Prefix: "Mozilla/",
+				},
+			}},
+			want: []HeaderCondition{{
+				Name:      "user-agent",
+				MatchType: "prefix",
+				Value:     "Mozilla/",
+			}},
+		},
+		"header not prefix": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:      "user-agent",
+					NotPrefix: "Mozilla/",
+				},
+			}},
+			want: []HeaderCondition{{
+				Name:      "user-agent",
+				MatchType: "prefix",
+				Value:     "Mozilla/",
+				Invert:    true,
+			}},
+		},
+		"header suffix": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:   "user-agent",
+					Suffix: "canary",
+				},
+			}},
+			want: []HeaderCondition{{
+				Name:      "user-agent",
+				MatchType: "suffix",
+				Value:     "canary",
+			}},
+		},
+		"header not suffix": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:      "user-agent",
+					NotSuffix: "canary",
+				},
+			}},
+			want: []HeaderCondition{{
+				Name:      "user-agent",
+				MatchType: "suffix",
+				Value:     "canary",
+				Invert:    true,
+			}},
+		},
+		"header regex": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:  "user-agent",
+					Regex: ".*session=canary.*",
+				},
+			}},
+			want: []HeaderCondition{{
+				Name:      "user-agent",
+				MatchType: "regex",
+				Value:     ".*session=canary.*",
+			}},
+		},
+		"header not regex": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:     "user-agent",
+					NotRegex: ".*session=canary.*",
+				},
+			}},
+			want: []HeaderCondition{{
+				Name:      "user-agent",
+				MatchType: "regex",
+				Value:     ".*session=canary.*",
+				Invert:    true,
+			}},
+		},
 		"two header contains": {
 			conditions: []projcontour.Condition{{
 				Header: &projcontour.HeaderCondition{
@@ -304,6 +422,59 @@ func TestPrefixConditionsValid(t *testing.T) {
 			}},
 			want: false,
 		},
+		"valid exact condition": {
+			conditions: []projcontour.Condition{{
+				Exact: "/healthz",
+			}},
+			want: true,
+		},
+		"invalid exact condition": {
+			conditions: []projcontour.Condition{{
+				Exact: "healthz",
+			}},
+			want: false,
+		},
+		"valid regex condition": {
+			conditions: []projcontour.Condition{{
+				Regex: "/v[0-9]+/.*",
+			}},
+			want: true,
+		},
+		"invalid regex condition": {
+			conditions: []projcontour.Condition{{
+				Regex: "/v[0-9+/.*",
+			}},
+			want: false,
+		},
+		"regex condition exceeding the program size limit": {
+			conditions: []projcontour.Condition{{
+				Regex: "/" + strings.Repeat("a", MaxRegexProgramSize),
+			}},
+			want: false,
+		},
+		"prefix and exact in one condition": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/api",
+				Exact:  "/api/healthz",
+			}},
+			want: false,
+		},
+		"prefix and regex conditions": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/api",
+			}, {
+				Regex: "/v[0-9]+",
+			}},
+			want: false,
+		},
+		"two exact conditions": {
+			conditions: []projcontour.Condition{{
+				Exact: "/healthz",
+			}, {
+				Exact: "/readyz",
+			}},
+			want: false,
+		},
 	}
 
 	for name, tc := range tests {
@@ -385,3 +556,223 @@ func TestValidateHeaderConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestIncludeConditionsValid(t *testing.T) {
+	tests := map[string]struct {
+		conditions []projcontour.Condition
+		want       bool
+	}{
+		"prefix condition": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/api",
+			}},
+			want: true,
+		},
+		"exact condition": {
+			conditions: []projcontour.Condition{{
+				Exact: "/api",
+			}},
+			want: false,
+		},
+		"regex condition": {
+			conditions: []projcontour.Condition{{
+				Regex: "/api/.*",
+			}},
+			want: false,
+		},
+		"invalid prefix condition": {
+			conditions: []projcontour.Condition{{
+				Prefix: "api",
+			}},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := includeConditionsValid(tc.conditions)
+			assert.Equal(t, tc.want, err == nil)
+		})
+	}
+}
+
+func TestQueryParamConditions(t *testing.T) {
+	tests := map[string]struct {
+		conditions []projcontour.Condition
+		want       []QueryParamCondition
+	}{
+		"empty condition list": {
+			conditions: nil,
+			want:       nil,
+		},
+		"prefix": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/",
+			}},
+			want: nil,
+		},
+		"query parameter present": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:    "debug",
+					Present: true,
+				},
+			}},
+			want: []QueryParamCondition{{
+				Name:      "debug",
+				MatchType: "present",
+			}},
+		},
+		"query parameter exact, prefix and regex": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:  "version",
+					Exact: "v1",
+				},
+			}, {
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:   "feature",
+					Prefix: "beta-",
+				},
+			}, {
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:  "id",
+					Regex: "[0-9]+",
+				},
+			}},
+			want: []QueryParamCondition{{
+				Name:      "version",
+				Value:     "v1",
+				MatchType: "exact",
+			}, {
+				Name:      "feature",
+				Value:     "beta-",
+				MatchType: "prefix",
+			}, {
+				Name:      "id",
+				Value:     "[0-9]+",
+				MatchType: "regex",
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := mergeQueryParamConditions(tc.conditions)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestQueryParamConditionsValid(t *testing.T) {
+	tests := map[string]struct {
+		conditions []projcontour.Condition
+		want       bool
+	}{
+		"no query parameter conditions": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/api",
+			}},
+			want: true,
+		},
+		"exact query parameter": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:  "version",
+					Exact: "v1",
+				},
+			}},
+			want: true,
+		},
+		"missing name": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Exact: "v1",
+				},
+			}},
+			want: false,
+		},
+		"missing matcher": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name: "version",
+				},
+			}},
+			want: false,
+		},
+		"more than one matcher": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:   "version",
+					Exact:  "v1",
+					Prefix: "v",
+				},
+			}},
+			want: false,
+		},
+		"invalid regex": {
+			conditions: []projcontour.Condition{{
+				QueryParameter: &projcontour.QueryParameterCondition{
+					Name:  "version",
+					Regex: "v(1",
+				},
+			}},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := queryParamConditionsValid(tc.conditions)
+			assert.Equal(t, tc.want, err == nil)
+		})
+	}
+}
+
+func TestHeaderConditionRegexesValid(t *testing.T) {
+	tests := map[string]struct {
+		conditions []projcontour.Condition
+		want       bool
+	}{
+		"no header conditions": {
+			conditions: []projcontour.Condition{{
+				Prefix: "/",
+			}},
+			want: true,
+		},
+		"valid regex": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:  "cookie",
+					Regex: ".*session=canary.*",
+				},
+			}},
+			want: true,
+		},
+		"invalid regex": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:  "cookie",
+					Regex: "(canary",
+				},
+			}},
+			want: false,
+		},
+		"invalid not regex": {
+			conditions: []projcontour.Condition{{
+				Header: &projcontour.HeaderCondition{
+					Name:     "cookie",
+					NotRegex: "(canary",
+				},
+			}},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := headerConditionRegexesValid(tc.conditions)
+			assert.Equal(t, tc.want, err == nil)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // A DAG represents a directed acylic graph of objects representing the relationship
@@ -61,6 +62,15 @@ func (pc *PrefixCondition) String() string {
 	return "prefix: " + pc.Prefix
 }
 
+// ExactCondition matches the whole URL path.
+type ExactCondition struct {
+	Path string
+}
+
+func (ec *ExactCondition) String() string {
+	return "exact: " + ec.Path
+}
+
 // RegexCondition matches the URL by regular expression.
 type RegexCondition struct {
 	Regex string
@@ -78,7 +88,23 @@ type HeaderCondition struct {
 }
 
 func (hc *HeaderCondition) String() string {
-	return "header: " + hc.Name
+	match := hc.MatchType
+	if hc.Invert {
+		match = "not " + match
+	}
+	return "header: " + hc.Name + " " + match + " " + hc.Value
+}
+
+// QueryParamCondition matches on a single query parameter of the
+// request. MatchType is one of exact, prefix, regex or present.
+type QueryParamCondition struct {
+	Name      string
+	Value     string
+	MatchType string
+}
+
+func (qc *QueryParamCondition) String() string {
+	return "queryparam: " + qc.Name + " " + qc.MatchType + " " + qc.Value
 }
 
 // Route defines the properties of a route to a Cluster.
@@ -92,6 +118,10 @@ type Route struct {
 	// match on the request headers.
 	HeaderConditions []HeaderCondition
 
+	// QueryParamConditions specifies a set of additional Conditions to
+	// match on the request query parameters.
+	QueryParamConditions []QueryParamCondition
+
 	Clusters []*Cluster
 
 	// Should this route generate a 301 upgrade if accessed
@@ -114,11 +144,35 @@ type Route struct {
 	// Mirror Policy defines the mirroring policy for this Route.
 	MirrorPolicy *MirrorPolicy
 
+	// RequestHashPolicies is the list of hash policies used to
+	// select an upstream host when the RequestHash load balancing
+	// strategy is in use.
+	RequestHashPolicies []RequestHashPolicy
+
+	// Redirect, if present, causes the route to respond with a
+	// redirect rather than forwarding the request to Clusters.
+	Redirect *Redirect
+
+	// DirectResponse, if present, causes the route to respond with
+	// a fixed response rather than forwarding the request to Clusters.
+	DirectResponse *DirectResponse
+
 	// RequestHeadersPolicy defines how headers are managed during forwarding
 	RequestHeadersPolicy *HeadersPolicy
 
 	// ResponseHeadersPolicy defines how headers are managed during forwarding
 	ResponseHeadersPolicy *HeadersPolicy
+
+	// RateLimitPolicy defines if/how requests for the route are rate limited.
+	RateLimitPolicy *RateLimitPolicy
+
+	// AuthDisabled is set if authorization should be disabled
+	// for this route. If authorization is disabled, the AuthContext
+	// field has no effect.
+	AuthDisabled bool
+
+	// AuthContext sets the authorization context (if authorization is enabled).
+	AuthContext map[string]string
 }
 
 // HasPathPrefix returns whether this route has a PrefixPathCondition.
@@ -127,6 +181,12 @@ func (r *Route) HasPathPrefix() bool {
 	return ok
 }
 
+// HasPathExact returns whether this route has an ExactCondition.
+func (r *Route) HasPathExact() bool {
+	_, ok := r.PathCondition.(*ExactCondition)
+	return ok
+}
+
 // HasPathRegex returns whether this route has a RegexPathCondition.
 func (r *Route) HasPathRegex() bool {
 	_, ok := r.PathCondition.(*RegexCondition)
@@ -160,11 +220,164 @@ type RetryPolicy struct {
 	PerTryTimeout time.Duration
 }
 
+// Redirect defines how a route redirects requests. Empty fields
+// take their value from the request.
+type Redirect struct {
+	// Scheme is the scheme to redirect to.
+	Scheme string
+
+	// Hostname is the host name to redirect to.
+	Hostname string
+
+	// PortNumber is the port to redirect to.
+	PortNumber uint32
+
+	// StatusCode is the HTTP status code of the redirect response.
+	StatusCode int
+
+	// PathRewrite replaces the whole request path.
+	PathRewrite string
+
+	// PrefixRewrite replaces the matched prefix of the request path.
+	PrefixRewrite string
+}
+
+// DirectResponse defines the fixed response a route returns.
+type DirectResponse struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode uint32
+
+	// Body is the response body, if any.
+	Body string
+}
+
+// RequestHashPolicy holds a single hash policy used to compute the
+// hash key of a request. Exactly one hash source is set.
+type RequestHashPolicy struct {
+	// Terminal skips the remaining policies if this policy
+	// produces a hash key.
+	Terminal bool
+
+	// HeaderHashOptions hashes on the value of a request header.
+	HeaderHashOptions *HeaderHashOptions
+
+	// CookieHashOptions hashes on the value of a request cookie.
+	CookieHashOptions *CookieHashOptions
+
+	// QueryParameterHashOptions hashes on the value of a request
+	// query parameter.
+	QueryParameterHashOptions *QueryParameterHashOptions
+
+	// HashSourceIP hashes on the downstream source IP address.
+	HashSourceIP bool
+}
+
+// HeaderHashOptions holds the options for hashing on a header.
+type HeaderHashOptions struct {
+	HeaderName string
+}
+
+// CookieHashOptions holds the options for hashing on a cookie.
+type CookieHashOptions struct {
+	CookieName string
+	TTL        time.Duration
+	Path       string
+}
+
+// QueryParameterHashOptions holds the options for hashing on a query
+// parameter.
+type QueryParameterHashOptions struct {
+	ParameterName string
+}
+
 // MirrorPolicy defines the mirroring policy for a route.
 type MirrorPolicy struct {
 	Cluster *Cluster
 }
 
+// RateLimitPolicy holds rate limiting parameters.
+type RateLimitPolicy struct {
+	Local  *LocalRateLimitPolicy
+	Global *GlobalRateLimitPolicy
+}
+
+// LocalRateLimitPolicy holds local rate limiting parameters,
+// expressed as a token bucket that is enforced by each Envoy.
+type LocalRateLimitPolicy struct {
+	MaxTokens          uint32
+	TokensPerFill      uint32
+	FillInterval       time.Duration
+	ResponseStatusCode uint32
+}
+
+// GlobalRateLimitPolicy holds global rate limiting parameters.
+type GlobalRateLimitPolicy struct {
+	Descriptors []*RateLimitDescriptor
+}
+
+// RateLimitDescriptor is a list of rate limit descriptor entries.
+type RateLimitDescriptor struct {
+	Entries []RateLimitDescriptorEntry
+}
+
+// RateLimitDescriptorEntry is an entry in a rate limit descriptor.
+// Exactly one field should be non-nil.
+type RateLimitDescriptorEntry struct {
+	GenericKey    *GenericKeyDescriptorEntry
+	HeaderMatch   *HeaderMatchDescriptorEntry
+	RemoteAddress *RemoteAddressDescriptorEntry
+}
+
+// GenericKeyDescriptorEntry configures a descriptor entry
+// that has a static value.
+type GenericKeyDescriptorEntry struct {
+	Value string
+}
+
+// HeaderMatchDescriptorEntry configures a descriptor entry
+// that's populated only if the specified header is present
+// on the request.
+type HeaderMatchDescriptorEntry struct {
+	HeaderName string
+	Key        string
+}
+
+// RemoteAddressDescriptorEntry configures a descriptor entry
+// that contains the remote address (i.e. client IP).
+type RemoteAddressDescriptorEntry struct{}
+
+// TracingPolicy holds the per virtual host tracing parameters.
+type TracingPolicy struct {
+	// Disabled turns off tracing for the virtual host.
+	Disabled bool
+
+	// RandomSampling is the percentage of requests randomly
+	// selected for tracing. Zero means the global rate applies.
+	RandomSampling uint32
+}
+
+// CORSPolicy allows setting the CORS policy
+type CORSPolicy struct {
+	// Specifies whether the resource allows credentials.
+	AllowCredentials bool
+
+	// AllowOrigin specifies the origins that will be allowed to do CORS requests.
+	AllowOrigin []string
+
+	// AllowMethods specifies the content for the *access-control-allow-methods* header.
+	AllowMethods []string
+
+	// AllowHeaders specifies the content for the *access-control-allow-headers* header.
+	AllowHeaders []string
+
+	// ExposeHeaders Specifies the content for the *access-control-expose-headers* header.
+	ExposeHeaders []string
+
+	// MaxAge specifies the content for the *access-control-max-age* header.
+	// Zero leaves the header unset.
+	MaxAge time.Duration
+}
+
 // HeadersPolicy defines how headers are managed during forwarding
 type HeadersPolicy struct {
 	// HostRewrite defines if a host should be rewritten on upstream requests
@@ -181,16 +394,36 @@ type HeaderValue struct {
 	Value string
 }
 
-// UpstreamValidation defines how to validate the certificate on the upstream service
-type UpstreamValidation struct {
+// PeerValidationContext defines how to validate the certificate presented
+// by the peer of a TLS connection, either an upstream service or a
+// downstream client.
+type PeerValidationContext struct {
 	// CACertificate holds a reference to the Secret containing the CA to be used to
-	// verify the upstream connection.
+	// verify the peer's certificate.
 	CACertificate *Secret
 	// SubjectName holds an optional subject name which Envoy will check against the
-	// certificate presented by the upstream.
+	// certificate presented by the peer.
 	SubjectName string
 }
 
+// GetCACertificate returns the CA certificate from PeerValidationContext.
+func (pvc *PeerValidationContext) GetCACertificate() []byte {
+	if pvc == nil || pvc.CACertificate == nil {
+		// No validation required.
+		return nil
+	}
+	return pvc.CACertificate.Object.Data[CACertificateKey]
+}
+
+// GetSubjectName returns the SubjectName from PeerValidationContext.
+func (pvc *PeerValidationContext) GetSubjectName() string {
+	if pvc == nil {
+		// No validation required.
+		return ""
+	}
+	return pvc.SubjectName
+}
+
 func (r *Route) Visit(f func(Vertex)) {
 	for _, c := range r.Clusters {
 		f(c)
@@ -203,6 +436,21 @@ type VirtualHost struct {
 	// as defined by RFC 3986.
 	Name string
 
+	// RateLimitPolicy defines if/how requests for the virtual host
+	// are rate limited.
+	RateLimitPolicy *RateLimitPolicy
+
+	// CORSPolicy is the cross-origin policy to apply to the VirtualHost.
+	CORSPolicy *CORSPolicy
+
+	// TracingPolicy overrides the global tracing sampling
+	// for requests to the VirtualHost.
+	TracingPolicy *TracingPolicy
+
+	// DisableCompression stops responses from the
+	// VirtualHost being compressed.
+	DisableCompression bool
+
 	routes map[string]*Route
 }
 
@@ -218,6 +466,9 @@ func conditionsToString(r *Route) string {
 	for _, cond := range r.HeaderConditions {
 		s = append(s, cond.String())
 	}
+	for _, cond := range r.QueryParamConditions {
+		s = append(s, cond.String())
+	}
 	return strings.Join(s, ",")
 }
 
@@ -239,11 +490,40 @@ type SecureVirtualHost struct {
 	// TLS minimum protocol version. Defaults to envoy_api_v2_auth.TlsParameters_TLS_AUTO
 	MinProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
 
+	// TLS maximum protocol version. If TLS_AUTO, the listener's
+	// configured maximum protocol version is used.
+	MaxProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
+
+	// CipherSuites offered to TLS 1.2 clients. If empty, the
+	// listener's configured cipher suites are used.
+	CipherSuites []string
+
 	// The cert and key for this host.
 	Secret *Secret
 
+	// DownstreamValidation defines how to verify the client's certificate.
+	DownstreamValidation *PeerValidationContext
+
+	// FallbackCertificate is the cert and key served to clients
+	// which do not send SNI. If nil, those clients are not
+	// routed to this host.
+	FallbackCertificate *Secret
+
 	// Service to TCP proxy all incoming connections.
 	*TCPProxy
+
+	// AuthorizationService points to the cluster that client
+	// requests are sent to for authorization. If nil, no
+	// authorization is enabled for this host.
+	AuthorizationService *Cluster
+
+	// AuthorizationResponseTimeout sets how long the proxy should
+	// wait for authorization server responses.
+	AuthorizationResponseTimeout time.Duration
+
+	// AuthorizationFailOpen sets whether client requests should be
+	// forwarded when the authorization server fails to respond.
+	AuthorizationFailOpen bool
 }
 
 func (s *SecureVirtualHost) Visit(f func(Vertex)) {
@@ -251,9 +531,15 @@ func (s *SecureVirtualHost) Visit(f func(Vertex)) {
 	if s.TCPProxy != nil {
 		f(s.TCPProxy)
 	}
+	if s.AuthorizationService != nil {
+		f(s.AuthorizationService)
+	}
 	if s.Secret != nil {
 		f(s.Secret) // secret is not required if vhost is using tls passthrough
 	}
+	if s.FallbackCertificate != nil {
+		f(s.FallbackCertificate)
+	}
 }
 
 func (s *SecureVirtualHost) Valid() bool {
@@ -283,12 +569,42 @@ type Listener struct {
 	Port int
 
 	VirtualHosts []Vertex
+
+	// Name is the name of an additional TCP listener.
+	// Only set when TCPProxy is set.
+	Name string
+
+	// TCPProxy, if set, forwards every connection accepted
+	// by this listener without inspecting TLS or SNI.
+	TCPProxy *TCPProxy
 }
 
 func (l *Listener) Visit(f func(Vertex)) {
 	for _, vh := range l.VirtualHosts {
 		f(vh)
 	}
+	if l.TCPProxy != nil {
+		f(l.TCPProxy)
+	}
+}
+
+// TCPListenerConfig describes an additional listener which forwards
+// plain TCP connections to a port of a Kubernetes service.
+type TCPListenerConfig struct {
+	// Name is the name of the listener.
+	Name string
+
+	// Address is the TCP address to listen on.
+	Address string
+
+	// Port is the TCP port to listen on.
+	Port int
+
+	// Service identifies the Kubernetes service connections are forwarded to.
+	Service types.NamespacedName
+
+	// ServicePort is the port of the service connections are forwarded to.
+	ServicePort int
 }
 
 // TCPProxy represents a cluster of TCP endpoints.
@@ -297,6 +613,32 @@ type TCPProxy struct {
 	// Clusters is the, possibly weighted, set
 	// of upstream services to forward decrypted traffic.
 	Clusters []*Cluster
+
+	// TimeoutPolicy defines the idle timeout of proxied
+	// connections. If nil, the default timeout applies.
+	TimeoutPolicy *TCPProxyTimeoutPolicy
+}
+
+// TCPProxyTimeoutPolicy defines the timeout policy for a TCPProxy.
+type TCPProxyTimeoutPolicy struct {
+	// IdleTimeout is the timeout applied to idle connections.
+	// A timeout of zero implies "use the default"
+	// A timeout of -1 represents "infinity"
+	IdleTimeout time.Duration
+
+	// ConnectTimeout is the timeout applied to establishing
+	// connections to the upstream clusters.
+	// A timeout of zero implies "use the default"
+	ConnectTimeout time.Duration
+}
+
+// GetConnectTimeout returns the ConnectTimeout from TCPProxyTimeoutPolicy.
+func (tp *TCPProxyTimeoutPolicy) GetConnectTimeout() time.Duration {
+	if tp == nil {
+		// No timeout policy, use the default.
+		return 0
+	}
+	return tp.ConnectTimeout
 }
 
 func (t *TCPProxy) Visit(f func(Vertex)) {
@@ -370,7 +712,7 @@ type Cluster struct {
 	Protocol string
 
 	// UpstreamValidation defines how to verify the backend service's certificate
-	UpstreamValidation *UpstreamValidation
+	UpstreamValidation *PeerValidationContext
 
 	// The load balancer type to use when picking a host in the cluster.
 	// See https://www.envoyproxy.io/docs/envoy/latest/api-v2/api/v2/cds.proto#envoy-api-enum-cluster-lbpolicy
@@ -382,6 +724,26 @@ type Cluster struct {
 	// Cluster tcp health check policy
 	*TCPHealthCheckPolicy
 
+	// OutlierDetectionPolicy defines how endpoints that fail requests
+	// are ejected from the cluster.
+	OutlierDetectionPolicy *OutlierDetectionPolicy
+
+	// CircuitBreakerPolicy overrides the circuit breaking limits
+	// of the Upstream service. If nil, the Upstream limits apply.
+	CircuitBreakerPolicy *CircuitBreakerPolicy
+
+	// ClientCertificate is the optional client certificate and key
+	// presented to the Upstream when connecting over TLS.
+	ClientCertificate *Secret
+
+	// ConnectTimeout is the timeout for establishing connections
+	// to the Upstream. If zero, the default timeout applies.
+	ConnectTimeout time.Duration
+
+	// ProxyProtocol is the version of the PROXY protocol header,
+	// v1 or v2, sent to the Upstream. If empty, none is sent.
+	ProxyProtocol string
+
 	// RequestHeadersPolicy defines how headers are managed during forwarding
 	RequestHeadersPolicy *HeadersPolicy
 
@@ -435,3 +797,22 @@ type TCPHealthCheckPolicy struct {
 	UnhealthyThreshold uint32
 	HealthyThreshold   uint32
 }
+
+// CircuitBreakerPolicy holds the circuit breaking limits of a Cluster.
+// Zero values take the Envoy defaults.
+type CircuitBreakerPolicy struct {
+	MaxConnections     uint32
+	MaxPendingRequests uint32
+	MaxRequests        uint32
+	MaxRetries         uint32
+}
+
+// OutlierDetectionPolicy holds the passive health checking parameters
+// of a Cluster. Zero values take the Envoy defaults.
+type OutlierDetectionPolicy struct {
+	ConsecutiveServerErrors  uint32
+	ConsecutiveGatewayErrors uint32
+	Interval                 time.Duration
+	BaseEjectionTime         time.Duration
+	MaxEjectionPercent       uint32
+}
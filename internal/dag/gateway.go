@@ -0,0 +1,485 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
+)
+
+// GatewayController is the value of GatewayClass.Spec.Controller which
+// identifies the GatewayClasses, and thus the Gateways, managed by Contour.
+const GatewayController = "projectcontour.io/contour"
+
+// computeGateways translates the service-apis Gateways managed by Contour
+// into virtual hosts on the HTTP and HTTPS listeners.
+func (b *Builder) computeGateways() {
+	// Gateways are processed in a stable order so that, if two Gateways
+	// program the same secure virtual host, the result does not depend on
+	// map iteration order.
+	var gateways []*serviceapis.Gateway
+	for _, gw := range b.Source.gateways {
+		if b.gatewayClassManaged(gw.Spec.Class) {
+			gateways = append(gateways, gw)
+		}
+	}
+	sort.Slice(gateways, func(i, j int) bool {
+		if gateways[i].Namespace != gateways[j].Namespace {
+			return gateways[i].Namespace < gateways[j].Namespace
+		}
+		return gateways[i].Name < gateways[j].Name
+	})
+
+	owners := b.hostnameOwners(gateways)
+	routes := make(map[*serviceapis.HTTPRoute]*httpRouteStatus)
+	for _, gw := range gateways {
+		b.computeGateway(gw, owners, routes)
+	}
+
+	// An HTTPRoute may be referenced by several Gateways, so its
+	// status is written once every Gateway has been computed.
+	for route, rs := range routes {
+		sw, commit := b.WithObject(route)
+		for _, gw := range rs.admitted {
+			sw.WithGateway(gw)
+		}
+		if rs.err != "" {
+			sw.SetInvalid("%s", rs.err)
+		} else {
+			sw.SetValid()
+		}
+		commit()
+	}
+}
+
+// httpRouteStatus records which Gateways admitted an HTTPRoute.
+type httpRouteStatus struct {
+	// admitted are the Gateways which admitted the route.
+	admitted []*serviceapis.Gateway
+
+	// err is the reason the first Gateway to reject the
+	// route did so, or empty if no Gateway rejected it.
+	err string
+}
+
+// hostnameOwner is an object which serves a hostname.
+type hostnameOwner struct {
+	kind, namespace, name string
+}
+
+func (o hostnameOwner) String() string {
+	return fmt.Sprintf("%s %s/%s", o.kind, o.namespace, o.name)
+}
+
+// hostnameOwners returns the objects which serve each hostname. A Gateway
+// may not take over a hostname served by an Ingress, IngressRoute or
+// HTTPProxy, nor one served by a Gateway in another namespace.
+func (b *Builder) hostnameOwners(gateways []*serviceapis.Gateway) map[string][]hostnameOwner {
+	owners := make(map[string][]hostnameOwner)
+	add := func(hostname string, owner hostnameOwner) {
+		for _, o := range owners[hostname] {
+			if o == owner {
+				return
+			}
+		}
+		owners[hostname] = append(owners[hostname], owner)
+	}
+
+	for _, ing := range b.Source.ingresses {
+		owner := hostnameOwner{kind: "Ingress", namespace: ing.Namespace, name: ing.Name}
+		for _, rule := range rulesFromSpec(ing.Spec) {
+			add(stringOrDefault(rule.Host, "*"), owner)
+		}
+		for _, tls := range ing.Spec.TLS {
+			for _, host := range tls.Hosts {
+				add(host, owner)
+			}
+		}
+	}
+
+	for _, ir := range b.Source.ingressroutes {
+		if ir.Spec.VirtualHost == nil || !b.rootAllowed(ir.Namespace) {
+			continue
+		}
+		add(ir.Spec.VirtualHost.Fqdn, hostnameOwner{kind: "IngressRoute", namespace: ir.Namespace, name: ir.Name})
+	}
+
+	for _, proxy := range b.Source.httpproxies {
+		if proxy.Spec.VirtualHost == nil || !b.rootAllowed(proxy.Namespace) {
+			continue
+		}
+		for _, host := range hostnames(proxy.Spec.VirtualHost) {
+			add(host, hostnameOwner{kind: "HTTPProxy", namespace: proxy.Namespace, name: proxy.Name})
+		}
+	}
+
+	for _, gw := range gateways {
+		if !b.rootAllowed(gw.Namespace) {
+			continue
+		}
+		owner := hostnameOwner{kind: "Gateway", namespace: gw.Namespace, name: gw.Name}
+		for _, ref := range gw.Spec.Routes {
+			if ref.Kind != "HTTPRoute" {
+				continue
+			}
+			route, ok := b.Source.httproutes[Meta{name: ref.Name, namespace: gw.Namespace}]
+			if !ok {
+				continue
+			}
+			for _, host := range route.Spec.Hosts {
+				for _, hostname := range host.Hostnames {
+					add(hostname, owner)
+				}
+			}
+			if route.Spec.Default != nil {
+				add("*", owner)
+			}
+		}
+	}
+
+	for _, o := range owners {
+		sort.Slice(o, func(i, j int) bool {
+			return o[i].String() < o[j].String()
+		})
+	}
+	return owners
+}
+
+// gatewayClassManaged returns true if the named GatewayClass exists and
+// is managed by Contour.
+func (b *Builder) gatewayClassManaged(name string) bool {
+	class, ok := b.Source.gatewayclasses[Meta{name: name}]
+	return ok && class.Spec.Controller == GatewayController
+}
+
+// gatewayListener is a Gateway listener resolved to one of Contour's
+// HTTP or HTTPS listeners.
+type gatewayListener struct {
+	secure          bool
+	secret          *Secret
+	minProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
+}
+
+func (b *Builder) computeGateway(gw *serviceapis.Gateway, owners map[string][]hostnameOwner, routes map[*serviceapis.HTTPRoute]*httpRouteStatus) {
+	sw, commit := b.WithObject(gw)
+	defer commit()
+
+	// every problem with the Gateway is reported in its status,
+	// not just the last one found.
+	var errs []string
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+	defer func() {
+		if len(errs) > 0 {
+			sw.SetInvalid("%s", strings.Join(errs, "; "))
+			return
+		}
+		sw.SetValid()
+	}()
+
+	// ensure the Gateway lives in an allowed root namespace
+	if !b.rootAllowed(gw.Namespace) {
+		invalid("root Gateway cannot be defined in this namespace")
+		return
+	}
+
+	// Contour's Envoy listeners are fixed by the serve configuration, so the
+	// address and port of each Gateway listener are not used; listeners are
+	// bound to the HTTP or HTTPS listener according to their protocol.
+	var listeners []gatewayListener
+	for _, l := range gw.Spec.Listeners {
+		listener, err := b.gatewayListener(gw, l)
+		if err != nil {
+			invalid("listener %q: %s", l.Name, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		invalid("Spec.Listeners must contain at least one valid listener")
+		return
+	}
+
+	for _, ref := range gw.Spec.Routes {
+		if ref.APIGroup != nil && *ref.APIGroup != serviceapis.GroupVersion.Group {
+			invalid("route %q: unsupported API group %q", ref.Name, *ref.APIGroup)
+			continue
+		}
+		switch ref.Kind {
+		case "HTTPRoute":
+			route, ok := b.Source.httproutes[Meta{name: ref.Name, namespace: gw.Namespace}]
+			if !ok {
+				invalid("HTTPRoute %q not found", ref.Name)
+				continue
+			}
+			rs, ok := routes[route]
+			if !ok {
+				rs = &httpRouteStatus{}
+				routes[route] = rs
+			}
+			if err := b.computeHTTPRoute(route, listeners, owners); err != nil {
+				invalid("HTTPRoute %q: %s", ref.Name, err)
+				if rs.err == "" {
+					rs.err = fmt.Sprintf("Gateway %q: %s", gw.Name, err)
+				}
+				continue
+			}
+			rs.admitted = append(rs.admitted, gw)
+		case "TcpRoute":
+			// TcpRoute has no spec fields in this version of the
+			// service-apis, so there is nothing to forward traffic to.
+			invalid("TcpRoute %q: TCP routes are not supported", ref.Name)
+		default:
+			invalid("route %q: unsupported kind %q", ref.Name, ref.Kind)
+		}
+	}
+}
+
+// gatewayListener validates a Gateway listener and resolves its TLS
+// configuration, if any.
+func (b *Builder) gatewayListener(gw *serviceapis.Gateway, l serviceapis.Listener) (gatewayListener, error) {
+	protocol := serviceapis.HTTPProcotol
+	if l.Protocol != nil {
+		protocol = *l.Protocol
+	}
+
+	switch protocol {
+	case serviceapis.HTTPProcotol:
+		return gatewayListener{}, nil
+	case serviceapis.HTTPSProcotol:
+		if l.TLS == nil || len(l.TLS.Certificates) == 0 {
+			return gatewayListener{}, fmt.Errorf("protocol %q requires a TLS certificate", protocol)
+		}
+		cert := l.TLS.Certificates[0]
+		if cert.Kind != "Secret" || (cert.APIGroup != nil && *cert.APIGroup != "") {
+			return gatewayListener{}, fmt.Errorf("unsupported certificate kind %q", cert.Kind)
+		}
+		sec := b.lookupSecret(Meta{name: cert.Name, namespace: gw.Namespace}, validSecret)
+		if sec == nil {
+			return gatewayListener{}, fmt.Errorf("TLS Secret [%s] not found or is malformed", cert.Name)
+		}
+		return gatewayListener{
+			secure:          true,
+			secret:          sec,
+			minProtoVersion: gatewayMinProtoVersion(l.TLS.MinimumVersion),
+		}, nil
+	default:
+		return gatewayListener{}, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+}
+
+// gatewayMinProtoVersion converts a service-apis TLS version into
+// the equivalent Envoy TLS protocol version.
+func gatewayMinProtoVersion(version *string) envoy_api_v2_auth.TlsParameters_TlsProtocol {
+	if version == nil {
+		return MinProtoVersion("")
+	}
+	switch *version {
+	case serviceapis.TLS1_3:
+		return MinProtoVersion("1.3")
+	case serviceapis.TLS1_2:
+		return MinProtoVersion("1.2")
+	default:
+		return MinProtoVersion("")
+	}
+}
+
+// computeHTTPRoute adds the routes of the HTTPRoute to the virtual hosts
+// of each of the listeners supplied. If any rule of the HTTPRoute is
+// invalid, or any of its hostnames is owned by another object, no
+// routes are added and an error is returned.
+func (b *Builder) computeHTTPRoute(route *serviceapis.HTTPRoute, listeners []gatewayListener, owners map[string][]hostnameOwner) error {
+	hosts := append([]serviceapis.HTTPRouteHost{}, route.Spec.Hosts...)
+	if def := route.Spec.Default; def != nil {
+		if len(def.Hostnames) > 0 {
+			return fmt.Errorf("Spec.Default.Hostnames must be empty")
+		}
+		hosts = append(hosts, serviceapis.HTTPRouteHost{
+			Hostnames: []string{"*"},
+			Rules:     def.Rules,
+		})
+	}
+
+	vhosts := make(map[string][]*Route)
+	for _, host := range hosts {
+		var routes []*Route
+		for _, rule := range host.Rules {
+			r, err := b.httpRouteRule(route.Namespace, rule)
+			if err != nil {
+				return err
+			}
+			routes = append(routes, r)
+		}
+		for _, hostname := range host.Hostnames {
+			if hostname != "*" && strings.Contains(hostname, "*") && !isWildcard(hostname) {
+				return fmt.Errorf("hostname %q can only use a wildcard as its leftmost label", hostname)
+			}
+			for _, o := range owners[hostname] {
+				// Gateways in the same namespace share their hostnames.
+				if o.kind == "Gateway" && o.namespace == route.Namespace {
+					continue
+				}
+				return fmt.Errorf("hostname %q is already used by %s", hostname, o)
+			}
+			vhosts[hostname] = append(vhosts[hostname], routes...)
+		}
+	}
+
+	for hostname, routes := range vhosts {
+		for _, l := range listeners {
+			if !l.secure {
+				addRoutes(b.lookupVirtualHost(hostname), routes)
+				continue
+			}
+			if hostname == "*" {
+				// SNI requires a hostname, so the default host is
+				// only served over HTTP.
+				continue
+			}
+			svhost := b.lookupSecureVirtualHost(hostname)
+			svhost.Secret = l.secret
+			svhost.MinProtoVersion = l.minProtoVersion
+			addRoutes(svhost, routes)
+		}
+	}
+	return nil
+}
+
+// httpRouteRule converts a HTTPRouteRule into a *Route.
+func (b *Builder) httpRouteRule(namespace string, rule serviceapis.HTTPRouteRule) (*Route, error) {
+	r := &Route{
+		PathCondition: &PrefixCondition{Prefix: "/"},
+	}
+
+	if match := rule.Match; match != nil {
+		cond, err := httpRoutePathCondition(match)
+		if err != nil {
+			return nil, err
+		}
+		r.PathCondition = cond
+
+		headers, err := httpRouteHeaderConditions(match)
+		if err != nil {
+			return nil, err
+		}
+		r.HeaderConditions = headers
+	}
+
+	if rule.Filter != nil && rule.Filter.Headers != nil {
+		policy := &projcontour.HeadersPolicy{
+			Remove: rule.Filter.Headers.Remove,
+		}
+		for _, name := range sortedKeys(rule.Filter.Headers.Add) {
+			policy.Set = append(policy.Set, projcontour.HeaderValue{
+				Name:  name,
+				Value: rule.Filter.Headers.Add[name],
+			})
+		}
+		reqHP, err := headersPolicy(policy, false /* disallow Host */)
+		if err != nil {
+			return nil, err
+		}
+		r.RequestHeadersPolicy = reqHP
+	}
+
+	if rule.Action == nil || rule.Action.ForwardTo == nil {
+		return nil, fmt.Errorf("rule action must forward to a Service")
+	}
+	ref := rule.Action.ForwardTo
+	if ref.Kind != "Service" || (ref.APIGroup != nil && *ref.APIGroup != "") {
+		return nil, fmt.Errorf("forwardTo: unsupported kind %q", ref.Kind)
+	}
+
+	m := Meta{name: ref.Name, namespace: namespace}
+	svc, ok := b.Source.services[m]
+	if !ok {
+		return nil, fmt.Errorf("Service [%s] not found", ref.Name)
+	}
+	// The forwardTo reference does not carry a port, so the
+	// Service must be unambiguous.
+	if len(svc.Spec.Ports) != 1 {
+		return nil, fmt.Errorf("Service [%s] must expose exactly one port", ref.Name)
+	}
+	s := b.lookupService(m, intstr.FromInt(int(svc.Spec.Ports[0].Port)))
+	if s == nil {
+		return nil, fmt.Errorf("Service [%s] not found", ref.Name)
+	}
+	r.Clusters = append(r.Clusters, &Cluster{
+		Upstream: s,
+		Protocol: s.Protocol,
+	})
+
+	return r, nil
+}
+
+// httpRoutePathCondition returns the path Condition for the HTTPRouteMatch.
+func httpRoutePathCondition(match *serviceapis.HTTPRouteMatch) (Condition, error) {
+	path := "/"
+	if match.Path != nil {
+		path = *match.Path
+	}
+
+	switch match.PathType {
+	case serviceapis.PathTypeExact, "":
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("path %q must start with /", path)
+		}
+		return &ExactCondition{Path: path}, nil
+	case serviceapis.PathTypePrefix, serviceapis.PathTypeImplementionSpecific:
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("path %q must start with /", path)
+		}
+		return &PrefixCondition{Prefix: path}, nil
+	case serviceapis.PathTypeRegularExpression:
+		if err := validRegex(path); err != nil {
+			return nil, fmt.Errorf("path %q is not a valid regular expression: %v", path, err)
+		}
+		return &RegexCondition{Regex: path}, nil
+	default:
+		return nil, fmt.Errorf("unsupported path type %q", match.PathType)
+	}
+}
+
+// httpRouteHeaderConditions returns the HeaderConditions for the HTTPRouteMatch.
+func httpRouteHeaderConditions(match *serviceapis.HTTPRouteMatch) ([]HeaderCondition, error) {
+	if match.HeaderType != nil && *match.HeaderType != serviceapis.HeaderTypeExact {
+		return nil, fmt.Errorf("unsupported header type %q", *match.HeaderType)
+	}
+
+	var conditions []HeaderCondition
+	for _, name := range sortedKeys(match.Header) {
+		conditions = append(conditions, HeaderCondition{
+			Name:      name,
+			Value:     match.Header[name],
+			MatchType: "exact",
+		})
+	}
+	return conditions, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
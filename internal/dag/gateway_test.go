@@ -0,0 +1,663 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	"github.com/google/go-cmp/cmp"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
+)
+
+func TestDAGInsertGateway(t *testing.T) {
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	class := &serviceapis.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: serviceapis.GatewayClassSpec{
+			Controller: GatewayController,
+		},
+	}
+
+	otherclass := &serviceapis.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other",
+		},
+		Spec: serviceapis.GatewayClassSpec{
+			Controller: "example.com/other",
+		},
+	}
+
+	route1 := &serviceapis.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic",
+			Namespace: "default",
+		},
+		Spec: serviceapis.HTTPRouteSpec{
+			Hosts: []serviceapis.HTTPRouteHost{{
+				Hostnames: []string{"example.com"},
+				Rules: []serviceapis.HTTPRouteRule{{
+					Match: &serviceapis.HTTPRouteMatch{
+						PathType: serviceapis.PathTypePrefix,
+						Path:     stringptr("/"),
+					},
+					Action: &serviceapis.HTTPRouteAction{
+						ForwardTo: &v1.TypedLocalObjectReference{
+							Kind: "Service",
+							Name: "kuard",
+						},
+					},
+				}},
+			}},
+		},
+	}
+
+	// route2 uses the default host, an exact path match,
+	// header conditions and a header filter.
+	route2 := &serviceapis.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: "default",
+		},
+		Spec: serviceapis.HTTPRouteSpec{
+			Default: &serviceapis.HTTPRouteHost{
+				Rules: []serviceapis.HTTPRouteRule{{
+					Match: &serviceapis.HTTPRouteMatch{
+						Path: stringptr("/healthz"),
+						Header: map[string]string{
+							"x-canary": "true",
+						},
+					},
+					Filter: &serviceapis.HTTPRouteFilter{
+						Headers: &serviceapis.HTTPHeaderFilter{
+							Add:    map[string]string{"x-gateway": "contour"},
+							Remove: []string{"x-internal"},
+						},
+					},
+					Action: &serviceapis.HTTPRouteAction{
+						ForwardTo: &v1.TypedLocalObjectReference{
+							Kind: "Service",
+							Name: "kuard",
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	gatewayHTTP := &serviceapis.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "http",
+			Namespace: "default",
+		},
+		Spec: serviceapis.GatewaySpec{
+			Class: "contour",
+			Listeners: []serviceapis.Listener{{
+				Name:     "http",
+				Protocol: stringptr(serviceapis.HTTPProcotol),
+			}},
+			Routes: []v1.TypedLocalObjectReference{{
+				Kind: "HTTPRoute",
+				Name: "basic",
+			}},
+		},
+	}
+
+	// routeWildcard is route1 served on a wildcard hostname
+	routeWildcard := route1.DeepCopy()
+	routeWildcard.Spec.Hosts[0].Hostnames = []string{"*.example.com"}
+
+	gatewayOtherClass := gatewayHTTP.DeepCopy()
+	gatewayOtherClass.Spec.Class = "other"
+
+	gatewayHTTPS := &serviceapis.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "https",
+			Namespace: "default",
+		},
+		Spec: serviceapis.GatewaySpec{
+			Class: "contour",
+			Listeners: []serviceapis.Listener{{
+				Name:     "https",
+				Protocol: stringptr(serviceapis.HTTPSProcotol),
+				TLS: &serviceapis.ListenerTLS{
+					Certificates: []v1.TypedLocalObjectReference{{
+						Kind: "Secret",
+						Name: "secret",
+					}},
+					MinimumVersion: stringptr(serviceapis.TLS1_3),
+				},
+			}},
+			Routes: []v1.TypedLocalObjectReference{{
+				Kind: "HTTPRoute",
+				Name: "basic",
+			}},
+		},
+	}
+
+	gatewayDefault := &serviceapis.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: "default",
+		},
+		Spec: serviceapis.GatewaySpec{
+			Class: "contour",
+			Listeners: []serviceapis.Listener{{
+				Name: "http",
+			}},
+			Routes: []v1.TypedLocalObjectReference{{
+				Kind: "HTTPRoute",
+				Name: "default",
+			}},
+		},
+	}
+
+	// gatewayEvil tries to take over bank.com, which is
+	// served by proxyBank in another namespace.
+	secBank := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "a",
+			Namespace: "teama",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	secEvil := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "b",
+			Namespace: "evil",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	sBank := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bank",
+			Namespace: "teama",
+		},
+		Spec: s1.Spec,
+	}
+
+	sSteal := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "steal",
+			Namespace: "evil",
+		},
+		Spec: s1.Spec,
+	}
+
+	proxyBank := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "a",
+			Namespace: "teama",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "bank.com",
+				TLS: &projcontour.TLS{
+					SecretName: "a",
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: "bank",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	routeSteal := &serviceapis.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "steal",
+			Namespace: "evil",
+		},
+		Spec: serviceapis.HTTPRouteSpec{
+			Hosts: []serviceapis.HTTPRouteHost{{
+				Hostnames: []string{"bank.com"},
+				Rules: []serviceapis.HTTPRouteRule{{
+					Match: &serviceapis.HTTPRouteMatch{
+						PathType: serviceapis.PathTypePrefix,
+						Path:     stringptr("/admin"),
+					},
+					Action: &serviceapis.HTTPRouteAction{
+						ForwardTo: &v1.TypedLocalObjectReference{
+							Kind: "Service",
+							Name: "steal",
+						},
+					},
+				}},
+			}},
+		},
+	}
+
+	gatewayEvil := &serviceapis.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw",
+			Namespace: "evil",
+		},
+		Spec: serviceapis.GatewaySpec{
+			Class: "contour",
+			Listeners: []serviceapis.Listener{{
+				Name:     "https",
+				Protocol: stringptr(serviceapis.HTTPSProcotol),
+				TLS: &serviceapis.ListenerTLS{
+					Certificates: []v1.TypedLocalObjectReference{{
+						Kind: "Secret",
+						Name: "b",
+					}},
+				},
+			}},
+			Routes: []v1.TypedLocalObjectReference{{
+				Kind: "HTTPRoute",
+				Name: "steal",
+			}},
+		},
+	}
+
+	// gatewayOtherNamespace serves example.com, like
+	// gatewayHTTP, from another namespace.
+	route1OtherNamespace := route1.DeepCopy()
+	route1OtherNamespace.Namespace = "other"
+	s1OtherNamespace := s1.DeepCopy()
+	s1OtherNamespace.Namespace = "other"
+	gatewayOtherNamespace := gatewayHTTP.DeepCopy()
+	gatewayOtherNamespace.Namespace = "other"
+
+	tests := map[string]struct {
+		objs           []interface{}
+		rootNamespaces []string
+		want           []Vertex
+	}{
+		"insert gateway with http listener": {
+			objs: []interface{}{class, gatewayHTTP, route1, s1},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", prefixroute("/", service(s1))),
+					),
+				},
+			),
+		},
+		"insert gateway with wildcard hostname": {
+			objs: []interface{}{class, gatewayHTTP, routeWildcard, s1},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("*.example.com", prefixroute("/", service(s1))),
+					),
+				},
+			),
+		},
+		"insert gateway with unmanaged class": {
+			objs: []interface{}{class, otherclass, gatewayOtherClass, route1, s1},
+			want: listeners(),
+		},
+		"insert gateway without gateway class": {
+			objs: []interface{}{gatewayHTTP, route1, s1},
+			want: listeners(),
+		},
+		"insert gateway with missing service": {
+			objs: []interface{}{class, gatewayHTTP, route1},
+			want: listeners(),
+		},
+		"insert gateway with https listener": {
+			objs: []interface{}{class, gatewayHTTPS, route1, s1, sec1},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:   "example.com",
+								routes: routes(prefixroute("/", service(s1))),
+							},
+							MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_3,
+							Secret:          secret(sec1),
+						},
+					),
+				},
+			),
+		},
+		"insert gateway with https listener missing secret": {
+			objs: []interface{}{class, gatewayHTTPS, route1, s1},
+			want: listeners(),
+		},
+		"insert gateway with default host": {
+			objs: []interface{}{class, gatewayDefault, route2, s1},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("*", &Route{
+							PathCondition: exact("/healthz"),
+							HeaderConditions: []HeaderCondition{{
+								Name:      "x-canary",
+								Value:     "true",
+								MatchType: "exact",
+							}},
+							Clusters: clustermap(s1),
+							RequestHeadersPolicy: &HeadersPolicy{
+								Set:    map[string]string{"X-Gateway": "contour"},
+								Remove: []string{"X-Internal"},
+							},
+						}),
+					),
+				},
+			),
+		},
+		"insert gateway with hostname owned by httpproxy": {
+			objs: []interface{}{class, proxyBank, secBank, sBank, gatewayEvil, routeSteal, secEvil, sSteal},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("bank.com", routeUpgrade("/", service(sBank))),
+					),
+				}, &Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						securevirtualhost("bank.com", secBank, routeUpgrade("/", service(sBank))),
+					),
+				},
+			),
+		},
+		"insert gateways with the same hostname in different namespaces": {
+			objs: []interface{}{class, gatewayHTTP, route1, s1, gatewayOtherNamespace, route1OtherNamespace, s1OtherNamespace},
+			want: listeners(),
+		},
+		"insert gateway outside of root namespaces": {
+			objs:           []interface{}{class, gatewayHTTP, route1, s1},
+			rootNamespaces: []string{"roots"},
+			want:           listeners(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: tc.rootNamespaces,
+					FieldLogger:    testLogger(t),
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			got := make(map[int]*Listener)
+			dag.Visit(listenerMap(got).Visit)
+
+			want := make(map[int]*Listener)
+			for _, v := range tc.want {
+				if l, ok := v.(*Listener); ok {
+					want[l.Port] = l
+				}
+			}
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDAGGatewayStatus(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	class := &serviceapis.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: serviceapis.GatewayClassSpec{
+			Controller: GatewayController,
+		},
+	}
+
+	route := func(name, path string) *serviceapis.HTTPRoute {
+		return &serviceapis.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: serviceapis.HTTPRouteSpec{
+				Hosts: []serviceapis.HTTPRouteHost{{
+					Hostnames: []string{"example.com"},
+					Rules: []serviceapis.HTTPRouteRule{{
+						Match: &serviceapis.HTTPRouteMatch{
+							PathType: serviceapis.PathTypePrefix,
+							Path:     stringptr(path),
+						},
+						Action: &serviceapis.HTTPRouteAction{
+							ForwardTo: &v1.TypedLocalObjectReference{
+								Kind: "Service",
+								Name: "kuard",
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	gateway := func(protocol string, routes ...v1.TypedLocalObjectReference) *serviceapis.Gateway {
+		return &serviceapis.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway",
+				Namespace: "default",
+			},
+			Spec: serviceapis.GatewaySpec{
+				Class: "contour",
+				Listeners: []serviceapis.Listener{{
+					Name:     "listener",
+					Protocol: stringptr(protocol),
+				}},
+				Routes: routes,
+			},
+		}
+	}
+
+	ref := func(kind, name string) v1.TypedLocalObjectReference {
+		return v1.TypedLocalObjectReference{Kind: kind, Name: name}
+	}
+
+	routeValid := route("valid", "/")
+	routeInvalid := route("invalid", "api")
+	routeWildcard := route("wildcard", "/")
+	routeWildcard.Spec.Hosts[0].Hostnames = []string{"*.example.com"}
+	routeBadWildcard := route("badwildcard", "/")
+	routeBadWildcard.Spec.Hosts[0].Hostnames = []string{"www.*.com"}
+
+	gwValid := gateway(serviceapis.HTTPProcotol, ref("HTTPRoute", "valid"))
+	gwMissingRoute := gateway(serviceapis.HTTPProcotol, ref("HTTPRoute", "missing"))
+	gwInvalidRoute := gateway(serviceapis.HTTPProcotol, ref("HTTPRoute", "invalid"))
+	gwTCPRoute := gateway(serviceapis.HTTPProcotol, ref("TcpRoute", "tcp"))
+	gwUDP := gateway("UDP", ref("HTTPRoute", "valid"))
+	gwHTTPSNoTLS := gateway(serviceapis.HTTPSProcotol, ref("HTTPRoute", "valid"))
+	gwManyErrors := gateway(serviceapis.HTTPProcotol, ref("HTTPRoute", "missing"), ref("TcpRoute", "tcp"))
+	gwWildcard := gateway(serviceapis.HTTPProcotol, ref("HTTPRoute", "wildcard"))
+	gwBadWildcard := gateway(serviceapis.HTTPProcotol, ref("HTTPRoute", "badwildcard"))
+
+	proxy := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example",
+			Namespace: "teama",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		objs           []interface{}
+		rootNamespaces []string
+		want           map[Meta]Status
+	}{
+		"valid gateway": {
+			objs: []interface{}{class, gwValid, routeValid, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwValid, Status: "valid", Description: "valid Gateway"},
+				{name: "valid", namespace: "default"}: {
+					Object:      routeValid,
+					Status:      "valid",
+					Description: "valid HTTPRoute",
+					Gateways:    []types.NamespacedName{{Name: "gateway", Namespace: "default"}},
+				},
+			},
+		},
+		"missing HTTPRoute": {
+			objs: []interface{}{class, gwMissingRoute, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwMissingRoute, Status: "invalid", Description: `HTTPRoute "missing" not found`},
+			},
+		},
+		"invalid HTTPRoute path": {
+			objs: []interface{}{class, gwInvalidRoute, routeInvalid, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwInvalidRoute, Status: "invalid", Description: `HTTPRoute "invalid": path "api" must start with /`},
+				{name: "invalid", namespace: "default"}: {Object: routeInvalid, Status: "invalid", Description: `Gateway "gateway": path "api" must start with /`},
+			},
+		},
+		"unsupported TcpRoute": {
+			objs: []interface{}{class, gwTCPRoute, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwTCPRoute, Status: "invalid", Description: `TcpRoute "tcp": TCP routes are not supported`},
+			},
+		},
+		"unsupported listener protocol": {
+			objs: []interface{}{class, gwUDP, routeValid, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwUDP, Status: "invalid", Description: `listener "listener": unsupported protocol "UDP"; Spec.Listeners must contain at least one valid listener`},
+			},
+		},
+		"https listener without tls": {
+			objs: []interface{}{class, gwHTTPSNoTLS, routeValid, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwHTTPSNoTLS, Status: "invalid", Description: `listener "listener": protocol "HTTPS" requires a TLS certificate; Spec.Listeners must contain at least one valid listener`},
+			},
+		},
+		"gateway with several errors": {
+			objs: []interface{}{class, gwManyErrors, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwManyErrors, Status: "invalid", Description: `HTTPRoute "missing" not found; TcpRoute "tcp": TCP routes are not supported`},
+			},
+		},
+		"wildcard hostname": {
+			objs: []interface{}{class, gwWildcard, routeWildcard, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwWildcard, Status: "valid", Description: "valid Gateway"},
+				{name: "wildcard", namespace: "default"}: {
+					Object:      routeWildcard,
+					Status:      "valid",
+					Description: "valid HTTPRoute",
+					Gateways:    []types.NamespacedName{{Name: "gateway", Namespace: "default"}},
+				},
+			},
+		},
+		"wildcard hostname not in leftmost label": {
+			objs: []interface{}{class, gwBadWildcard, routeBadWildcard, s1},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}:     {Object: gwBadWildcard, Status: "invalid", Description: `HTTPRoute "badwildcard": hostname "www.*.com" can only use a wildcard as its leftmost label`},
+				{name: "badwildcard", namespace: "default"}: {Object: routeBadWildcard, Status: "invalid", Description: `Gateway "gateway": hostname "www.*.com" can only use a wildcard as its leftmost label`},
+			},
+		},
+		"hostname owned by httpproxy": {
+			objs: []interface{}{class, gwValid, routeValid, s1, proxy},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}:        {Object: gwValid, Status: "invalid", Description: `HTTPRoute "valid": hostname "example.com" is already used by HTTPProxy teama/example`},
+				{name: "valid", namespace: "default"}:          {Object: routeValid, Status: "invalid", Description: `Gateway "gateway": hostname "example.com" is already used by HTTPProxy teama/example`},
+				{name: proxy.Name, namespace: proxy.Namespace}: {Object: proxy, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"gateway outside of root namespaces": {
+			objs:           []interface{}{class, gwValid, routeValid, s1},
+			rootNamespaces: []string{"roots"},
+			want: map[Meta]Status{
+				{name: "gateway", namespace: "default"}: {Object: gwValid, Status: "invalid", Description: "root Gateway cannot be defined in this namespace"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: tc.rootNamespaces,
+					FieldLogger:    testLogger(t),
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+			got := dag.Statuses()
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func stringptr(s string) *string { return &s }
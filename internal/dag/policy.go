@@ -15,6 +15,7 @@ package dag
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
@@ -147,6 +148,34 @@ func timeoutPolicy(tp *projcontour.TimeoutPolicy) *TimeoutPolicy {
 		IdleTimeout:     parseTimeout(tp.Idle),
 	}
 }
+
+// tcpProxyTimeoutPolicy builds a TCPProxyTimeoutPolicy from the
+// supplied timeout configuration.
+func tcpProxyTimeoutPolicy(tp *projcontour.TCPProxyTimeoutPolicy) (*TCPProxyTimeoutPolicy, error) {
+	if tp == nil {
+		return nil, nil
+	}
+
+	idle := time.Duration(-1)
+	if tp.Idle != "infinity" {
+		var err error
+		idle, err = positiveDuration("idle timeout", tp.Idle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	connect, err := positiveDuration("connect timeout", tp.Connect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCPProxyTimeoutPolicy{
+		IdleTimeout:    idle,
+		ConnectTimeout: connect,
+	}, nil
+}
+
 func ingressrouteHealthCheckPolicy(hc *ingressroutev1.HealthCheck) *HTTPHealthCheckPolicy {
 	if hc == nil {
 		return nil
@@ -187,6 +216,102 @@ func tcpHealthCheckPolicy(hc *projcontour.TCPHealthCheckPolicy) *TCPHealthCheckP
 	}
 }
 
+// outlierDetectionPolicy builds an OutlierDetectionPolicy from the
+// supplied outlier detection configuration.
+func outlierDetectionPolicy(in *projcontour.OutlierDetection) (*OutlierDetectionPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	if in.MaxEjectionPercent > 100 {
+		return nil, fmt.Errorf("maxEjectionPercent %d must be no more than 100", in.MaxEjectionPercent)
+	}
+
+	interval, err := positiveDuration("interval", in.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEjectionTime, err := positiveDuration("baseEjectionTime", in.BaseEjectionTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutlierDetectionPolicy{
+		ConsecutiveServerErrors:  in.ConsecutiveServerErrors,
+		ConsecutiveGatewayErrors: in.ConsecutiveGatewayErrors,
+		Interval:                 interval,
+		BaseEjectionTime:         baseEjectionTime,
+		MaxEjectionPercent:       in.MaxEjectionPercent,
+	}, nil
+}
+
+// circuitBreakerPolicy builds a CircuitBreakerPolicy from the supplied
+// circuit breaker configuration. Unset thresholds are taken from the
+// circuit breaking annotations of the upstream service.
+func circuitBreakerPolicy(s *Service, in *projcontour.CircuitBreakers) (*CircuitBreakerPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	maxConnections, err := circuitBreakerThreshold("maxConnections", in.MaxConnections, s.MaxConnections)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPendingRequests, err := circuitBreakerThreshold("maxPendingRequests", in.MaxPendingRequests, s.MaxPendingRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRequests, err := circuitBreakerThreshold("maxRequests", in.MaxRequests, s.MaxRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRetries, err := circuitBreakerThreshold("maxRetries", in.MaxRetries, s.MaxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CircuitBreakerPolicy{
+		MaxConnections:     maxConnections,
+		MaxPendingRequests: maxPendingRequests,
+		MaxRequests:        maxRequests,
+		MaxRetries:         maxRetries,
+	}, nil
+}
+
+// circuitBreakerThreshold validates the named threshold, returning
+// the fallback value if the threshold is unset.
+func circuitBreakerThreshold(field string, value int64, fallback uint32) (uint32, error) {
+	switch {
+	case value == 0:
+		return fallback, nil
+	case value < 0 || value > math.MaxUint32:
+		return 0, fmt.Errorf("%s %d must be in the range 1-%d", field, value, uint32(math.MaxUint32))
+	default:
+		return uint32(value), nil
+	}
+}
+
+// positiveDuration parses the named duration field, returning zero
+// if the field is empty.
+func positiveDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %s", field, value, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s %q must be positive", field, value)
+	}
+	return d, nil
+}
+
 // loadBalancerPolicy returns the load balancer strategy or
 // blank if no valid strategy is supplied.
 func loadBalancerPolicy(lbp *projcontour.LoadBalancerPolicy) string {
@@ -200,11 +325,93 @@ func loadBalancerPolicy(lbp *projcontour.LoadBalancerPolicy) string {
 		return "Random"
 	case "Cookie":
 		return "Cookie"
+	case "RequestHash":
+		return "RequestHash"
 	default:
 		return ""
 	}
 }
 
+// requestHashPolicies returns the hash policies of the supplied load
+// balancer policy, or nil if the policy does not use the RequestHash
+// strategy.
+func requestHashPolicies(lbp *projcontour.LoadBalancerPolicy) ([]RequestHashPolicy, error) {
+	if lbp == nil {
+		return nil, nil
+	}
+
+	if lbp.Strategy != "RequestHash" {
+		if len(lbp.RequestHashPolicies) > 0 {
+			return nil, fmt.Errorf("requestHashPolicies may only be used with the RequestHash strategy")
+		}
+		return nil, nil
+	}
+
+	if len(lbp.RequestHashPolicies) == 0 {
+		return nil, fmt.Errorf("the RequestHash strategy requires at least one requestHashPolicy")
+	}
+
+	var policies []RequestHashPolicy
+	for i, in := range lbp.RequestHashPolicies {
+		p := RequestHashPolicy{
+			Terminal:     in.Terminal,
+			HashSourceIP: in.HashSourceIP,
+		}
+
+		sources := 0
+		if in.HashSourceIP {
+			sources++
+		}
+		if in.HeaderHashOptions != nil {
+			sources++
+			if in.HeaderHashOptions.HeaderName == "" {
+				return nil, fmt.Errorf("requestHashPolicies[%d]: headerName must be set", i)
+			}
+			p.HeaderHashOptions = &HeaderHashOptions{
+				HeaderName: in.HeaderHashOptions.HeaderName,
+			}
+		}
+		if in.CookieHashOptions != nil {
+			sources++
+			if in.CookieHashOptions.CookieName == "" {
+				return nil, fmt.Errorf("requestHashPolicies[%d]: cookieName must be set", i)
+			}
+			var ttl time.Duration
+			if in.CookieHashOptions.TTL != "" {
+				d, err := time.ParseDuration(in.CookieHashOptions.TTL)
+				if err != nil {
+					return nil, fmt.Errorf("requestHashPolicies[%d]: invalid ttl %q: %s", i, in.CookieHashOptions.TTL, err)
+				}
+				if d < 0 {
+					return nil, fmt.Errorf("requestHashPolicies[%d]: ttl %q must not be negative", i, in.CookieHashOptions.TTL)
+				}
+				ttl = d
+			}
+			p.CookieHashOptions = &CookieHashOptions{
+				CookieName: in.CookieHashOptions.CookieName,
+				TTL:        ttl,
+				Path:       in.CookieHashOptions.Path,
+			}
+		}
+		if in.QueryParameterHashOptions != nil {
+			sources++
+			if in.QueryParameterHashOptions.ParameterName == "" {
+				return nil, fmt.Errorf("requestHashPolicies[%d]: parameterName must be set", i)
+			}
+			p.QueryParameterHashOptions = &QueryParameterHashOptions{
+				ParameterName: in.QueryParameterHashOptions.ParameterName,
+			}
+		}
+		if sources != 1 {
+			return nil, fmt.Errorf("requestHashPolicies[%d]: exactly one of headerHashOptions, cookieHashOptions, queryParameterHashOptions or hashSourceIP must be set", i)
+		}
+
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
 func parseTimeout(timeout string) time.Duration {
 	if timeout == "" {
 		// Blank is interpreted as no timeout specified, use envoy defaults
@@ -238,6 +445,239 @@ func max(a, b uint32) uint32 {
 	return b
 }
 
+func rateLimitPolicy(in *projcontour.RateLimitPolicy) (*RateLimitPolicy, error) {
+	if in == nil || (in.Local == nil && in.Global == nil) {
+		return nil, nil
+	}
+
+	local, err := localRateLimitPolicy(in.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	global, err := globalRateLimitPolicy(in.Global)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateLimitPolicy{
+		Local:  local,
+		Global: global,
+	}, nil
+}
+
+func localRateLimitPolicy(in *projcontour.LocalRateLimitPolicy) (*LocalRateLimitPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	if in.Requests == 0 {
+		return nil, fmt.Errorf("local.requests must be greater than 0")
+	}
+
+	var fillInterval time.Duration
+	switch in.Unit {
+	case "second":
+		fillInterval = time.Second
+	case "minute":
+		fillInterval = time.Minute
+	case "hour":
+		fillInterval = time.Hour
+	default:
+		return nil, fmt.Errorf("local.unit must be second, minute or hour")
+	}
+
+	if in.ResponseStatusCode > 0 && (in.ResponseStatusCode < 400 || in.ResponseStatusCode > 599) {
+		return nil, fmt.Errorf("local.responseStatusCode must be in the 400-599 range")
+	}
+
+	return &LocalRateLimitPolicy{
+		MaxTokens:          in.Requests + in.Burst,
+		TokensPerFill:      in.Requests,
+		FillInterval:       fillInterval,
+		ResponseStatusCode: in.ResponseStatusCode,
+	}, nil
+}
+
+func globalRateLimitPolicy(in *projcontour.GlobalRateLimitPolicy) (*GlobalRateLimitPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	global := &GlobalRateLimitPolicy{}
+	for _, d := range in.Descriptors {
+		var rld RateLimitDescriptor
+
+		for _, entry := range d.Entries {
+			// Exactly one descriptor entry type must be set.
+			set := 0
+			if entry.GenericKey != nil {
+				set++
+			}
+			if entry.RequestHeader != nil {
+				set++
+			}
+			if entry.RemoteAddress != nil {
+				set++
+			}
+			if set != 1 {
+				return nil, fmt.Errorf("rate limit descriptor entry must have exactly one field set")
+			}
+
+			switch {
+			case entry.GenericKey != nil:
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					GenericKey: &GenericKeyDescriptorEntry{
+						Value: entry.GenericKey.Value,
+					},
+				})
+			case entry.RequestHeader != nil:
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					HeaderMatch: &HeaderMatchDescriptorEntry{
+						HeaderName: entry.RequestHeader.HeaderName,
+						Key:        entry.RequestHeader.DescriptorKey,
+					},
+				})
+			case entry.RemoteAddress != nil:
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					RemoteAddress: &RemoteAddressDescriptorEntry{},
+				})
+			}
+		}
+
+		global.Descriptors = append(global.Descriptors, &rld)
+	}
+
+	return global, nil
+}
+
+func corsPolicy(in *projcontour.CORSPolicy) (*CORSPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	if len(in.AllowOrigin) == 0 {
+		return nil, fmt.Errorf("allowOrigin must have at least one entry")
+	}
+	if len(in.AllowMethods) == 0 {
+		return nil, fmt.Errorf("allowMethods must have at least one entry")
+	}
+
+	var maxAge time.Duration
+	if in.MaxAge != "" {
+		var err error
+		maxAge, err = time.ParseDuration(in.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxAge %q: %s", in.MaxAge, err)
+		}
+		if maxAge <= 0 {
+			return nil, fmt.Errorf("maxAge %q must be positive", in.MaxAge)
+		}
+	}
+
+	return &CORSPolicy{
+		AllowCredentials: in.AllowCredentials,
+		AllowOrigin:      in.AllowOrigin,
+		AllowMethods:     corsHeaderValues(in.AllowMethods),
+		AllowHeaders:     corsHeaderValues(in.AllowHeaders),
+		ExposeHeaders:    corsHeaderValues(in.ExposeHeaders),
+		MaxAge:           maxAge,
+	}, nil
+}
+
+func corsHeaderValues(values []projcontour.CORSHeaderValue) []string {
+	var out []string
+	for _, v := range values {
+		out = append(out, string(v))
+	}
+	return out
+}
+
+// tracingPolicy builds a TracingPolicy from the supplied virtual host
+// tracing configuration.
+func tracingPolicy(in *projcontour.TracingPolicy) (*TracingPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	if in.RandomSampling > 100 {
+		return nil, fmt.Errorf("randomSampling %d must be no more than 100", in.RandomSampling)
+	}
+	if in.Disabled && in.RandomSampling > 0 {
+		return nil, fmt.Errorf("randomSampling cannot be combined with disabled")
+	}
+
+	return &TracingPolicy{
+		Disabled:       in.Disabled,
+		RandomSampling: in.RandomSampling,
+	}, nil
+}
+
+// redirectPolicy builds a Redirect from the supplied request redirect
+// policy, defaulting the status code to 302.
+func redirectPolicy(in *projcontour.HTTPRequestRedirectPolicy) (*Redirect, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	switch in.Scheme {
+	case "", "http", "https":
+	default:
+		return nil, fmt.Errorf("scheme %q must be http or https", in.Scheme)
+	}
+
+	if in.Port < 0 || in.Port > 65535 {
+		return nil, fmt.Errorf("port must be in the range 1-65535")
+	}
+
+	statusCode := in.StatusCode
+	switch statusCode {
+	case 0:
+		statusCode = http.StatusFound
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, fmt.Errorf("statusCode %d must be one of 301, 302, 307 or 308", in.StatusCode)
+	}
+
+	if in.Path != "" && in.Prefix != "" {
+		return nil, fmt.Errorf("only one of path or prefix may be specified")
+	}
+
+	return &Redirect{
+		Scheme:        in.Scheme,
+		Hostname:      in.Hostname,
+		PortNumber:    uint32(in.Port),
+		StatusCode:    statusCode,
+		PathRewrite:   in.Path,
+		PrefixRewrite: in.Prefix,
+	}, nil
+}
+
+// maxDirectResponseBodySize is Envoy's default limit on the
+// size of a direct response body.
+const maxDirectResponseBodySize = 4096
+
+// directResponsePolicy builds a DirectResponse from the supplied
+// direct response policy.
+func directResponsePolicy(in *projcontour.HTTPDirectResponsePolicy) (*DirectResponse, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	if in.StatusCode < 200 || in.StatusCode > 599 {
+		return nil, fmt.Errorf("statusCode %d must be in the 200-599 range", in.StatusCode)
+	}
+
+	if len(in.Body) > maxDirectResponseBodySize {
+		return nil, fmt.Errorf("body must be no more than %d bytes", maxDirectResponseBodySize)
+	}
+
+	return &DirectResponse{
+		StatusCode: uint32(in.StatusCode),
+		Body:       in.Body,
+	}, nil
+}
+
 func prefixReplacementsAreValid(replacements []projcontour.ReplacePrefix) error {
 	prefixes := map[string]bool{}
 
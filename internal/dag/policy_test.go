@@ -14,6 +14,7 @@
 package dag
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -371,6 +372,12 @@ func TestLoadBalancerPolicy(t *testing.T) {
 			},
 			want: "Cookie",
 		},
+		"RequestHash": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+			},
+			want: "RequestHash",
+		},
 		"unknown": {
 			lbp: &projcontour.LoadBalancerPolicy{
 				Strategy: "please",
@@ -387,6 +394,140 @@ func TestLoadBalancerPolicy(t *testing.T) {
 	}
 }
 
+func TestRequestHashPolicies(t *testing.T) {
+	tests := map[string]struct {
+		lbp     *projcontour.LoadBalancerPolicy
+		want    []RequestHashPolicy
+		wantErr string
+	}{
+		"nil": {
+			lbp:  nil,
+			want: nil,
+		},
+		"other strategy": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "Cookie",
+			},
+			want: nil,
+		},
+		"all hash sources": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+				RequestHashPolicies: []projcontour.RequestHashPolicy{{
+					Terminal: true,
+					HeaderHashOptions: &projcontour.HeaderHashOptions{
+						HeaderName: "X-Tenant-Id",
+					},
+				}, {
+					CookieHashOptions: &projcontour.CookieHashOptions{
+						CookieName: "session",
+						TTL:        "1h",
+						Path:       "/",
+					},
+				}, {
+					QueryParameterHashOptions: &projcontour.QueryParameterHashOptions{
+						ParameterName: "tenant",
+					},
+				}, {
+					HashSourceIP: true,
+				}},
+			},
+			want: []RequestHashPolicy{{
+				Terminal: true,
+				HeaderHashOptions: &HeaderHashOptions{
+					HeaderName: "X-Tenant-Id",
+				},
+			}, {
+				CookieHashOptions: &CookieHashOptions{
+					CookieName: "session",
+					TTL:        time.Hour,
+					Path:       "/",
+				},
+			}, {
+				QueryParameterHashOptions: &QueryParameterHashOptions{
+					ParameterName: "tenant",
+				},
+			}, {
+				HashSourceIP: true,
+			}},
+		},
+		"policies without RequestHash strategy": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "Random",
+				RequestHashPolicies: []projcontour.RequestHashPolicy{{
+					HashSourceIP: true,
+				}},
+			},
+			wantErr: "requestHashPolicies may only be used with the RequestHash strategy",
+		},
+		"RequestHash strategy without policies": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+			},
+			wantErr: "the RequestHash strategy requires at least one requestHashPolicy",
+		},
+		"more than one hash source": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+				RequestHashPolicies: []projcontour.RequestHashPolicy{{
+					HashSourceIP: true,
+					HeaderHashOptions: &projcontour.HeaderHashOptions{
+						HeaderName: "X-Tenant-Id",
+					},
+				}},
+			},
+			wantErr: "requestHashPolicies[0]: exactly one of headerHashOptions, cookieHashOptions, queryParameterHashOptions or hashSourceIP must be set",
+		},
+		"missing header name": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+				RequestHashPolicies: []projcontour.RequestHashPolicy{{
+					HashSourceIP: true,
+				}, {
+					HeaderHashOptions: &projcontour.HeaderHashOptions{},
+				}},
+			},
+			wantErr: "requestHashPolicies[1]: headerName must be set",
+		},
+		"invalid cookie ttl": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+				RequestHashPolicies: []projcontour.RequestHashPolicy{{
+					CookieHashOptions: &projcontour.CookieHashOptions{
+						CookieName: "session",
+						TTL:        "forever",
+					},
+				}},
+			},
+			wantErr: `requestHashPolicies[0]: invalid ttl "forever": time: invalid duration "forever"`,
+		},
+		"negative cookie ttl": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RequestHash",
+				RequestHashPolicies: []projcontour.RequestHashPolicy{{
+					CookieHashOptions: &projcontour.CookieHashOptions{
+						CookieName: "session",
+						TTL:        "-1h",
+					},
+				}},
+			},
+			wantErr: `requestHashPolicies[0]: ttl "-1h" must not be negative`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := requestHashPolicies(tc.lbp)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestParseTimeout(t *testing.T) {
 	tests := map[string]struct {
 		duration string
@@ -417,3 +558,647 @@ func TestParseTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimitPolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.RateLimitPolicy
+		want    *RateLimitPolicy
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"no local or global": {
+			in:   &projcontour.RateLimitPolicy{},
+			want: nil,
+		},
+		"local with requests, unit and burst": {
+			in: &projcontour.RateLimitPolicy{
+				Local: &projcontour.LocalRateLimitPolicy{
+					Requests: 10,
+					Unit:     "minute",
+					Burst:    5,
+				},
+			},
+			want: &RateLimitPolicy{
+				Local: &LocalRateLimitPolicy{
+					MaxTokens:     15,
+					TokensPerFill: 10,
+					FillInterval:  time.Minute,
+				},
+			},
+		},
+		"local with response status code": {
+			in: &projcontour.RateLimitPolicy{
+				Local: &projcontour.LocalRateLimitPolicy{
+					Requests:           1,
+					Unit:               "second",
+					ResponseStatusCode: 503,
+				},
+			},
+			want: &RateLimitPolicy{
+				Local: &LocalRateLimitPolicy{
+					MaxTokens:          1,
+					TokensPerFill:      1,
+					FillInterval:       time.Second,
+					ResponseStatusCode: 503,
+				},
+			},
+		},
+		"local with no requests": {
+			in: &projcontour.RateLimitPolicy{
+				Local: &projcontour.LocalRateLimitPolicy{
+					Unit: "hour",
+				},
+			},
+			wantErr: "local.requests must be greater than 0",
+		},
+		"local with invalid unit": {
+			in: &projcontour.RateLimitPolicy{
+				Local: &projcontour.LocalRateLimitPolicy{
+					Requests: 10,
+					Unit:     "day",
+				},
+			},
+			wantErr: "local.unit must be second, minute or hour",
+		},
+		"local with invalid response status code": {
+			in: &projcontour.RateLimitPolicy{
+				Local: &projcontour.LocalRateLimitPolicy{
+					Requests:           10,
+					Unit:               "second",
+					ResponseStatusCode: 200,
+				},
+			},
+			wantErr: "local.responseStatusCode must be in the 400-599 range",
+		},
+		"generic key, request header and remote address entries": {
+			in: &projcontour.RateLimitPolicy{
+				Global: &projcontour.GlobalRateLimitPolicy{
+					Descriptors: []projcontour.RateLimitDescriptor{
+						{
+							Entries: []projcontour.RateLimitDescriptorEntry{
+								{
+									GenericKey: &projcontour.GenericKeyDescriptor{
+										Value: "generic-key-value",
+									},
+								},
+								{
+									RequestHeader: &projcontour.RequestHeaderDescriptor{
+										HeaderName:    "X-Header",
+										DescriptorKey: "header-key",
+									},
+								},
+							},
+						},
+						{
+							Entries: []projcontour.RateLimitDescriptorEntry{
+								{
+									RemoteAddress: &projcontour.RemoteAddressDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Global: &GlobalRateLimitPolicy{
+					Descriptors: []*RateLimitDescriptor{
+						{
+							Entries: []RateLimitDescriptorEntry{
+								{
+									GenericKey: &GenericKeyDescriptorEntry{
+										Value: "generic-key-value",
+									},
+								},
+								{
+									HeaderMatch: &HeaderMatchDescriptorEntry{
+										HeaderName: "X-Header",
+										Key:        "header-key",
+									},
+								},
+							},
+						},
+						{
+							Entries: []RateLimitDescriptorEntry{
+								{
+									RemoteAddress: &RemoteAddressDescriptorEntry{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"entry with more than one field set": {
+			in: &projcontour.RateLimitPolicy{
+				Global: &projcontour.GlobalRateLimitPolicy{
+					Descriptors: []projcontour.RateLimitDescriptor{
+						{
+							Entries: []projcontour.RateLimitDescriptorEntry{
+								{
+									GenericKey: &projcontour.GenericKeyDescriptor{
+										Value: "generic-key-value",
+									},
+									RemoteAddress: &projcontour.RemoteAddressDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "rate limit descriptor entry must have exactly one field set",
+		},
+		"entry with no fields set": {
+			in: &projcontour.RateLimitPolicy{
+				Global: &projcontour.GlobalRateLimitPolicy{
+					Descriptors: []projcontour.RateLimitDescriptor{
+						{
+							Entries: []projcontour.RateLimitDescriptorEntry{{}},
+						},
+					},
+				},
+			},
+			wantErr: "rate limit descriptor entry must have exactly one field set",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := rateLimitPolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCORSPolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.CORSPolicy
+		want    *CORSPolicy
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"all fields": {
+			in: &projcontour.CORSPolicy{
+				AllowCredentials: true,
+				AllowOrigin:      []string{"*"},
+				AllowMethods:     []projcontour.CORSHeaderValue{"GET", "POST", "OPTIONS"},
+				AllowHeaders:     []projcontour.CORSHeaderValue{"authorization", "cache-control"},
+				ExposeHeaders:    []projcontour.CORSHeaderValue{"Content-Length"},
+				MaxAge:           "10m",
+			},
+			want: &CORSPolicy{
+				AllowCredentials: true,
+				AllowOrigin:      []string{"*"},
+				AllowMethods:     []string{"GET", "POST", "OPTIONS"},
+				AllowHeaders:     []string{"authorization", "cache-control"},
+				ExposeHeaders:    []string{"Content-Length"},
+				MaxAge:           10 * time.Minute,
+			},
+		},
+		"no max age": {
+			in: &projcontour.CORSPolicy{
+				AllowOrigin:  []string{"https://www.example.com"},
+				AllowMethods: []projcontour.CORSHeaderValue{"GET"},
+			},
+			want: &CORSPolicy{
+				AllowOrigin:  []string{"https://www.example.com"},
+				AllowMethods: []string{"GET"},
+			},
+		},
+		"no allowed origins": {
+			in: &projcontour.CORSPolicy{
+				AllowMethods: []projcontour.CORSHeaderValue{"GET"},
+			},
+			wantErr: "allowOrigin must have at least one entry",
+		},
+		"no allowed methods": {
+			in: &projcontour.CORSPolicy{
+				AllowOrigin: []string{"*"},
+			},
+			wantErr: "allowMethods must have at least one entry",
+		},
+		"invalid max age": {
+			in: &projcontour.CORSPolicy{
+				AllowOrigin:  []string{"*"},
+				AllowMethods: []projcontour.CORSHeaderValue{"GET"},
+				MaxAge:       "forever",
+			},
+			wantErr: `invalid maxAge "forever": time: invalid duration "forever"`,
+		},
+		"negative max age": {
+			in: &projcontour.CORSPolicy{
+				AllowOrigin:  []string{"*"},
+				AllowMethods: []projcontour.CORSHeaderValue{"GET"},
+				MaxAge:       "-10s",
+			},
+			wantErr: `maxAge "-10s" must be positive`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := corsPolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.HTTPRequestRedirectPolicy
+		want    *Redirect
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"default status code": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				Hostname: "new.example.com",
+			},
+			want: &Redirect{
+				Hostname:   "new.example.com",
+				StatusCode: 302,
+			},
+		},
+		"all fields": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				Scheme:     "https",
+				Hostname:   "new.example.com",
+				Port:       8443,
+				StatusCode: 301,
+				Path:       "/landing",
+			},
+			want: &Redirect{
+				Scheme:      "https",
+				Hostname:    "new.example.com",
+				PortNumber:  8443,
+				StatusCode:  301,
+				PathRewrite: "/landing",
+			},
+		},
+		"prefix": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				StatusCode: 308,
+				Prefix:     "/v2",
+			},
+			want: &Redirect{
+				StatusCode:    308,
+				PrefixRewrite: "/v2",
+			},
+		},
+		"invalid scheme": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				Scheme: "ftp",
+			},
+			wantErr: `scheme "ftp" must be http or https`,
+		},
+		"invalid port": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				Port: 65536,
+			},
+			wantErr: "port must be in the range 1-65535",
+		},
+		"invalid status code": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				StatusCode: 303,
+			},
+			wantErr: "statusCode 303 must be one of 301, 302, 307 or 308",
+		},
+		"path and prefix": {
+			in: &projcontour.HTTPRequestRedirectPolicy{
+				Path:   "/landing",
+				Prefix: "/v2",
+			},
+			wantErr: "only one of path or prefix may be specified",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := redirectPolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDirectResponsePolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.HTTPDirectResponsePolicy
+		want    *DirectResponse
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"status code only": {
+			in: &projcontour.HTTPDirectResponsePolicy{
+				StatusCode: 410,
+			},
+			want: &DirectResponse{
+				StatusCode: 410,
+			},
+		},
+		"status code and body": {
+			in: &projcontour.HTTPDirectResponsePolicy{
+				StatusCode: 200,
+				Body:       "User-agent: *\nDisallow: /\n",
+			},
+			want: &DirectResponse{
+				StatusCode: 200,
+				Body:       "User-agent: *\nDisallow: /\n",
+			},
+		},
+		"invalid status code": {
+			in: &projcontour.HTTPDirectResponsePolicy{
+				StatusCode: 100,
+			},
+			wantErr: "statusCode 100 must be in the 200-599 range",
+		},
+		"body too large": {
+			in: &projcontour.HTTPDirectResponsePolicy{
+				StatusCode: 503,
+				Body:       strings.Repeat("a", 4097),
+			},
+			wantErr: "body must be no more than 4096 bytes",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := directResponsePolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestOutlierDetectionPolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.OutlierDetection
+		want    *OutlierDetectionPolicy
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"defaults": {
+			in:   &projcontour.OutlierDetection{},
+			want: &OutlierDetectionPolicy{},
+		},
+		"all fields": {
+			in: &projcontour.OutlierDetection{
+				ConsecutiveServerErrors:  5,
+				ConsecutiveGatewayErrors: 3,
+				Interval:                 "10s",
+				BaseEjectionTime:         "30s",
+				MaxEjectionPercent:       50,
+			},
+			want: &OutlierDetectionPolicy{
+				ConsecutiveServerErrors:  5,
+				ConsecutiveGatewayErrors: 3,
+				Interval:                 10 * time.Second,
+				BaseEjectionTime:         30 * time.Second,
+				MaxEjectionPercent:       50,
+			},
+		},
+		"max ejection percent too large": {
+			in: &projcontour.OutlierDetection{
+				MaxEjectionPercent: 101,
+			},
+			wantErr: "maxEjectionPercent 101 must be no more than 100",
+		},
+		"invalid interval": {
+			in: &projcontour.OutlierDetection{
+				Interval: "ten seconds",
+			},
+			wantErr: `invalid interval "ten seconds": time: invalid duration "ten seconds"`,
+		},
+		"negative base ejection time": {
+			in: &projcontour.OutlierDetection{
+				BaseEjectionTime: "-1s",
+			},
+			wantErr: `baseEjectionTime "-1s" must be positive`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := outlierDetectionPolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCircuitBreakerPolicy(t *testing.T) {
+	s := &Service{
+		MaxConnections: 9000,
+		MaxRetries:     7,
+	}
+
+	tests := map[string]struct {
+		in      *projcontour.CircuitBreakers
+		want    *CircuitBreakerPolicy
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"empty takes service annotations": {
+			in: &projcontour.CircuitBreakers{},
+			want: &CircuitBreakerPolicy{
+				MaxConnections: 9000,
+				MaxRetries:     7,
+			},
+		},
+		"overrides service annotations": {
+			in: &projcontour.CircuitBreakers{
+				MaxConnections:     100,
+				MaxPendingRequests: 20,
+				MaxRequests:        200,
+			},
+			want: &CircuitBreakerPolicy{
+				MaxConnections:     100,
+				MaxPendingRequests: 20,
+				MaxRequests:        200,
+				MaxRetries:         7,
+			},
+		},
+		"negative threshold": {
+			in: &projcontour.CircuitBreakers{
+				MaxRequests: -1,
+			},
+			wantErr: "maxRequests -1 must be in the range 1-4294967295",
+		},
+		"threshold too large": {
+			in: &projcontour.CircuitBreakers{
+				MaxRetries: 1 << 32,
+			},
+			wantErr: "maxRetries 4294967296 must be in the range 1-4294967295",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := circuitBreakerPolicy(s, tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTCPProxyTimeoutPolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.TCPProxyTimeoutPolicy
+		want    *TCPProxyTimeoutPolicy
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"defaults": {
+			in:   &projcontour.TCPProxyTimeoutPolicy{},
+			want: &TCPProxyTimeoutPolicy{},
+		},
+		"idle and connect": {
+			in: &projcontour.TCPProxyTimeoutPolicy{
+				Idle:    "2h",
+				Connect: "1s",
+			},
+			want: &TCPProxyTimeoutPolicy{
+				IdleTimeout:    2 * time.Hour,
+				ConnectTimeout: time.Second,
+			},
+		},
+		"infinite idle": {
+			in: &projcontour.TCPProxyTimeoutPolicy{
+				Idle: "infinity",
+			},
+			want: &TCPProxyTimeoutPolicy{
+				IdleTimeout: -1,
+			},
+		},
+		"invalid idle": {
+			in: &projcontour.TCPProxyTimeoutPolicy{
+				Idle: "forever",
+			},
+			wantErr: `invalid idle timeout "forever": time: invalid duration "forever"`,
+		},
+		"infinite connect": {
+			in: &projcontour.TCPProxyTimeoutPolicy{
+				Connect: "infinity",
+			},
+			wantErr: `invalid connect timeout "infinity": time: invalid duration "infinity"`,
+		},
+		"zero connect": {
+			in: &projcontour.TCPProxyTimeoutPolicy{
+				Connect: "0s",
+			},
+			wantErr: `connect timeout "0s" must be positive`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tcpProxyTimeoutPolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTracingPolicy(t *testing.T) {
+	tests := map[string]struct {
+		in      *projcontour.TracingPolicy
+		want    *TracingPolicy
+		wantErr string
+	}{
+		"nil": {
+			in:   nil,
+			want: nil,
+		},
+		"disabled": {
+			in: &projcontour.TracingPolicy{
+				Disabled: true,
+			},
+			want: &TracingPolicy{
+				Disabled: true,
+			},
+		},
+		"random sampling": {
+			in: &projcontour.TracingPolicy{
+				RandomSampling: 5,
+			},
+			want: &TracingPolicy{
+				RandomSampling: 5,
+			},
+		},
+		"random sampling too large": {
+			in: &projcontour.TracingPolicy{
+				RandomSampling: 101,
+			},
+			wantErr: "randomSampling 101 must be no more than 100",
+		},
+		"disabled with random sampling": {
+			in: &projcontour.TracingPolicy{
+				Disabled:       true,
+				RandomSampling: 5,
+			},
+			wantErr: "randomSampling cannot be combined with disabled",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tracingPolicy(tc.in)
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -20,6 +20,8 @@ import (
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/k8s"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
 )
 
 // Status contains the status for an IngressRoute (valid / invalid / orphan, etc)
@@ -28,6 +30,9 @@ type Status struct {
 	Status      string
 	Description string
 	Vhost       string
+
+	// Gateways are the Gateways which admitted an HTTPRoute.
+	Gateways []types.NamespacedName
 }
 
 type StatusWriter struct {
@@ -39,9 +44,10 @@ type Object interface {
 }
 
 type ObjectStatusWriter struct {
-	sw     *StatusWriter
-	obj    Object
-	values map[string]string
+	sw       *StatusWriter
+	obj      Object
+	values   map[string]string
+	gateways []types.NamespacedName
 }
 
 // WithObject returns an ObjectStatusWriter that can be used to set the state of
@@ -79,6 +85,7 @@ func (sw *StatusWriter) commit(osw *ObjectStatusWriter) {
 			Status:      osw.values["status"],
 			Description: osw.values["description"],
 			Vhost:       osw.values["vhost"],
+			Gateways:    osw.gateways,
 		}
 	}
 }
@@ -87,6 +94,16 @@ func (osw *ObjectStatusWriter) WithValue(key, val string) *ObjectStatusWriter {
 	return osw
 }
 
+// WithGateway records that the Gateway admitted the object, which
+// must be an HTTPRoute.
+func (osw *ObjectStatusWriter) WithGateway(gw Object) *ObjectStatusWriter {
+	osw.gateways = append(osw.gateways, types.NamespacedName{
+		Namespace: gw.GetObjectMeta().GetNamespace(),
+		Name:      gw.GetObjectMeta().GetName(),
+	})
+	return osw
+}
+
 func (osw *ObjectStatusWriter) SetInvalid(format string, args ...interface{}) {
 	osw.WithValue("description", fmt.Sprintf(format, args...)).WithValue("status", k8s.StatusInvalid)
 }
@@ -97,6 +114,10 @@ func (osw *ObjectStatusWriter) SetValid() {
 		osw.WithValue("description", "valid HTTPProxy").WithValue("status", k8s.StatusValid)
 	case *ingressroutev1.IngressRoute:
 		osw.WithValue("description", "valid IngressRoute").WithValue("status", k8s.StatusValid)
+	case *serviceapis.Gateway:
+		osw.WithValue("description", "valid Gateway").WithValue("status", k8s.StatusValid)
+	case *serviceapis.HTTPRoute:
+		osw.WithValue("description", "valid HTTPRoute").WithValue("status", k8s.StatusValid)
 	default:
 		// not a supported type
 	}
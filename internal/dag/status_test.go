@@ -1884,9 +1884,1096 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		},
 	}
 
+	// proxy50 requires client validation using a missing CA secret
+	proxy50 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client-validation",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "client-validation.example.com",
+				TLS: &projcontour.TLS{
+					SecretName: sec1.Name,
+					ClientValidation: &projcontour.DownstreamValidation{
+						CACertificate: "missing",
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy51 combines tls passthrough with client validation
+	proxy51 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client-validation-passthrough",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "client-validation.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+					ClientValidation: &projcontour.DownstreamValidation{
+						CACertificate: "ca",
+					},
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// proxy52 has a rate limit descriptor entry with more than one field set
+	proxy52 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-ratelimit",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "ratelimit.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+				RateLimitPolicy: &projcontour.RateLimitPolicy{
+					Global: &projcontour.GlobalRateLimitPolicy{
+						Descriptors: []projcontour.RateLimitDescriptor{{
+							Entries: []projcontour.RateLimitDescriptorEntry{{
+								GenericKey: &projcontour.GenericKeyDescriptor{
+									Value: "generic-key-value",
+								},
+								RemoteAddress: &projcontour.RemoteAddressDescriptor{},
+							}},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	// proxy53 has a local rate limit policy with an invalid unit
+	proxy53 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-local-ratelimit",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "local-ratelimit.example.com",
+				RateLimitPolicy: &projcontour.RateLimitPolicy{
+					Local: &projcontour.LocalRateLimitPolicy{
+						Requests: 100,
+						Unit:     "fortnight",
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy54 configures authorization without TLS
+	proxy54 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "authorization-without-tls",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "authorization.example.com",
+				Authorization: &projcontour.AuthorizationServer{
+					ServiceRef: projcontour.ExtensionServiceReference{
+						Name: s1.Name,
+						Port: 8080,
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy55 configures authorization with a route that permits
+	// insecure requests without disabling authorization
+	proxy55 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "authorization-permit-insecure",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "authorization.example.com",
+				TLS: &projcontour.TLS{
+					SecretName: "ssl-cert",
+				},
+				Authorization: &projcontour.AuthorizationServer{
+					ServiceRef: projcontour.ExtensionServiceReference{
+						Name: s1.Name,
+						Port: 8080,
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+				PermitInsecure: true,
+			}},
+		},
+	}
+
+	// proxy55a configures authorization with an extension service
+	// in another namespace
+	proxy55a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "authorization-other-namespace",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "authorization.example.com",
+				TLS: &projcontour.TLS{
+					SecretName: "ssl-cert",
+				},
+				Authorization: &projcontour.AuthorizationServer{
+					ServiceRef: projcontour.ExtensionServiceReference{
+						Name:      s11.Name,
+						Namespace: s11.Namespace,
+						Port:      8080,
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy56 has a CORS policy with an invalid max age
+	proxy56 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-cors-policy",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "cors.example.com",
+				CORSPolicy: &projcontour.CORSPolicy{
+					AllowOrigin:  []string{"*"},
+					AllowMethods: []projcontour.CORSHeaderValue{"GET"},
+					MaxAge:       "-10m",
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy57 includes another HTTPProxy with an exact path condition
+	proxy57 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []projcontour.Include{{
+				Name:      "child",
+				Namespace: "teama",
+				Conditions: []projcontour.Condition{{
+					Exact: "/api",
+				}},
+			}},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy58 has a route with an invalid regex path condition
+	proxy58 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Regex: "/api/(v1",
+				}},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy59 has a query parameter condition without a matcher
+	proxy59 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name: "version",
+					},
+				}},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy60 has a header condition with an invalid regex
+	proxy60 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Header: &projcontour.HeaderCondition{
+						Name:  "cookie",
+						Regex: "[canary",
+					},
+				}},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy60a has a query parameter condition with an invalid regex
+	proxy60a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name:  "version",
+						Regex: "v(1",
+					},
+				}},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy61 has a route with both services and a redirect policy
+	proxy61 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				RequestRedirectPolicy: &projcontour.HTTPRequestRedirectPolicy{
+					Hostname: "example.org",
+				},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy62 has a redirect policy with a prefix on an exact route
+	proxy62 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Exact: "/old",
+				}},
+				RequestRedirectPolicy: &projcontour.HTTPRequestRedirectPolicy{
+					Prefix: "/new",
+				},
+			}},
+		},
+	}
+
+	// proxy63 has a route with both services and a direct response policy
+	proxy63 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				DirectResponsePolicy: &projcontour.HTTPDirectResponsePolicy{
+					StatusCode: 410,
+				},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy64 has a route with both a redirect and a direct response policy
+	proxy64 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				RequestRedirectPolicy: &projcontour.HTTPRequestRedirectPolicy{
+					Hostname: "example.org",
+				},
+				DirectResponsePolicy: &projcontour.HTTPDirectResponsePolicy{
+					StatusCode: 410,
+				},
+			}},
+		},
+	}
+
+	// proxy65 uses the RequestHash strategy without any hash policies
+	proxy65 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+					Strategy: "RequestHash",
+				},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy66 has an outlier detection policy with an invalid maxEjectionPercent
+	proxy66 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+					OutlierDetection: &projcontour.OutlierDetection{
+						MaxEjectionPercent: 150,
+					},
+				}},
+			}},
+		},
+	}
+
+	// proxy67 has a circuit breaker policy with a negative threshold
+	proxy67 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+					CircuitBreakers: &projcontour.CircuitBreakers{
+						MaxConnections: -1,
+					},
+				}},
+			}},
+		},
+	}
+
+	tlsProtocol := "tls"
+
+	// proxy68 references a client certificate that has not been delegated
+	proxy68 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:              s1.Name,
+					Port:              8080,
+					Protocol:          &tlsProtocol,
+					ClientCertificate: sec2.Namespace + "/" + sec2.Name,
+				}},
+			}},
+		},
+	}
+
+	// proxy69 references a client certificate for a plaintext service
+	proxy69 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:              s1.Name,
+					Port:              8080,
+					ClientCertificate: sec1.Name,
+				}},
+			}},
+		},
+	}
+
+	// proxy70 has a tcpproxy with an invalid idle timeout
+	proxy70 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+				TimeoutPolicy: &projcontour.TCPProxyTimeoutPolicy{
+					Idle: "forever",
+				},
+			},
+		},
+	}
+
+	// proxy71 has a tracing policy with an invalid sampling rate
+	proxy71 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				Tracing: &projcontour.TracingPolicy{
+					RandomSampling: 150,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy72 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wildcard",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "*.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy73 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "www.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy74 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-wildcard",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "*.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy75 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wildcard",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "*.*.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy76 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "aliases",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn:    "example.com",
+				Aliases: []string{"www.example.com"},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy77 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "aliases",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn:    "example.com",
+				Aliases: []string{"www.example.com", "example.com"},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy78 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "aliases",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn:    "example.com",
+				Aliases: []string{"www.*.com"},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy79 enables the fallback certificate, which is not configured
+	proxy79 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fallback",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:                sec1.Name,
+					EnableFallbackCertificate: true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy80 combines tls passthrough with the fallback certificate
+	proxy80 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fallback",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					Passthrough:               true,
+					EnableFallbackCertificate: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// proxy81 has an invalid maximum tls protocol version
+	proxy81 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "max-version",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:             sec1.Name,
+					MaximumProtocolVersion: "1.4",
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy82 has a maximum tls protocol version lower than its minimum
+	proxy82 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "version-range",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:             sec1.Name,
+					MinimumProtocolVersion: "1.3",
+					MaximumProtocolVersion: "1.2",
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy82a has a maximum tls protocol version lower than
+	// the minimum in the Contour configuration file
+	proxy82a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "version-range",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:             sec1.Name,
+					MaximumProtocolVersion: "1.2",
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy83 offers an unsupported cipher suite
+	proxy83 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ciphers",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:   sec1.Name,
+					CipherSuites: []string{"ECDHE-RSA-AES256-GCM-SHA384", "RC4-MD5"},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy84 combines cipher suites with the fallback certificate
+	proxy84 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fallback-ciphers",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:                sec1.Name,
+					CipherSuites:              []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+					EnableFallbackCertificate: true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy84a raises the minimum tls protocol version and enables
+	// the fallback certificate
+	proxy84a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fallback-min-version",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName:                sec1.Name,
+					MinimumProtocolVersion:    "1.3",
+					EnableFallbackCertificate: true,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy85 uses global rate limiting without a rate limit service
+	proxy85 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "global-ratelimit",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "ratelimit.example.com",
+				RateLimitPolicy: &projcontour.RateLimitPolicy{
+					Global: &projcontour.GlobalRateLimitPolicy{
+						Descriptors: []projcontour.RateLimitDescriptor{{
+							Entries: []projcontour.RateLimitDescriptorEntry{{
+								RemoteAddress: &projcontour.RemoteAddressDescriptor{},
+							}},
+						}},
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy86 uses global rate limiting on a route without a rate limit service
+	proxy86 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "route-global-ratelimit",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "ratelimit.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+				RateLimitPolicy: &projcontour.RateLimitPolicy{
+					Global: &projcontour.GlobalRateLimitPolicy{
+						Descriptors: []projcontour.RateLimitDescriptor{{
+							Entries: []projcontour.RateLimitDescriptorEntry{{
+								GenericKey: &projcontour.GenericKeyDescriptor{
+									Value: "generic-key-value",
+								},
+							}},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	// proxy87 uses local rate limiting without enabling it
+	proxy87 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-ratelimit",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "local-ratelimit.example.com",
+				RateLimitPolicy: &projcontour.RateLimitPolicy{
+					Local: &projcontour.LocalRateLimitPolicy{
+						Requests: 100,
+						Unit:     "second",
+					},
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy88 has includes which differ only by query parameter
+	proxy88 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []projcontour.Include{{
+				Name:      "blog",
+				Namespace: s11.Namespace,
+				Conditions: []projcontour.Condition{{
+					Prefix: "/blog",
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name:  "version",
+						Exact: "v1",
+					},
+				}},
+			}, {
+				Name:      "blog",
+				Namespace: s12.Namespace,
+				Conditions: []projcontour.Condition{{
+					Prefix: "/blog",
+					QueryParameter: &projcontour.QueryParameterCondition{
+						Name:  "version",
+						Exact: "v2",
+					},
+				}},
+			}},
+		},
+	}
+
+	// proxy88a is a child of proxy88
+	proxy88a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s11.Namespace,
+			Name:      "blog",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s11.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy88b is a child of proxy88
+	proxy88b := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s12.Namespace,
+			Name:      "blog",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s12.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// proxy89 sends PROXY protocol upstream without enabling it
+	proxy89 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proxy-protocol",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:          s1.Name,
+					Port:          8080,
+					ProxyProtocol: "v1",
+				}},
+			}},
+		},
+	}
+
+	// proxy90 has a wildcard alias
+	proxy90 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wildcard-alias",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn:    "example.org",
+				Aliases: []string{"*.example.com"},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
 	tests := map[string]struct {
-		objs []interface{}
-		want map[Meta]Status
+		objs                   []interface{}
+		minimumProtocolVersion string
+		want                   map[Meta]Status
 	}{
 		"valid ingressroute": {
 			objs: []interface{}{ir1, s4},
@@ -2240,7 +3327,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"proxy invalid FQDN contains wildcard": {
 			objs: []interface{}{proxy15},
 			want: map[Meta]Status{
-				{name: proxy15.Name, namespace: proxy15.Namespace}: {Object: proxy15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Vhost: "example.*.com"},
+				{name: proxy15.Name, namespace: proxy15.Namespace}: {Object: proxy15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" can only use a wildcard as its leftmost label`, Vhost: "example.*.com"},
 			},
 		},
 		"proxy missing service shows invalid status": {
@@ -2438,6 +3525,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 				{name: proxy41b.Name, namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 			},
 		},
+		"include conditions differing only by query parameter": {
+			objs: []interface{}{proxy88, proxy88a, proxy88b, s11, s12},
+			want: map[Meta]Status{
+				{name: proxy88.Name, namespace: proxy88.Namespace}:   {Object: proxy88, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+				{name: proxy88a.Name, namespace: proxy88a.Namespace}: {Object: proxy88a, Status: "valid", Description: "valid HTTPProxy", Vhost: ""},
+				{name: proxy88b.Name, namespace: proxy88b.Namespace}: {Object: proxy88b, Status: "valid", Description: "valid HTTPProxy", Vhost: ""},
+			},
+		},
 		"httpproxy with invalid tcpproxy": {
 			objs: []interface{}{proxy37, s1},
 			want: map[Meta]Status{
@@ -2582,6 +3677,453 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 				},
 			},
 		},
+		"invalid HTTPProxy due to missing client validation CA": {
+			objs: []interface{}{proxy50, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy50.Name, namespace: proxy50.Namespace}: {
+					Object:      proxy50,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.TLS client validation is invalid: secret not found or misconfigured",
+					Vhost:       "client-validation.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to client validation with tls passthrough": {
+			objs: []interface{}{proxy51, s1},
+			want: map[Meta]Status{
+				{name: proxy51.Name, namespace: proxy51.Namespace}: {
+					Object:      proxy51,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.TLS passthrough cannot be combined with tls.clientValidation",
+					Vhost:       "client-validation.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to invalid rate limit descriptor entry": {
+			objs: []interface{}{proxy52, s1},
+			want: map[Meta]Status{
+				{name: proxy52.Name, namespace: proxy52.Namespace}: {
+					Object:      proxy52,
+					Status:      "invalid",
+					Description: "route.rateLimitPolicy is invalid: rate limit descriptor entry must have exactly one field set",
+					Vhost:       "ratelimit.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to invalid local rate limit policy": {
+			objs: []interface{}{proxy53, s1},
+			want: map[Meta]Status{
+				{name: proxy53.Name, namespace: proxy53.Namespace}: {
+					Object:      proxy53,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.RateLimitPolicy is invalid: local.unit must be second, minute or hour",
+					Vhost:       "local-ratelimit.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to authorization without TLS": {
+			objs: []interface{}{proxy54, s1},
+			want: map[Meta]Status{
+				{name: proxy54.Name, namespace: proxy54.Namespace}: {
+					Object:      proxy54,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.Authorization requires TLS termination and cannot be combined with tcpproxy",
+					Vhost:       "authorization.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to authorization with permitInsecure route": {
+			objs: []interface{}{proxy55, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy55.Name, namespace: proxy55.Namespace}: {
+					Object:      proxy55,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.Authorization cannot be combined with permitInsecure routes unless route authPolicy is disabled",
+					Vhost:       "authorization.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to authorization service in another namespace": {
+			objs: []interface{}{proxy55a, s1, s11, sec1},
+			want: map[Meta]Status{
+				{name: proxy55a.Name, namespace: proxy55a.Namespace}: {
+					Object:      proxy55a,
+					Status:      "invalid",
+					Description: `Spec.VirtualHost.Authorization is invalid: service "kuard": namespace "teama" must match the HTTPProxy namespace "roots"`,
+					Vhost:       "authorization.example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to invalid CORS policy": {
+			objs: []interface{}{proxy56, s1},
+			want: map[Meta]Status{
+				{name: proxy56.Name, namespace: proxy56.Namespace}: {
+					Object:      proxy56,
+					Status:      "invalid",
+					Description: `Spec.VirtualHost.CORSPolicy is invalid: maxAge "-10m" must be positive`,
+					Vhost:       "cors.example.com",
+				},
+			},
+		},
+		"proxy with exact condition as an include": {
+			objs: []interface{}{proxy57, proxy34, s1},
+			want: map[Meta]Status{
+				{name: proxy57.Name, namespace: proxy57.Namespace}: {
+					Object:      proxy57,
+					Status:      "invalid",
+					Description: "include: exact and regex conditions are not allowed on includes",
+					Vhost:       "example.com",
+				}, {name: proxy34.Name, namespace: proxy34.Namespace}: {
+					Object:      proxy34,
+					Status:      "orphaned",
+					Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy",
+				},
+			},
+		},
+		"proxy with query parameter condition without a matcher": {
+			objs: []interface{}{proxy59, s1},
+			want: map[Meta]Status{
+				{name: proxy59.Name, namespace: proxy59.Namespace}: {
+					Object:      proxy59,
+					Status:      "invalid",
+					Description: `route: queryParameter "version": exactly one of exact, prefix, regex or present must be set`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with invalid regex header condition": {
+			objs: []interface{}{proxy60, s1},
+			want: map[Meta]Status{
+				{name: proxy60.Name, namespace: proxy60.Namespace}: {
+					Object:      proxy60,
+					Status:      "invalid",
+					Description: "route: header \"cookie\": regex \"[canary\" is not a valid regular expression: error parsing regexp: missing closing ]: `[canary`",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with invalid regex query parameter condition": {
+			objs: []interface{}{proxy60a, s1},
+			want: map[Meta]Status{
+				{name: proxy60a.Name, namespace: proxy60a.Namespace}: {
+					Object:      proxy60a,
+					Status:      "invalid",
+					Description: "route: queryParameter \"version\": regex \"v(1\" is not a valid regular expression: error parsing regexp: missing closing ): `v(1`",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to services and request redirect policy": {
+			objs: []interface{}{proxy61, s1},
+			want: map[Meta]Status{
+				{name: proxy61.Name, namespace: proxy61.Namespace}: {
+					Object:      proxy61,
+					Status:      "invalid",
+					Description: "route.services and route.requestRedirectPolicy cannot both be specified",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to redirect prefix without prefix condition": {
+			objs: []interface{}{proxy62},
+			want: map[Meta]Status{
+				{name: proxy62.Name, namespace: proxy62.Namespace}: {
+					Object:      proxy62,
+					Status:      "invalid",
+					Description: "route.requestRedirectPolicy is invalid: prefix requires a prefix condition",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to services and direct response policy": {
+			objs: []interface{}{proxy63, s1},
+			want: map[Meta]Status{
+				{name: proxy63.Name, namespace: proxy63.Namespace}: {
+					Object:      proxy63,
+					Status:      "invalid",
+					Description: "route.services and route.directResponsePolicy cannot both be specified",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to request redirect and direct response policy": {
+			objs: []interface{}{proxy64},
+			want: map[Meta]Status{
+				{name: proxy64.Name, namespace: proxy64.Namespace}: {
+					Object:      proxy64,
+					Status:      "invalid",
+					Description: "route.requestRedirectPolicy and route.directResponsePolicy cannot both be specified",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to RequestHash strategy without hash policies": {
+			objs: []interface{}{proxy65, s1},
+			want: map[Meta]Status{
+				{name: proxy65.Name, namespace: proxy65.Namespace}: {
+					Object:      proxy65,
+					Status:      "invalid",
+					Description: "route.loadBalancerPolicy is invalid: the RequestHash strategy requires at least one requestHashPolicy",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to outlier detection maxEjectionPercent": {
+			objs: []interface{}{proxy66, s1},
+			want: map[Meta]Status{
+				{name: proxy66.Name, namespace: proxy66.Namespace}: {
+					Object:      proxy66,
+					Status:      "invalid",
+					Description: "Service [kuard:8080] outlier detection policy error: maxEjectionPercent 150 must be no more than 100",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to negative circuit breaker threshold": {
+			objs: []interface{}{proxy67, s1},
+			want: map[Meta]Status{
+				{name: proxy67.Name, namespace: proxy67.Namespace}: {
+					Object:      proxy67,
+					Status:      "invalid",
+					Description: "Service [kuard:8080] circuit breaker policy error: maxConnections -1 must be in the range 1-4294967295",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to undelegated client certificate": {
+			objs: []interface{}{proxy68, s1, sec2},
+			want: map[Meta]Status{
+				{name: proxy68.Name, namespace: proxy68.Namespace}: {
+					Object:      proxy68,
+					Status:      "invalid",
+					Description: `Service [kuard:8080] client certificate "heptio-contour/default-ssl-cert": certificate delegation not permitted`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to client certificate on plaintext service": {
+			objs: []interface{}{proxy69, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy69.Name, namespace: proxy69.Namespace}: {
+					Object:      proxy69,
+					Status:      "invalid",
+					Description: "Service [kuard:8080] client certificate requires the tls or h2 protocol",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to invalid tcpproxy idle timeout": {
+			objs: []interface{}{proxy70, s1},
+			want: map[Meta]Status{
+				{name: proxy70.Name, namespace: proxy70.Namespace}: {
+					Object:      proxy70,
+					Status:      "invalid",
+					Description: `tcpproxy.timeoutPolicy is invalid: invalid idle timeout "forever": time: invalid duration "forever"`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"invalid HTTPProxy due to invalid tracing policy": {
+			objs: []interface{}{proxy71, s1},
+			want: map[Meta]Status{
+				{name: proxy71.Name, namespace: proxy71.Namespace}: {
+					Object:      proxy71,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.Tracing is invalid: randomSampling 150 must be no more than 100",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with wildcard fqdn": {
+			objs: []interface{}{proxy72, s1},
+			want: map[Meta]Status{
+				{name: proxy72.Name, namespace: proxy72.Namespace}: {Object: proxy72, Status: "valid", Description: "valid HTTPProxy", Vhost: "*.example.com"},
+			},
+		},
+		"proxy with wildcard fqdn and proxy with exact fqdn": {
+			objs: []interface{}{proxy72, proxy73, s1},
+			want: map[Meta]Status{
+				{name: proxy72.Name, namespace: proxy72.Namespace}: {Object: proxy72, Status: "valid", Description: "valid HTTPProxy; exact hosts take precedence: www.example.com", Vhost: "*.example.com"},
+				{name: proxy73.Name, namespace: proxy73.Namespace}: {Object: proxy73, Status: "valid", Description: "valid HTTPProxy", Vhost: "www.example.com"},
+			},
+		},
+		"proxy with wildcard fqdn and proxy with exact alias": {
+			objs: []interface{}{proxy72, proxy76, s1},
+			want: map[Meta]Status{
+				{name: proxy72.Name, namespace: proxy72.Namespace}: {Object: proxy72, Status: "valid", Description: "valid HTTPProxy; exact hosts take precedence: www.example.com", Vhost: "*.example.com"},
+				{name: proxy76.Name, namespace: proxy76.Namespace}: {Object: proxy76, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"proxy with wildcard fqdn and ingressroute with exact fqdn": {
+			objs: []interface{}{proxy72, ir27, s1, s10},
+			want: map[Meta]Status{
+				{name: proxy72.Name, namespace: proxy72.Namespace}: {Object: proxy72, Status: "valid", Description: "valid HTTPProxy; exact hosts take precedence: kuard.example.com", Vhost: "*.example.com"},
+				{name: ir27.Name, namespace: ir27.Namespace}:       {Object: ir27, Status: "valid", Description: "valid IngressRoute", Vhost: "kuard.example.com"},
+			},
+		},
+		"proxy with wildcard alias and proxy with exact fqdn": {
+			objs: []interface{}{proxy90, proxy73, s1},
+			want: map[Meta]Status{
+				{name: proxy90.Name, namespace: proxy90.Namespace}: {Object: proxy90, Status: "valid", Description: "valid HTTPProxy; exact hosts take precedence: www.example.com", Vhost: "example.org"},
+				{name: proxy73.Name, namespace: proxy73.Namespace}: {Object: proxy73, Status: "valid", Description: "valid HTTPProxy", Vhost: "www.example.com"},
+			},
+		},
+		"proxies with conflicting wildcard fqdn": {
+			objs: []interface{}{proxy72, proxy74, s1},
+			want: map[Meta]Status{
+				{name: proxy72.Name, namespace: proxy72.Namespace}: {Object: proxy72, Status: "invalid", Description: `fqdn "*.example.com" is used in multiple HTTPProxies: roots/other-wildcard, roots/wildcard`, Vhost: "*.example.com"},
+				{name: proxy74.Name, namespace: proxy74.Namespace}: {Object: proxy74, Status: "invalid", Description: `fqdn "*.example.com" is used in multiple HTTPProxies: roots/other-wildcard, roots/wildcard`, Vhost: "*.example.com"},
+			},
+		},
+		"proxy with multiple wildcards in fqdn": {
+			objs: []interface{}{proxy75, s1},
+			want: map[Meta]Status{
+				{name: proxy75.Name, namespace: proxy75.Namespace}: {Object: proxy75, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "*.*.example.com" can only use a wildcard as its leftmost label`, Vhost: "*.*.example.com"},
+			},
+		},
+		"proxy with alias": {
+			objs: []interface{}{proxy76, s1},
+			want: map[Meta]Status{
+				{name: proxy76.Name, namespace: proxy76.Namespace}: {Object: proxy76, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"proxy with alias conflicting with another proxy's fqdn": {
+			objs: []interface{}{proxy76, proxy73, s1},
+			want: map[Meta]Status{
+				{name: proxy76.Name, namespace: proxy76.Namespace}: {Object: proxy76, Status: "invalid", Description: `fqdn "www.example.com" is used in multiple HTTPProxies: roots/aliases, roots/www`, Vhost: "example.com"},
+				{name: proxy73.Name, namespace: proxy73.Namespace}: {Object: proxy73, Status: "invalid", Description: `fqdn "www.example.com" is used in multiple HTTPProxies: roots/aliases, roots/www`, Vhost: "www.example.com"},
+			},
+		},
+		"proxy with duplicate alias": {
+			objs: []interface{}{proxy77, s1},
+			want: map[Meta]Status{
+				{name: proxy77.Name, namespace: proxy77.Namespace}: {Object: proxy77, Status: "invalid", Description: `Spec.VirtualHost.Aliases "example.com" is duplicated`, Vhost: "example.com"},
+			},
+		},
+		"proxy with invalid wildcard alias": {
+			objs: []interface{}{proxy78, s1},
+			want: map[Meta]Status{
+				{name: proxy78.Name, namespace: proxy78.Namespace}: {Object: proxy78, Status: "invalid", Description: `Spec.VirtualHost.Aliases "www.*.com" can only use a wildcard as its leftmost label`, Vhost: "example.com"},
+			},
+		},
+		"proxy with fallback certificate not configured": {
+			objs: []interface{}{proxy79, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy79.Name, namespace: proxy79.Namespace}: {Object: proxy79, Status: "invalid", Description: "Spec.VirtualHost.TLS enableFallbackCertificate requires a fallback certificate in the Contour configuration file", Vhost: "example.com"},
+			},
+		},
+		"proxy with fallback certificate and tls passthrough": {
+			objs: []interface{}{proxy80, s1},
+			want: map[Meta]Status{
+				{name: proxy80.Name, namespace: proxy80.Namespace}: {Object: proxy80, Status: "invalid", Description: "Spec.VirtualHost.TLS enableFallbackCertificate requires TLS termination and cannot be combined with tcpproxy", Vhost: "example.com"},
+			},
+		},
+		"proxy with invalid maximum tls protocol version": {
+			objs: []interface{}{proxy81, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy81.Name, namespace: proxy81.Namespace}: {Object: proxy81, Status: "invalid", Description: `Spec.VirtualHost.TLS is invalid: invalid maximum protocol version "1.4"`, Vhost: "example.com"},
+			},
+		},
+		"proxy with maximum tls protocol version lower than minimum": {
+			objs: []interface{}{proxy82, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy82.Name, namespace: proxy82.Namespace}: {Object: proxy82, Status: "invalid", Description: `Spec.VirtualHost.TLS maximumProtocolVersion "1.2" is lower than the minimum protocol version`, Vhost: "example.com"},
+			},
+		},
+		"proxy with maximum tls protocol version lower than configured minimum": {
+			objs:                   []interface{}{proxy82a, s1, sec1},
+			minimumProtocolVersion: "1.3",
+			want: map[Meta]Status{
+				{name: proxy82a.Name, namespace: proxy82a.Namespace}: {Object: proxy82a, Status: "invalid", Description: `Spec.VirtualHost.TLS maximumProtocolVersion "1.2" is lower than the minimum protocol version "1.3" in the Contour configuration file`, Vhost: "example.com"},
+			},
+		},
+		"proxy with maximum tls protocol version at configured minimum": {
+			objs:                   []interface{}{proxy82a, s1, sec1},
+			minimumProtocolVersion: "1.2",
+			want: map[Meta]Status{
+				{name: proxy82a.Name, namespace: proxy82a.Namespace}: {Object: proxy82a, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"proxy with unsupported cipher suite": {
+			objs: []interface{}{proxy83, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy83.Name, namespace: proxy83.Namespace}: {Object: proxy83, Status: "invalid", Description: `Spec.VirtualHost.TLS is invalid: invalid cipher suite "RC4-MD5"`, Vhost: "example.com"},
+			},
+		},
+		"proxy with fallback certificate and cipher suites": {
+			objs: []interface{}{proxy84, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy84.Name, namespace: proxy84.Namespace}: {Object: proxy84, Status: "invalid", Description: "Spec.VirtualHost.TLS enableFallbackCertificate cannot be combined with tls.maximumProtocolVersion or tls.cipherSuites", Vhost: "example.com"},
+			},
+		},
+		"proxy with fallback certificate and higher minimum tls protocol version": {
+			objs: []interface{}{proxy84a, s1, sec1},
+			want: map[Meta]Status{
+				{name: proxy84a.Name, namespace: proxy84a.Namespace}: {Object: proxy84a, Status: "invalid", Description: "Spec.VirtualHost.TLS enableFallbackCertificate cannot be combined with a tls.minimumProtocolVersion higher than the minimum protocol version in the Contour configuration file", Vhost: "example.com"},
+			},
+		},
+		"proxy with fallback certificate and minimum tls protocol version at configured minimum": {
+			objs:                   []interface{}{proxy84a, s1, sec1},
+			minimumProtocolVersion: "1.3",
+			want: map[Meta]Status{
+				{name: proxy84a.Name, namespace: proxy84a.Namespace}: {Object: proxy84a, Status: "invalid", Description: "Spec.VirtualHost.TLS enableFallbackCertificate requires a fallback certificate in the Contour configuration file", Vhost: "example.com"},
+			},
+		},
+		"global rate limit policy without a rate limit service": {
+			objs: []interface{}{proxy85, s1},
+			want: map[Meta]Status{
+				{name: proxy85.Name, namespace: proxy85.Namespace}: {
+					Object:      proxy85,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.RateLimitPolicy.Global requires a rate limit service to be configured",
+					Vhost:       "ratelimit.example.com",
+				},
+			},
+		},
+		"route global rate limit policy without a rate limit service": {
+			objs: []interface{}{proxy86, s1},
+			want: map[Meta]Status{
+				{name: proxy86.Name, namespace: proxy86.Namespace}: {
+					Object:      proxy86,
+					Status:      "invalid",
+					Description: "route.rateLimitPolicy.global requires a rate limit service to be configured",
+					Vhost:       "ratelimit.example.com",
+				},
+			},
+		},
+		"local rate limit policy without local rate limiting enabled": {
+			objs: []interface{}{proxy87, s1},
+			want: map[Meta]Status{
+				{name: proxy87.Name, namespace: proxy87.Namespace}: {
+					Object:      proxy87,
+					Status:      "invalid",
+					Description: "Spec.VirtualHost.RateLimitPolicy.Local requires local rate limiting to be enabled",
+					Vhost:       "local-ratelimit.example.com",
+				},
+			},
+		},
+		"proxy protocol without upstream PROXY protocol enabled": {
+			objs: []interface{}{proxy89, s1},
+			want: map[Meta]Status{
+				{name: proxy89.Name, namespace: proxy89.Namespace}: {
+					Object:      proxy89,
+					Status:      "invalid",
+					Description: "Service [kuard:8080] proxyProtocol requires upstream PROXY protocol to be enabled",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with invalid regex condition on route": {
+			objs: []interface{}{proxy58, s1},
+			want: map[Meta]Status{
+				{name: proxy58.Name, namespace: proxy58.Namespace}: {
+					Object:      proxy58,
+					Status:      "invalid",
+					Description: "route: regex condition \"/api/(v1\" is not a valid regular expression: error parsing regexp: missing closing ): `/api/(v1`",
+					Vhost:       "example.com",
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -2591,6 +4133,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					RootNamespaces: []string{"roots", "marketing"},
 					FieldLogger:    testLogger(t),
 				},
+				MinimumProtocolVersion: tc.minimumProtocolVersion,
 			}
 			for _, o := range tc.objs {
 				builder.Source.Insert(o)
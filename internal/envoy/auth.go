@@ -17,6 +17,8 @@ import (
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 var (
@@ -46,9 +48,10 @@ var (
 )
 
 // UpstreamTLSContext creates an envoy_api_v2_auth.UpstreamTlsContext. By default
-// UpstreamTLSContext returns a HTTP/1.1 TLS enabled context. A list of
-// additional ALPN protocols can be provided.
-func UpstreamTLSContext(ca []byte, subjectName string, sni string, alpnProtocols ...string) *envoy_api_v2_auth.UpstreamTlsContext {
+// UpstreamTLSContext returns a HTTP/1.1 TLS enabled context. If clientSecret
+// is supplied, its certificate is presented to the upstream, delivered via SDS.
+// A list of additional ALPN protocols can be provided.
+func UpstreamTLSContext(ca []byte, subjectName string, sni string, clientSecret *dag.Secret, alpnProtocols ...string) *envoy_api_v2_auth.UpstreamTlsContext {
 	context := &envoy_api_v2_auth.UpstreamTlsContext{
 		CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
 			AlpnProtocols: alpnProtocols,
@@ -56,14 +59,26 @@ func UpstreamTLSContext(ca []byte, subjectName string, sni string, alpnProtocols
 		Sni: sni,
 	}
 
+	if clientSecret != nil {
+		context.CommonTlsContext.TlsCertificateSdsSecretConfigs = []*envoy_api_v2_auth.SdsSecretConfig{{
+			Name:      Secretname(clientSecret),
+			SdsConfig: ConfigSource("contour"),
+		}}
+	}
+
 	// we have to do explicitly assign the value from validationContext
 	// to context.CommonTlsContext.ValidationContextType because the latter
 	// is an interface, returning nil from validationContext directly into
 	// this field boxes the nil into the unexported type of this grpc OneOf field
 	// which causes proto marshaling to explode later on. Not happy Jan.
-	vc := validationContext(ca, subjectName)
-	if vc != nil {
-		context.CommonTlsContext.ValidationContextType = vc
+	//
+	// Upstream validation is only performed when both the CA and the
+	// expected subject name are provided.
+	if len(subjectName) > 0 {
+		vc := validationContext(ca, subjectName)
+		if vc != nil {
+			context.CommonTlsContext.ValidationContextType = vc
+		}
 	}
 
 	return context
@@ -75,12 +90,7 @@ func validationContext(ca []byte, subjectName string) *envoy_api_v2_auth.CommonT
 		return nil
 	}
 
-	if len(subjectName) < 1 {
-		// no subject name provided, nothing to do
-		return nil
-	}
-
-	return &envoy_api_v2_auth.CommonTlsContext_ValidationContext{
+	vc := &envoy_api_v2_auth.CommonTlsContext_ValidationContext{
 		ValidationContext: &envoy_api_v2_auth.CertificateValidationContext{
 			TrustedCa: &envoy_api_v2_core.DataSource{
 				// TODO(dfc) update this for SDS
@@ -88,23 +98,38 @@ func validationContext(ca []byte, subjectName string) *envoy_api_v2_auth.CommonT
 					InlineBytes: ca,
 				},
 			},
-			MatchSubjectAltNames: []*matcher.StringMatcher{{
-				MatchPattern: &matcher.StringMatcher_Exact{
-					Exact: subjectName,
-				}},
-			},
 		},
 	}
+
+	if len(subjectName) > 0 {
+		vc.ValidationContext.MatchSubjectAltNames = []*matcher.StringMatcher{{
+			MatchPattern: &matcher.StringMatcher_Exact{
+				Exact: subjectName,
+			}},
+		}
+	}
+
+	return vc
 }
 
-// DownstreamTLSContext creates a new DownstreamTlsContext.
-func DownstreamTLSContext(secretName string, tlsMinProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol, alpnProtos ...string) *envoy_api_v2_auth.DownstreamTlsContext {
-	return &envoy_api_v2_auth.DownstreamTlsContext{
+// DownstreamTLSContext creates a new DownstreamTlsContext. If tlsMaxProtoVersion
+// is TLS_AUTO, TLS 1.3 is the maximum version negotiated. If cipherSuites is
+// empty, the default cipher suites are offered. If peerValidationContext
+// is supplied, clients must present a certificate signed by its CA.
+func DownstreamTLSContext(secretName string, tlsMinProtoVersion, tlsMaxProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol, cipherSuites []string, peerValidationContext *dag.PeerValidationContext, alpnProtos ...string) *envoy_api_v2_auth.DownstreamTlsContext {
+	if tlsMaxProtoVersion == envoy_api_v2_auth.TlsParameters_TLS_AUTO {
+		tlsMaxProtoVersion = envoy_api_v2_auth.TlsParameters_TLSv1_3
+	}
+	if len(cipherSuites) == 0 {
+		cipherSuites = ciphers
+	}
+
+	context := &envoy_api_v2_auth.DownstreamTlsContext{
 		CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
 			TlsParams: &envoy_api_v2_auth.TlsParameters{
 				TlsMinimumProtocolVersion: tlsMinProtoVersion,
-				TlsMaximumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_3,
-				CipherSuites:              ciphers,
+				TlsMaximumProtocolVersion: tlsMaxProtoVersion,
+				CipherSuites:              cipherSuites,
 			},
 			TlsCertificateSdsSecretConfigs: []*envoy_api_v2_auth.SdsSecretConfig{{
 				Name:      secretName,
@@ -113,4 +138,14 @@ func DownstreamTLSContext(secretName string, tlsMinProtoVersion envoy_api_v2_aut
 			AlpnProtocols: alpnProtos,
 		},
 	}
+
+	// See the comment in UpstreamTLSContext on why the validation
+	// context must only be assigned when it is not nil.
+	vc := validationContext(peerValidationContext.GetCACertificate(), peerValidationContext.GetSubjectName())
+	if vc != nil {
+		context.CommonTlsContext.ValidationContextType = vc
+		context.RequireClientCertificate = protobuf.Bool(true)
+	}
+
+	return context
 }
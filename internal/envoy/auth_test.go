@@ -20,6 +20,9 @@ import (
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
 	"github.com/google/go-cmp/cmp"
+	"github.com/projectcontour/contour/internal/dag"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestUpstreamTLSContext(t *testing.T) {
@@ -28,6 +31,7 @@ func TestUpstreamTLSContext(t *testing.T) {
 		subjectName   string
 		alpnProtocols []string
 		externalName  string
+		clientSecret  *dag.Secret
 		want          *envoy_api_v2_auth.UpstreamTlsContext
 	}{
 		"no alpn, no validation": {
@@ -84,11 +88,33 @@ func TestUpstreamTLSContext(t *testing.T) {
 				Sni:              "projectcontour.local",
 			},
 		},
+		"client certificate": {
+			clientSecret: &dag.Secret{
+				Object: &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "clientcert",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						v1.TLSCertKey:       []byte("cert"),
+						v1.TLSPrivateKeyKey: []byte("key"),
+					},
+				},
+			},
+			want: &envoy_api_v2_auth.UpstreamTlsContext{
+				CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+					TlsCertificateSdsSecretConfigs: []*envoy_api_v2_auth.SdsSecretConfig{{
+						Name:      "default/clientcert/cd1b506996",
+						SdsConfig: ConfigSource("contour"),
+					}},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := UpstreamTLSContext(tc.ca, tc.subjectName, tc.externalName, tc.alpnProtocols...)
+			got := UpstreamTLSContext(tc.ca, tc.subjectName, tc.externalName, tc.clientSecret, tc.alpnProtocols...)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Fatal(diff)
 			}
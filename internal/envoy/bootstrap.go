@@ -103,6 +103,28 @@ func Bootstrap(c *BootstrapConfig) *bootstrap.Bootstrap {
 		)
 	}
 
+	if c.TracingAddress != "" {
+		b.StaticResources.Clusters = append(b.StaticResources.Clusters, &api.Cluster{
+			Name:                 TracingCluster,
+			AltStatName:          strings.Join([]string{c.Namespace, TracingCluster, strconv.Itoa(c.tracingPort())}, "_"),
+			ConnectTimeout:       protobuf.Duration(250 * time.Millisecond),
+			ClusterDiscoveryType: ClusterDiscoveryType(api.Cluster_STRICT_DNS),
+			LbPolicy:             api.Cluster_ROUND_ROBIN,
+			LoadAssignment: &api.ClusterLoadAssignment{
+				ClusterName: TracingCluster,
+				Endpoints: Endpoints(
+					SocketAddress(c.TracingAddress, c.tracingPort()),
+				),
+			},
+		})
+		b.Tracing = ZipkinTracer(c.tracingCollectorEndpoint())
+		if c.TracingServiceName != "" {
+			b.Node = &envoy_api_v2_core.Node{
+				Cluster: c.TracingServiceName,
+			}
+		}
+	}
+
 	return b
 }
 
@@ -176,12 +198,34 @@ type BootstrapConfig struct {
 
 	// GrpcClientKey is the filename that contains a client key for secure gRPC with TLS.
 	GrpcClientKey string
+
+	// TracingAddress is the DNS name or IP address of a Zipkin compatible
+	// trace collector. If not set, Envoy is not configured with a tracer.
+	TracingAddress string
+
+	// TracingPort is the port of the trace collector.
+	// Defaults to 9411.
+	TracingPort int
+
+	// TracingCollectorEndpoint is the path spans are sent to.
+	// Defaults to /api/v2/spans.
+	TracingCollectorEndpoint string
+
+	// TracingServiceName is the service name Envoy reports spans
+	// under. Envoy's Zipkin tracer uses the node's cluster as the
+	// service name, so this sets the bootstrap node cluster. Note
+	// that Envoy's --service-cluster flag takes precedence.
+	TracingServiceName string
 }
 
 func (c *BootstrapConfig) xdsAddress() string   { return stringOrDefault(c.XDSAddress, "127.0.0.1") }
 func (c *BootstrapConfig) xdsGRPCPort() int     { return intOrDefault(c.XDSGRPCPort, 8001) }
 func (c *BootstrapConfig) adminAddress() string { return stringOrDefault(c.AdminAddress, "127.0.0.1") }
 func (c *BootstrapConfig) adminPort() int       { return intOrDefault(c.AdminPort, 9001) }
+func (c *BootstrapConfig) tracingPort() int     { return intOrDefault(c.TracingPort, 9411) }
+func (c *BootstrapConfig) tracingCollectorEndpoint() string {
+	return stringOrDefault(c.TracingCollectorEndpoint, "/api/v2/spans")
+}
 func (c *BootstrapConfig) adminAccessLogPath() string {
 	return stringOrDefault(c.AdminAccessLogPath, "/dev/null")
 }
@@ -47,6 +47,10 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 	cluster.AltStatName = altStatName(service)
 	cluster.LbPolicy = lbPolicy(c.LoadBalancerPolicy)
 	cluster.HealthChecks = edshealthcheck(c)
+	cluster.OutlierDetection = outlierDetection(c.OutlierDetectionPolicy)
+	if c.ConnectTimeout > 0 {
+		cluster.ConnectTimeout = protobuf.Duration(c.ConnectTimeout)
+	}
 
 	switch len(service.ExternalName) {
 	case 0:
@@ -64,16 +68,7 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 		cluster.DrainConnectionsOnHostRemoval = true
 	}
 
-	if anyPositive(service.MaxConnections, service.MaxPendingRequests, service.MaxRequests, service.MaxRetries) {
-		cluster.CircuitBreakers = &envoy_cluster.CircuitBreakers{
-			Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
-				MaxConnections:     u32nil(service.MaxConnections),
-				MaxPendingRequests: u32nil(service.MaxPendingRequests),
-				MaxRequests:        u32nil(service.MaxRequests),
-				MaxRetries:         u32nil(service.MaxRetries),
-			}},
-		}
-	}
+	cluster.CircuitBreakers = circuitBreakers(c)
 
 	switch c.Protocol {
 	case "tls":
@@ -82,6 +77,7 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 				upstreamValidationCACert(c),
 				upstreamValidationSubjectAltName(c),
 				service.ExternalName,
+				c.ClientCertificate,
 			),
 		)
 	case "h2":
@@ -90,6 +86,7 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 				upstreamValidationCACert(c),
 				upstreamValidationSubjectAltName(c),
 				service.ExternalName,
+				c.ClientCertificate,
 				"h2",
 			),
 		)
@@ -98,23 +95,35 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 		cluster.Http2ProtocolOptions = &envoy_api_v2_core.Http2ProtocolOptions{}
 	}
 
+	if c.ProxyProtocol != "" {
+		cluster.TransportSocket = UpstreamProxyProtocolTransportSocket(c.ProxyProtocol, cluster.TransportSocket)
+	}
+
 	return cluster
 }
 
-func upstreamValidationCACert(c *dag.Cluster) []byte {
-	if c.UpstreamValidation == nil {
-		// No validation required
-		return nil
+// GRPCServiceCluster creates a new v2.Cluster for a gRPC service
+// reachable at the supplied address and port, resolved via DNS.
+func GRPCServiceCluster(name, address string, port int) *v2.Cluster {
+	cluster := clusterDefaults()
+
+	cluster.Name = name
+	cluster.ClusterDiscoveryType = ClusterDiscoveryType(v2.Cluster_STRICT_DNS)
+	cluster.LoadAssignment = &v2.ClusterLoadAssignment{
+		ClusterName: name,
+		Endpoints:   Endpoints(SocketAddress(address, port)),
 	}
-	return c.UpstreamValidation.CACertificate.Object.Data[dag.CACertificateKey]
+	cluster.Http2ProtocolOptions = &envoy_api_v2_core.Http2ProtocolOptions{}
+
+	return cluster
+}
+
+func upstreamValidationCACert(c *dag.Cluster) []byte {
+	return c.UpstreamValidation.GetCACertificate()
 }
 
 func upstreamValidationSubjectAltName(c *dag.Cluster) string {
-	if c.UpstreamValidation == nil {
-		// No validation required
-		return ""
-	}
-	return c.UpstreamValidation.SubjectName
+	return c.UpstreamValidation.GetSubjectName()
 }
 
 // StaticClusterLoadAssignment creates a *v2.ClusterLoadAssignment pointing to the external DNS address of the service
@@ -153,13 +162,66 @@ func lbPolicy(strategy string) v2.Cluster_LbPolicy {
 		return v2.Cluster_LEAST_REQUEST
 	case "Random":
 		return v2.Cluster_RANDOM
-	case "Cookie":
+	case "Cookie", "RequestHash":
 		return v2.Cluster_RING_HASH
 	default:
 		return v2.Cluster_ROUND_ROBIN
 	}
 }
 
+// circuitBreakers returns the circuit breaking thresholds of the
+// cluster, preferring the cluster's own policy over the limits set on
+// its upstream service. If no thresholds are set nil is returned.
+func circuitBreakers(c *dag.Cluster) *envoy_cluster.CircuitBreakers {
+	cb := dag.CircuitBreakerPolicy{
+		MaxConnections:     c.Upstream.MaxConnections,
+		MaxPendingRequests: c.Upstream.MaxPendingRequests,
+		MaxRequests:        c.Upstream.MaxRequests,
+		MaxRetries:         c.Upstream.MaxRetries,
+	}
+	if c.CircuitBreakerPolicy != nil {
+		cb = *c.CircuitBreakerPolicy
+	}
+
+	if !anyPositive(cb.MaxConnections, cb.MaxPendingRequests, cb.MaxRequests, cb.MaxRetries) {
+		return nil
+	}
+	return &envoy_cluster.CircuitBreakers{
+		Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
+			MaxConnections:     u32nil(cb.MaxConnections),
+			MaxPendingRequests: u32nil(cb.MaxPendingRequests),
+			MaxRequests:        u32nil(cb.MaxRequests),
+			MaxRetries:         u32nil(cb.MaxRetries),
+		}},
+	}
+}
+
+// outlierDetection returns the Envoy outlier detection configuration
+// for the supplied policy, or nil if no policy is supplied.
+func outlierDetection(od *dag.OutlierDetectionPolicy) *envoy_cluster.OutlierDetection {
+	if od == nil {
+		return nil
+	}
+
+	o := &envoy_cluster.OutlierDetection{
+		Consecutive_5Xx:    u32nil(od.ConsecutiveServerErrors),
+		MaxEjectionPercent: u32nil(od.MaxEjectionPercent),
+	}
+	if od.Interval > 0 {
+		o.Interval = protobuf.Duration(od.Interval)
+	}
+	if od.BaseEjectionTime > 0 {
+		o.BaseEjectionTime = protobuf.Duration(od.BaseEjectionTime)
+	}
+	if od.ConsecutiveGatewayErrors > 0 {
+		// Envoy does not enforce gateway failure ejection
+		// unless asked to.
+		o.ConsecutiveGatewayFailure = protobuf.UInt32(od.ConsecutiveGatewayErrors)
+		o.EnforcingConsecutiveGatewayFailure = protobuf.UInt32(100)
+	}
+	return o
+}
+
 func edshealthcheck(c *dag.Cluster) []*envoy_api_v2_core.HealthCheck {
 	if c.HTTPHealthCheckPolicy == nil && c.TCPHealthCheckPolicy == nil {
 		return nil
@@ -199,6 +261,22 @@ func Clustername(cluster *dag.Cluster) string {
 		buf += uv.CACertificate.Object.ObjectMeta.Name
 		buf += uv.SubjectName
 	}
+	if cluster.ConnectTimeout > 0 {
+		buf += cluster.ConnectTimeout.String()
+	}
+	if cc := cluster.ClientCertificate; cc != nil {
+		buf += cc.Namespace() + "/" + cc.Name()
+	}
+	if od := cluster.OutlierDetectionPolicy; od != nil {
+		buf += fmt.Sprintf("od:%d/%d/%s/%s/%d;",
+			od.ConsecutiveServerErrors, od.ConsecutiveGatewayErrors,
+			od.Interval, od.BaseEjectionTime, od.MaxEjectionPercent)
+	}
+	if cb := cluster.CircuitBreakerPolicy; cb != nil {
+		buf += fmt.Sprintf("cb:%d/%d/%d/%d;",
+			cb.MaxConnections, cb.MaxPendingRequests, cb.MaxRequests, cb.MaxRetries)
+	}
+	buf += cluster.ProxyProtocol
 
 	hash := sha1.Sum([]byte(buf))
 	ns := service.Namespace
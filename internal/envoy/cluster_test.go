@@ -79,6 +79,19 @@ func TestCluster(t *testing.T) {
 		},
 	}
 
+	clientSecret := &dag.Secret{
+		Object: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "clientcert",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				v1.TLSCertKey:       []byte("cert"),
+				v1.TLSPrivateKeyKey: []byte("key"),
+			},
+		},
+	}
+
 	tests := map[string]struct {
 		cluster *dag.Cluster
 		want    *v2.Cluster
@@ -127,7 +140,7 @@ func TestCluster(t *testing.T) {
 					ServiceName: "default/kuard/http",
 				},
 				TransportSocket: UpstreamTLSTransportSocket(
-					UpstreamTLSContext(nil, "", "", "h2"),
+					UpstreamTLSContext(nil, "", "", nil, "h2"),
 				),
 				Http2ProtocolOptions: &envoy_api_v2_core.Http2ProtocolOptions{},
 			},
@@ -157,7 +170,7 @@ func TestCluster(t *testing.T) {
 					ServiceName: "default/kuard/http",
 				},
 				TransportSocket: UpstreamTLSTransportSocket(
-					UpstreamTLSContext(nil, "", ""),
+					UpstreamTLSContext(nil, "", "", nil),
 				),
 			},
 		},
@@ -172,7 +185,7 @@ func TestCluster(t *testing.T) {
 				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_STRICT_DNS),
 				LoadAssignment:       StaticClusterLoadAssignment(service(svcExternal, "tls")),
 				TransportSocket: UpstreamTLSTransportSocket(
-					UpstreamTLSContext(nil, "", "projectcontour.local"),
+					UpstreamTLSContext(nil, "", "projectcontour.local", nil),
 				),
 			},
 		},
@@ -180,7 +193,7 @@ func TestCluster(t *testing.T) {
 			cluster: &dag.Cluster{
 				Upstream: service(s1, "tls"),
 				Protocol: "tls",
-				UpstreamValidation: &dag.UpstreamValidation{
+				UpstreamValidation: &dag.PeerValidationContext{
 					CACertificate: &dag.Secret{
 						Object: &v1.Secret{
 							ObjectMeta: metav1.ObjectMeta{
@@ -204,10 +217,82 @@ func TestCluster(t *testing.T) {
 					ServiceName: "default/kuard/http",
 				},
 				TransportSocket: UpstreamTLSTransportSocket(
-					UpstreamTLSContext([]byte("cacert"), "foo.bar.io", ""),
+					UpstreamTLSContext([]byte("cacert"), "foo.bar.io", "", nil),
+				),
+			},
+		},
+		"tls upstream with client certificate": {
+			cluster: &dag.Cluster{
+				Upstream:          service(s1, "tls"),
+				Protocol:          "tls",
+				ClientCertificate: clientSecret,
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/abd732ffaf",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext(nil, "", "", clientSecret),
+				),
+			},
+		},
+		"proxy protocol": {
+			cluster: &dag.Cluster{
+				Upstream:      service(s1),
+				ProxyProtocol: "v1",
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/5a6df72054",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamProxyProtocolTransportSocket("v1", nil),
+			},
+		},
+		"tls upstream with proxy protocol": {
+			cluster: &dag.Cluster{
+				Upstream:      service(s1, "tls"),
+				Protocol:      "tls",
+				ProxyProtocol: "v2",
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/a1047eab10",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamProxyProtocolTransportSocket("v2",
+					UpstreamTLSTransportSocket(
+						UpstreamTLSContext(nil, "", "", nil),
+					),
 				),
 			},
 		},
+		"connect timeout": {
+			cluster: &dag.Cluster{
+				Upstream:       service(s1),
+				ConnectTimeout: 1500 * time.Millisecond,
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/e9f5753712",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				ConnectTimeout: protobuf.Duration(1500 * time.Millisecond),
+			},
+		},
 		"projectcontour.io/max-connections": {
 			cluster: &dag.Cluster{
 				Upstream: &dag.Service{
@@ -231,6 +316,34 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"circuit breaker policy overrides annotations": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name: s1.Name, Namespace: s1.Namespace,
+					ServicePort:    &s1.Spec.Ports[0],
+					MaxConnections: 9000,
+				},
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{
+					MaxConnections: 100,
+					MaxRetries:     5,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/abb59c7d24",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CircuitBreakers: &envoy_cluster.CircuitBreakers{
+					Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
+						MaxConnections: protobuf.UInt32(100),
+						MaxRetries:     protobuf.UInt32(5),
+					}},
+				},
+			},
+		},
 		"projectcontour.io/max-pending-requests": {
 			cluster: &dag.Cluster{
 				Upstream: &dag.Service{
@@ -377,6 +490,35 @@ func TestCluster(t *testing.T) {
 				}},
 			},
 		},
+		"outlier detection": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				OutlierDetectionPolicy: &dag.OutlierDetectionPolicy{
+					ConsecutiveServerErrors:  5,
+					ConsecutiveGatewayErrors: 3,
+					Interval:                 10 * time.Second,
+					BaseEjectionTime:         30 * time.Second,
+					MaxEjectionPercent:       50,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/eef9662596",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				OutlierDetection: &envoy_cluster.OutlierDetection{
+					Consecutive_5Xx:                    protobuf.UInt32(5),
+					ConsecutiveGatewayFailure:          protobuf.UInt32(3),
+					EnforcingConsecutiveGatewayFailure: protobuf.UInt32(100),
+					Interval:                           protobuf.Duration(10 * time.Second),
+					BaseEjectionTime:                   protobuf.Duration(30 * time.Second),
+					MaxEjectionPercent:                 protobuf.UInt32(50),
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -462,7 +604,7 @@ func TestClustername(t *testing.T) {
 					},
 				},
 				LoadBalancerPolicy: "Random",
-				UpstreamValidation: &dag.UpstreamValidation{
+				UpstreamValidation: &dag.PeerValidationContext{
 					CACertificate: &dag.Secret{
 						Object: &v1.Secret{
 							ObjectMeta: metav1.ObjectMeta{
@@ -479,6 +621,43 @@ func TestClustername(t *testing.T) {
 			},
 			want: "default/backend/80/6bf46b7b3a",
 		},
+		"outlier detection": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:      "backend",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+				OutlierDetectionPolicy: &dag.OutlierDetectionPolicy{
+					ConsecutiveServerErrors: 5,
+					Interval:                10 * time.Second,
+				},
+			},
+			want: "default/backend/80/06ad03abaf",
+		},
+		"circuit breakers": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:      "backend",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{
+					MaxConnections: 100,
+				},
+			},
+			want: "default/backend/80/99e4a314e1",
+		},
 	}
 
 	for name, tc := range tests {
@@ -489,6 +668,78 @@ func TestClustername(t *testing.T) {
 	}
 }
 
+func TestClusternameDistinct(t *testing.T) {
+	cluster := func(c dag.Cluster) *dag.Cluster {
+		c.Upstream = &dag.Service{
+			Name:      "backend",
+			Namespace: "default",
+			ServicePort: &v1.ServicePort{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       80,
+				TargetPort: intstr.FromInt(6502),
+			},
+		}
+		return &c
+	}
+
+	tests := map[string][2]*dag.Cluster{
+		"outlier detection errors": {
+			cluster(dag.Cluster{
+				OutlierDetectionPolicy: &dag.OutlierDetectionPolicy{
+					ConsecutiveServerErrors:  1,
+					ConsecutiveGatewayErrors: 11,
+				},
+			}),
+			cluster(dag.Cluster{
+				OutlierDetectionPolicy: &dag.OutlierDetectionPolicy{
+					ConsecutiveServerErrors:  11,
+					ConsecutiveGatewayErrors: 1,
+				},
+			}),
+		},
+		"outlier detection max ejection percent and circuit breakers": {
+			cluster(dag.Cluster{
+				OutlierDetectionPolicy: &dag.OutlierDetectionPolicy{
+					MaxEjectionPercent: 1,
+				},
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{
+					MaxConnections: 20,
+				},
+			}),
+			cluster(dag.Cluster{
+				OutlierDetectionPolicy: &dag.OutlierDetectionPolicy{
+					MaxEjectionPercent: 12,
+				},
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{},
+			}),
+		},
+		"circuit breakers": {
+			cluster(dag.Cluster{
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{
+					MaxConnections:     1,
+					MaxPendingRequests: 23,
+				},
+			}),
+			cluster(dag.Cluster{
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{
+					MaxConnections:     12,
+					MaxPendingRequests: 3,
+				},
+			}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := Clustername(tc[0]), Clustername(tc[1])
+			if a == b {
+				t.Fatalf("expected distinct cluster names, got %q for both", a)
+			}
+		})
+	}
+}
+
 func TestLBPolicy(t *testing.T) {
 	tests := map[string]v2.Cluster_LbPolicy{
 		"WeightedLeastRequest": v2.Cluster_LEAST_REQUEST,
@@ -497,6 +748,7 @@ func TestLBPolicy(t *testing.T) {
 		"":                     v2.Cluster_ROUND_ROBIN,
 		"unknown":              v2.Cluster_ROUND_ROBIN,
 		"Cookie":               v2.Cluster_RING_HASH,
+		"RequestHash":          v2.Cluster_RING_HASH,
 
 		// RingHash and Maglev were removed as options in 0.13.
 		// See #1150
@@ -0,0 +1,88 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	gzip "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/gzip/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+// GzipConfig holds the parameters of the gzip HTTP filter.
+type GzipConfig struct {
+	// Disabled removes the gzip filter from the HTTP
+	// connection managers.
+	Disabled bool
+
+	// ContentTypes are the response content types which are
+	// compressed. If empty, Envoy's defaults are used.
+	ContentTypes []string
+
+	// MinContentLength is the minimum response size, in bytes,
+	// which is compressed. If zero, Envoy's default is used.
+	MinContentLength uint32
+
+	// CompressionLevel is one of "default", "best" or "speed".
+	// If empty, "default" is used.
+	CompressionLevel string
+}
+
+// gzipFilter returns the gzip HTTP filter for the supplied
+// configuration, or nil if compression is disabled.
+func gzipFilter(gc *GzipConfig) *http.HttpFilter {
+	switch {
+	case gc == nil:
+		return &http.HttpFilter{
+			Name: wellknown.Gzip,
+		}
+	case gc.Disabled:
+		return nil
+	}
+
+	config := &gzip.Gzip{
+		ContentType: gc.ContentTypes,
+	}
+	if gc.MinContentLength > 0 {
+		config.ContentLength = protobuf.UInt32(gc.MinContentLength)
+	}
+	switch gc.CompressionLevel {
+	case "best":
+		config.CompressionLevel = gzip.Gzip_CompressionLevel_BEST
+	case "speed":
+		config.CompressionLevel = gzip.Gzip_CompressionLevel_SPEED
+	default:
+		config.CompressionLevel = gzip.Gzip_CompressionLevel_DEFAULT
+	}
+
+	return &http.HttpFilter{
+		Name: wellknown.Gzip,
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: toAny(config),
+		},
+	}
+}
+
+// DisableCompressionHeaders returns the response headers which stop
+// the gzip filter compressing responses. Envoy cannot disable the
+// filter per virtual host, but it never compresses a response carrying
+// Cache-Control: no-transform. The header is also seen by clients and
+// intermediate caches, which must then not transform the response, so
+// it should only be added when the gzip filter is installed.
+func DisableCompressionHeaders() []*envoy_api_v2_core.HeaderValueOption {
+	return Headers(
+		AppendHeader("cache-control", "no-transform"),
+	)
+}
@@ -0,0 +1,94 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	gzip "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/gzip/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+func TestGzipFilter(t *testing.T) {
+	tests := map[string]struct {
+		gc   *GzipConfig
+		want *http.HttpFilter
+	}{
+		"nil config": {
+			gc: nil,
+			want: &http.HttpFilter{
+				Name: wellknown.Gzip,
+			},
+		},
+		"disabled": {
+			gc: &GzipConfig{
+				Disabled: true,
+			},
+			want: nil,
+		},
+		"tuned": {
+			gc: &GzipConfig{
+				ContentTypes:     []string{"text/html", "application/json"},
+				MinContentLength: 1024,
+				CompressionLevel: "speed",
+			},
+			want: &http.HttpFilter{
+				Name: wellknown.Gzip,
+				ConfigType: &http.HttpFilter_TypedConfig{
+					TypedConfig: toAny(&gzip.Gzip{
+						ContentType:      []string{"text/html", "application/json"},
+						ContentLength:    protobuf.UInt32(1024),
+						CompressionLevel: gzip.Gzip_CompressionLevel_SPEED,
+					}),
+				},
+			},
+		},
+		"best compression": {
+			gc: &GzipConfig{
+				CompressionLevel: "best",
+			},
+			want: &http.HttpFilter{
+				Name: wellknown.Gzip,
+				ConfigType: &http.HttpFilter_TypedConfig{
+					TypedConfig: toAny(&gzip.Gzip{
+						CompressionLevel: gzip.Gzip_CompressionLevel_BEST,
+					}),
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := gzipFilter(tc.gc)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDisableCompressionHeaders(t *testing.T) {
+	got := DisableCompressionHeaders()
+	want := []*envoy_api_v2_core.HeaderValueOption{{
+		Header: &envoy_api_v2_core.HeaderValue{
+			Key:   "cache-control",
+			Value: "no-transform",
+		},
+		Append: protobuf.Bool(true),
+	}}
+	assert.Equal(t, want, got)
+}
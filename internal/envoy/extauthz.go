@@ -0,0 +1,80 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"time"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	ext_authz "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/ext_authz/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+// ExternalAuthzFilter returns an HTTP external authorization filter
+// which sends check requests to the gRPC service reachable via the
+// supplied cluster.
+func ExternalAuthzFilter(cluster string, timeout time.Duration, failOpen bool) *http.HttpFilter {
+	grpc := &envoy_api_v2_core.GrpcService{
+		TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+			EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+				ClusterName: cluster,
+			},
+		},
+	}
+
+	// A zero timeout leaves Envoy's default of 200ms in place.
+	if timeout > 0 {
+		grpc.Timeout = protobuf.Duration(timeout)
+	}
+
+	return &http.HttpFilter{
+		Name: wellknown.HTTPExternalAuthorization,
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: toAny(&ext_authz.ExtAuthz{
+				Services: &ext_authz.ExtAuthz_GrpcService{
+					GrpcService: grpc,
+				},
+				FailureModeAllow:       failOpen,
+				IncludePeerCertificate: true,
+			}),
+		},
+	}
+}
+
+// ExternalAuthzConfig returns the per-route configuration of the
+// external authorization filter, or nil if the route uses the
+// defaults of the virtual host.
+func ExternalAuthzConfig(disabled bool, context map[string]string) *any.Any {
+	switch {
+	case disabled:
+		return toAny(&ext_authz.ExtAuthzPerRoute{
+			Override: &ext_authz.ExtAuthzPerRoute_Disabled{
+				Disabled: true,
+			},
+		})
+	case len(context) > 0:
+		return toAny(&ext_authz.ExtAuthzPerRoute{
+			Override: &ext_authz.ExtAuthzPerRoute_CheckSettings{
+				CheckSettings: &ext_authz.CheckSettings{
+					ContextExtensions: context,
+				},
+			},
+		})
+	default:
+		return nil
+	}
+}
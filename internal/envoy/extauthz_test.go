@@ -0,0 +1,119 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+	"time"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	ext_authz "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/ext_authz/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+func TestExternalAuthzFilter(t *testing.T) {
+	tests := map[string]struct {
+		timeout  time.Duration
+		failOpen bool
+		want     *ext_authz.ExtAuthz
+	}{
+		"default timeout, fail closed": {
+			want: &ext_authz.ExtAuthz{
+				Services: &ext_authz.ExtAuthz_GrpcService{
+					GrpcService: &envoy_api_v2_core.GrpcService{
+						TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+							EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+								ClusterName: "auth/extension/8080/da39a3ee5e",
+							},
+						},
+					},
+				},
+				IncludePeerCertificate: true,
+			},
+		},
+		"timeout, fail open": {
+			timeout:  time.Second,
+			failOpen: true,
+			want: &ext_authz.ExtAuthz{
+				Services: &ext_authz.ExtAuthz_GrpcService{
+					GrpcService: &envoy_api_v2_core.GrpcService{
+						TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+							EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+								ClusterName: "auth/extension/8080/da39a3ee5e",
+							},
+						},
+						Timeout: protobuf.Duration(time.Second),
+					},
+				},
+				FailureModeAllow:       true,
+				IncludePeerCertificate: true,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ExternalAuthzFilter("auth/extension/8080/da39a3ee5e", tc.timeout, tc.failOpen)
+			want := &http.HttpFilter{
+				Name: wellknown.HTTPExternalAuthorization,
+				ConfigType: &http.HttpFilter_TypedConfig{
+					TypedConfig: toAny(tc.want),
+				},
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestExternalAuthzConfig(t *testing.T) {
+	if got := ExternalAuthzConfig(false, nil); got != nil {
+		t.Fatalf("expected nil config for default route policy, got %v", got)
+	}
+
+	tests := map[string]struct {
+		disabled bool
+		context  map[string]string
+		want     *ext_authz.ExtAuthzPerRoute
+	}{
+		"disabled": {
+			disabled: true,
+			context:  map[string]string{"team": "a"},
+			want: &ext_authz.ExtAuthzPerRoute{
+				Override: &ext_authz.ExtAuthzPerRoute_Disabled{
+					Disabled: true,
+				},
+			},
+		},
+		"context": {
+			context: map[string]string{"team": "a"},
+			want: &ext_authz.ExtAuthzPerRoute{
+				Override: &ext_authz.ExtAuthzPerRoute_CheckSettings{
+					CheckSettings: &ext_authz.CheckSettings{
+						ContextExtensions: map[string]string{"team": "a"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ExternalAuthzConfig(tc.disabled, tc.context)
+			assert.Equal(t, toAny(tc.want), got)
+		})
+	}
+}
@@ -64,18 +64,130 @@ func Listener(name, address string, port int, lf []*envoy_api_v2_listener.Listen
 	return l
 }
 
-// HTTPConnectionManager creates a new HTTP Connection Manager filter
-// for the supplied route, access log, and client request timeout.
-func HTTPConnectionManager(routename string, accesslogger []*accesslog.AccessLog, requestTimeout time.Duration) *envoy_api_v2_listener.Filter {
+type httpConnectionManagerBuilder struct {
+	routeConfigName string
+	metricsPrefix   string
+	accessLoggers   []*accesslog.AccessLog
+	requestTimeout  time.Duration
+	tracing         *TracingConfig
+	numTrustedHops  uint32
+	skipXffAppend   bool
+	gzip            *GzipConfig
+	disableGRPCWeb  bool
+	filters         []*http.HttpFilter
+}
+
+// HTTPConnectionManagerBuilder returns a new builder for an
+// HTTP Connection Manager filter.
+func HTTPConnectionManagerBuilder() *httpConnectionManagerBuilder {
+	return &httpConnectionManagerBuilder{}
+}
+
+// RouteConfigName sets the name of the RDS route configuration.
+func (b *httpConnectionManagerBuilder) RouteConfigName(name string) *httpConnectionManagerBuilder {
+	b.routeConfigName = name
+	return b
+}
+
+// MetricsPrefix sets the stat prefix of the connection manager.
+func (b *httpConnectionManagerBuilder) MetricsPrefix(prefix string) *httpConnectionManagerBuilder {
+	b.metricsPrefix = prefix
+	return b
+}
+
+// AccessLoggers sets the access loggers of the connection manager.
+func (b *httpConnectionManagerBuilder) AccessLoggers(loggers []*accesslog.AccessLog) *httpConnectionManagerBuilder {
+	b.accessLoggers = loggers
+	return b
+}
+
+// RequestTimeout sets the client request timeout.
+func (b *httpConnectionManagerBuilder) RequestTimeout(timeout time.Duration) *httpConnectionManagerBuilder {
+	b.requestTimeout = timeout
+	return b
+}
+
+// Tracing enables tracing of requests with the supplied
+// configuration. Tracing is disabled if tc is nil.
+func (b *httpConnectionManagerBuilder) Tracing(tc *TracingConfig) *httpConnectionManagerBuilder {
+	b.tracing = tc
+	return b
+}
+
+// NumTrustedHops sets the number of additional proxy hops, from the
+// right of the X-Forwarded-For header, trusted when determining the
+// client address.
+func (b *httpConnectionManagerBuilder) NumTrustedHops(hops uint32) *httpConnectionManagerBuilder {
+	b.numTrustedHops = hops
+	return b
+}
+
+// SkipXffAppend stops the client address being appended
+// to the X-Forwarded-For header.
+func (b *httpConnectionManagerBuilder) SkipXffAppend(skip bool) *httpConnectionManagerBuilder {
+	b.skipXffAppend = skip
+	return b
+}
+
+// Gzip configures the gzip HTTP filter added by DefaultFilters.
+// If gc is nil the filter uses Envoy's default settings.
+func (b *httpConnectionManagerBuilder) Gzip(gc *GzipConfig) *httpConnectionManagerBuilder {
+	b.gzip = gc
+	return b
+}
+
+// DisableGRPCWeb stops DefaultFilters adding the grpc-web HTTP filter.
+func (b *httpConnectionManagerBuilder) DisableGRPCWeb(disable bool) *httpConnectionManagerBuilder {
+	b.disableGRPCWeb = disable
+	return b
+}
+
+// DefaultFilters adds the gzip, grpc-web, cors and router HTTP filters.
+// The gzip and grpc-web filters are omitted if they have been disabled.
+func (b *httpConnectionManagerBuilder) DefaultFilters() *httpConnectionManagerBuilder {
+	if f := gzipFilter(b.gzip); f != nil {
+		b.filters = append(b.filters, f)
+	}
+	if !b.disableGRPCWeb {
+		b.filters = append(b.filters, &http.HttpFilter{
+			Name: wellknown.GRPCWeb,
+		})
+	}
+	b.filters = append(b.filters,
+		&http.HttpFilter{
+			Name: wellknown.CORS,
+		},
+		&http.HttpFilter{
+			Name: wellknown.Router,
+		},
+	)
 
+	return b
+}
+
+// AddFilter adds the supplied HTTP filter. As the router filter must
+// be the last filter in the chain, if it is present the new filter is
+// inserted before it.
+func (b *httpConnectionManagerBuilder) AddFilter(f *http.HttpFilter) *httpConnectionManagerBuilder {
+	if n := len(b.filters); n > 0 && b.filters[n-1].Name == wellknown.Router {
+		b.filters = append(b.filters[:n-1], f, b.filters[n-1])
+		return b
+	}
+
+	b.filters = append(b.filters, f)
+	return b
+}
+
+// Get returns the HTTP Connection Manager filter.
+func (b *httpConnectionManagerBuilder) Get() *envoy_api_v2_listener.Filter {
 	return &envoy_api_v2_listener.Filter{
 		Name: wellknown.HTTPConnectionManager,
 		ConfigType: &envoy_api_v2_listener.Filter_TypedConfig{
 			TypedConfig: toAny(&http.HttpConnectionManager{
-				StatPrefix: routename,
+				StatPrefix: b.metricsPrefix,
 				RouteSpecifier: &http.HttpConnectionManager_Rds{
 					Rds: &http.Rds{
-						RouteConfigName: routename,
+						RouteConfigName: b.routeConfigName,
 						ConfigSource: &envoy_api_v2_core.ConfigSource{
 							ConfigSourceSpecifier: &envoy_api_v2_core.ConfigSource_ApiConfigSource{
 								ApiConfigSource: &envoy_api_v2_core.ApiConfigSource{
@@ -92,13 +204,7 @@ func HTTPConnectionManager(routename string, accesslogger []*accesslog.AccessLog
 						},
 					},
 				},
-				HttpFilters: []*http.HttpFilter{{
-					Name: wellknown.Gzip,
-				}, {
-					Name: wellknown.GRPCWeb,
-				}, {
-					Name: wellknown.Router,
-				}},
+				HttpFilters: b.filters,
 				CommonHttpProtocolOptions: &envoy_api_v2_core.HttpProtocolOptions{
 					// Sets the idle timeout for HTTP connections to 60 seconds.
 					// This is chosen as a rough default to stop idle connections wasting resources,
@@ -110,10 +216,13 @@ func HTTPConnectionManager(routename string, accesslogger []*accesslog.AccessLog
 					// a Host: header. See #537.
 					AcceptHttp_10: true,
 				},
-				AccessLog:        accesslogger,
-				UseRemoteAddress: protobuf.Bool(true),
-				NormalizePath:    protobuf.Bool(true),
-				RequestTimeout:   protobuf.Duration(requestTimeout),
+				AccessLog:         b.accessLoggers,
+				UseRemoteAddress:  protobuf.Bool(true),
+				XffNumTrustedHops: b.numTrustedHops,
+				SkipXffAppend:     b.skipXffAppend,
+				NormalizePath:     protobuf.Bool(true),
+				RequestTimeout:    protobuf.Duration(b.requestTimeout),
+				Tracing:           httpConnectionManagerTracing(b.tracing),
 
 				// issue #1487 pass through X-Request-Id if provided.
 				PreserveExternalRequestId: true,
@@ -122,6 +231,18 @@ func HTTPConnectionManager(routename string, accesslogger []*accesslog.AccessLog
 	}
 }
 
+// HTTPConnectionManager creates a new HTTP Connection Manager filter
+// for the supplied route, access log, and client request timeout.
+func HTTPConnectionManager(routename string, accesslogger []*accesslog.AccessLog, requestTimeout time.Duration) *envoy_api_v2_listener.Filter {
+	return HTTPConnectionManagerBuilder().
+		RouteConfigName(routename).
+		MetricsPrefix(routename).
+		AccessLoggers(accesslogger).
+		RequestTimeout(requestTimeout).
+		DefaultFilters().
+		Get()
+}
+
 // TCPProxy creates a new TCPProxy filter.
 func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*accesslog.AccessLog) *envoy_api_v2_listener.Filter {
 	// Set the idle timeout in seconds for connections through a TCP Proxy type filter.
@@ -129,6 +250,15 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*accesslog.
 	// https://github.com/projectcontour/contour/issues/1074
 	// Set to 9001 because now it's OVER NINE THOUSAND.
 	idleTimeout := protobuf.Duration(9001 * time.Second)
+	if tp := proxy.TimeoutPolicy; tp != nil {
+		switch {
+		case tp.IdleTimeout < 0:
+			// An idle timeout of zero disables the timeout.
+			idleTimeout = protobuf.Duration(0)
+		case tp.IdleTimeout > 0:
+			idleTimeout = protobuf.Duration(tp.IdleTimeout)
+		}
+	}
 
 	switch len(proxy.Clusters) {
 	case 1:
@@ -242,7 +372,9 @@ func FilterChains(filters ...*envoy_api_v2_listener.Filter) []*envoy_api_v2_list
 }
 
 // FilterChainTLS returns a TLS enabled envoy_api_v2_listener.FilterChain,
-func FilterChainTLS(domain string, secret *dag.Secret, filters []*envoy_api_v2_listener.Filter, tlsMinProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol, alpnProtos ...string) *envoy_api_v2_listener.FilterChain {
+// matching the supplied domain using SNI. domain may be a wildcard such as
+// *.example.com, in which case Envoy prefers any exact match over it.
+func FilterChainTLS(domain string, downstream *envoy_api_v2_auth.DownstreamTlsContext, filters []*envoy_api_v2_listener.Filter) *envoy_api_v2_listener.FilterChain {
 	fc := &envoy_api_v2_listener.FilterChain{
 		Filters: filters,
 		FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
@@ -250,14 +382,25 @@ func FilterChainTLS(domain string, secret *dag.Secret, filters []*envoy_api_v2_l
 		},
 	}
 	// attach certificate data to this listener if provided.
-	if secret != nil {
-		fc.TransportSocket = DownstreamTLSTransportSocket(
-			DownstreamTLSContext(Secretname(secret), tlsMinProtoVersion, alpnProtos...),
-		)
+	if downstream != nil {
+		fc.TransportSocket = DownstreamTLSTransportSocket(downstream)
 	}
 	return fc
 }
 
+// FilterChainTLSFallback returns a TLS enabled envoy_api_v2_listener.FilterChain
+// without a server name match. Envoy uses it for TLS connections whose SNI,
+// if any, does not match another filter chain.
+func FilterChainTLSFallback(downstream *envoy_api_v2_auth.DownstreamTlsContext, filters []*envoy_api_v2_listener.Filter) *envoy_api_v2_listener.FilterChain {
+	return &envoy_api_v2_listener.FilterChain{
+		Filters: filters,
+		FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+			TransportProtocol: "tls",
+		},
+		TransportSocket: DownstreamTLSTransportSocket(downstream),
+	}
+}
+
 // ListenerFilters returns a []*envoy_api_v2_listener.ListenerFilter for the supplied listener filters.
 func ListenerFilters(filters ...*envoy_api_v2_listener.ListenerFilter) []*envoy_api_v2_listener.ListenerFilter {
 	return filters
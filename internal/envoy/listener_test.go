@@ -25,6 +25,7 @@ import (
 	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
 	envoy_config_v2_tcpproxy "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/tcp_proxy/v2"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
 	"github.com/projectcontour/contour/internal/assert"
 	"github.com/projectcontour/contour/internal/dag"
@@ -160,44 +161,116 @@ func TestSocketAddress(t *testing.T) {
 func TestDownstreamTLSContext(t *testing.T) {
 	const secretName = "default/tls-cert"
 
-	got := DownstreamTLSContext(secretName, envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1")
-	want := &envoy_api_v2_auth.DownstreamTlsContext{
-		CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
-			TlsParams: &envoy_api_v2_auth.TlsParameters{
-				TlsMinimumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_1,
-				TlsMaximumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_3,
-				CipherSuites: []string{
-					"[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]",
-					"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]",
-					"ECDHE-ECDSA-AES128-SHA",
-					"ECDHE-RSA-AES128-SHA",
-					"ECDHE-ECDSA-AES256-GCM-SHA384",
-					"ECDHE-RSA-AES256-GCM-SHA384",
-					"ECDHE-ECDSA-AES256-SHA",
-					"ECDHE-RSA-AES256-SHA",
+	tlsParams := &envoy_api_v2_auth.TlsParameters{
+		TlsMinimumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_1,
+		TlsMaximumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_3,
+		CipherSuites: []string{
+			"[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]",
+			"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]",
+			"ECDHE-ECDSA-AES128-SHA",
+			"ECDHE-RSA-AES128-SHA",
+			"ECDHE-ECDSA-AES256-GCM-SHA384",
+			"ECDHE-RSA-AES256-GCM-SHA384",
+			"ECDHE-ECDSA-AES256-SHA",
+			"ECDHE-RSA-AES256-SHA",
+		},
+	}
+
+	tlsCertificateSdsSecretConfigs := []*envoy_api_v2_auth.SdsSecretConfig{{
+		Name: secretName,
+		SdsConfig: &envoy_api_v2_core.ConfigSource{
+			ConfigSourceSpecifier: &envoy_api_v2_core.ConfigSource_ApiConfigSource{
+				ApiConfigSource: &envoy_api_v2_core.ApiConfigSource{
+					ApiType: envoy_api_v2_core.ApiConfigSource_GRPC,
+					GrpcServices: []*envoy_api_v2_core.GrpcService{{
+						TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+							EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+								ClusterName: "contour",
+							},
+						},
+					}},
 				},
 			},
-			TlsCertificateSdsSecretConfigs: []*envoy_api_v2_auth.SdsSecretConfig{{
-				Name: secretName,
-				SdsConfig: &envoy_api_v2_core.ConfigSource{
-					ConfigSourceSpecifier: &envoy_api_v2_core.ConfigSource_ApiConfigSource{
-						ApiConfigSource: &envoy_api_v2_core.ApiConfigSource{
-							ApiType: envoy_api_v2_core.ApiConfigSource_GRPC,
-							GrpcServices: []*envoy_api_v2_core.GrpcService{{
-								TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
-									EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
-										ClusterName: "contour",
-									},
+		},
+	}}
+
+	alpnProtos := []string{"h2", "http/1.1"}
+
+	peerValidationContext := &dag.PeerValidationContext{
+		CACertificate: &dag.Secret{
+			Object: &v1.Secret{
+				Data: map[string][]byte{
+					dag.CACertificateKey: []byte("ca"),
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		got  *envoy_api_v2_auth.DownstreamTlsContext
+		want *envoy_api_v2_auth.DownstreamTlsContext
+	}{
+		"TLS context without client authentication": {
+			DownstreamTLSContext(secretName, envoy_api_v2_auth.TlsParameters_TLSv1_1, envoy_api_v2_auth.TlsParameters_TLSv1_3, nil, nil, "h2", "http/1.1"),
+			&envoy_api_v2_auth.DownstreamTlsContext{
+				CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+					TlsParams:                      tlsParams,
+					TlsCertificateSdsSecretConfigs: tlsCertificateSdsSecretConfigs,
+					AlpnProtocols:                  alpnProtos,
+				},
+			},
+		},
+		"TLS context with client authentication": {
+			DownstreamTLSContext(secretName, envoy_api_v2_auth.TlsParameters_TLSv1_1, envoy_api_v2_auth.TlsParameters_TLSv1_3, nil, peerValidationContext, "h2", "http/1.1"),
+			&envoy_api_v2_auth.DownstreamTlsContext{
+				CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+					TlsParams:                      tlsParams,
+					TlsCertificateSdsSecretConfigs: tlsCertificateSdsSecretConfigs,
+					AlpnProtocols:                  alpnProtos,
+					ValidationContextType: &envoy_api_v2_auth.CommonTlsContext_ValidationContext{
+						ValidationContext: &envoy_api_v2_auth.CertificateValidationContext{
+							TrustedCa: &envoy_api_v2_core.DataSource{
+								Specifier: &envoy_api_v2_core.DataSource_InlineBytes{
+									InlineBytes: []byte("ca"),
 								},
-							}},
+							},
 						},
 					},
 				},
-			}},
-			AlpnProtocols: []string{"h2", "http/1.1"},
+				RequireClientCertificate: protobuf.Bool(true),
+			},
+		},
+		"TLS context with maximum version and cipher suites": {
+			DownstreamTLSContext(secretName, envoy_api_v2_auth.TlsParameters_TLSv1_2, envoy_api_v2_auth.TlsParameters_TLSv1_2, []string{"ECDHE-RSA-AES256-GCM-SHA384"}, nil, "h2", "http/1.1"),
+			&envoy_api_v2_auth.DownstreamTlsContext{
+				CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+					TlsParams: &envoy_api_v2_auth.TlsParameters{
+						TlsMinimumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+						TlsMaximumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+						CipherSuites:              []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+					},
+					TlsCertificateSdsSecretConfigs: tlsCertificateSdsSecretConfigs,
+					AlpnProtocols:                  alpnProtos,
+				},
+			},
+		},
+		"TLS context with automatic maximum version": {
+			DownstreamTLSContext(secretName, envoy_api_v2_auth.TlsParameters_TLSv1_1, envoy_api_v2_auth.TlsParameters_TLS_AUTO, nil, nil, "h2", "http/1.1"),
+			&envoy_api_v2_auth.DownstreamTlsContext{
+				CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+					TlsParams:                      tlsParams,
+					TlsCertificateSdsSecretConfigs: tlsCertificateSdsSecretConfigs,
+					AlpnProtocols:                  alpnProtos,
+				},
+			},
 		},
 	}
-	assert.Equal(t, want, got)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.got)
+		})
+	}
 }
 
 func TestHTTPConnectionManager(t *testing.T) {
@@ -239,6 +312,8 @@ func TestHTTPConnectionManager(t *testing.T) {
 							Name: wellknown.Gzip,
 						}, {
 							Name: wellknown.GRPCWeb,
+						}, {
+							Name: wellknown.CORS,
 						}, {
 							Name: wellknown.Router,
 						}},
@@ -291,6 +366,8 @@ func TestHTTPConnectionManager(t *testing.T) {
 							Name: wellknown.Gzip,
 						}, {
 							Name: wellknown.GRPCWeb,
+						}, {
+							Name: wellknown.CORS,
 						}, {
 							Name: wellknown.Router,
 						}},
@@ -320,6 +397,103 @@ func TestHTTPConnectionManager(t *testing.T) {
 	}
 }
 
+func TestHTTPConnectionManagerBuilderAddFilter(t *testing.T) {
+	names := func(b *httpConnectionManagerBuilder) []string {
+		var names []string
+		for _, f := range b.filters {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	tests := map[string]struct {
+		builder *httpConnectionManagerBuilder
+		want    []string
+	}{
+		"no default filters": {
+			builder: HTTPConnectionManagerBuilder().
+				AddFilter(&http.HttpFilter{Name: wellknown.HTTPRateLimit}),
+			want: []string{wellknown.HTTPRateLimit},
+		},
+		"filter is inserted before the router": {
+			builder: HTTPConnectionManagerBuilder().
+				DefaultFilters().
+				AddFilter(&http.HttpFilter{Name: wellknown.HTTPRateLimit}),
+			want: []string{wellknown.Gzip, wellknown.GRPCWeb, wellknown.CORS, wellknown.HTTPRateLimit, wellknown.Router},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, names(tc.builder))
+		})
+	}
+}
+
+func TestHTTPConnectionManagerBuilderXff(t *testing.T) {
+	tests := map[string]struct {
+		builder           *httpConnectionManagerBuilder
+		wantTrustedHops   uint32
+		wantSkipXffAppend bool
+	}{
+		"default": {
+			builder: HTTPConnectionManagerBuilder(),
+		},
+		"trusted hops": {
+			builder:         HTTPConnectionManagerBuilder().NumTrustedHops(2),
+			wantTrustedHops: 2,
+		},
+		"skip xff append": {
+			builder:           HTTPConnectionManagerBuilder().SkipXffAppend(true),
+			wantSkipXffAppend: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var hcm http.HttpConnectionManager
+			err := ptypes.UnmarshalAny(tc.builder.Get().GetTypedConfig(), &hcm)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, protobuf.Bool(true), hcm.UseRemoteAddress)
+			assert.Equal(t, tc.wantTrustedHops, hcm.XffNumTrustedHops)
+			assert.Equal(t, tc.wantSkipXffAppend, hcm.SkipXffAppend)
+		})
+	}
+}
+
+func TestHTTPConnectionManagerBuilderDefaultFilters(t *testing.T) {
+	tests := map[string]struct {
+		builder *httpConnectionManagerBuilder
+		want    []string
+	}{
+		"default": {
+			builder: HTTPConnectionManagerBuilder(),
+			want:    []string{wellknown.Gzip, wellknown.GRPCWeb, wellknown.CORS, wellknown.Router},
+		},
+		"gzip disabled": {
+			builder: HTTPConnectionManagerBuilder().Gzip(&GzipConfig{Disabled: true}),
+			want:    []string{wellknown.GRPCWeb, wellknown.CORS, wellknown.Router},
+		},
+		"grpc-web disabled": {
+			builder: HTTPConnectionManagerBuilder().DisableGRPCWeb(true),
+			want:    []string{wellknown.Gzip, wellknown.CORS, wellknown.Router},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var hcm http.HttpConnectionManager
+			err := ptypes.UnmarshalAny(tc.builder.DefaultFilters().Get().GetTypedConfig(), &hcm)
+			assert.Equal(t, nil, err)
+			var got []string
+			for _, f := range hcm.HttpFilters {
+				got = append(got, f.Name)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestTCPProxy(t *testing.T) {
 	const (
 		statPrefix    = "ingress_https"
@@ -398,6 +572,69 @@ func TestTCPProxy(t *testing.T) {
 				},
 			},
 		},
+		"idle timeout": {
+			proxy: &dag.TCPProxy{
+				Clusters: []*dag.Cluster{c1},
+				TimeoutPolicy: &dag.TCPProxyTimeoutPolicy{
+					IdleTimeout: 2 * time.Hour,
+				},
+			},
+			want: &envoy_api_v2_listener.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_api_v2_listener.Filter_TypedConfig{
+					TypedConfig: toAny(&envoy_config_v2_tcpproxy.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_config_v2_tcpproxy.TcpProxy_Cluster{
+							Cluster: Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath),
+						IdleTimeout: protobuf.Duration(2 * time.Hour),
+					}),
+				},
+			},
+		},
+		"infinite idle timeout": {
+			proxy: &dag.TCPProxy{
+				Clusters: []*dag.Cluster{c1},
+				TimeoutPolicy: &dag.TCPProxyTimeoutPolicy{
+					IdleTimeout: -1,
+				},
+			},
+			want: &envoy_api_v2_listener.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_api_v2_listener.Filter_TypedConfig{
+					TypedConfig: toAny(&envoy_config_v2_tcpproxy.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_config_v2_tcpproxy.TcpProxy_Cluster{
+							Cluster: Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath),
+						IdleTimeout: protobuf.Duration(0),
+					}),
+				},
+			},
+		},
+		"connect timeout only": {
+			proxy: &dag.TCPProxy{
+				Clusters: []*dag.Cluster{c1},
+				TimeoutPolicy: &dag.TCPProxyTimeoutPolicy{
+					ConnectTimeout: time.Second,
+				},
+			},
+			want: &envoy_api_v2_listener.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_api_v2_listener.Filter_TypedConfig{
+					TypedConfig: toAny(&envoy_config_v2_tcpproxy.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_config_v2_tcpproxy.TcpProxy_Cluster{
+							Cluster: Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath),
+						IdleTimeout: protobuf.Duration(9001 * time.Second),
+					}),
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
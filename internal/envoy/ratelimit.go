@@ -0,0 +1,188 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"fmt"
+	"time"
+
+	udpa_type_v1 "github.com/cncf/udpa/go/udpa/type/v1"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	ratelimit_filter "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/rate_limit/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	ratelimit_config "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/any"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+// GlobalRateLimits converts DAG RateLimitDescriptors to Envoy RateLimits.
+func GlobalRateLimits(descriptors []*dag.RateLimitDescriptor) []*envoy_api_v2_route.RateLimit {
+	var rateLimits []*envoy_api_v2_route.RateLimit
+	for _, descriptor := range descriptors {
+		var rl envoy_api_v2_route.RateLimit
+
+		for _, entry := range descriptor.Entries {
+			switch {
+			case entry.GenericKey != nil:
+				rl.Actions = append(rl.Actions, &envoy_api_v2_route.RateLimit_Action{
+					ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_GenericKey_{
+						GenericKey: &envoy_api_v2_route.RateLimit_Action_GenericKey{
+							DescriptorValue: entry.GenericKey.Value,
+						},
+					},
+				})
+			case entry.HeaderMatch != nil:
+				rl.Actions = append(rl.Actions, &envoy_api_v2_route.RateLimit_Action{
+					ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_RequestHeaders_{
+						RequestHeaders: &envoy_api_v2_route.RateLimit_Action_RequestHeaders{
+							HeaderName:    entry.HeaderMatch.HeaderName,
+							DescriptorKey: entry.HeaderMatch.Key,
+						},
+					},
+				})
+			case entry.RemoteAddress != nil:
+				rl.Actions = append(rl.Actions, &envoy_api_v2_route.RateLimit_Action{
+					ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_RemoteAddress_{
+						RemoteAddress: &envoy_api_v2_route.RateLimit_Action_RemoteAddress{},
+					},
+				})
+			}
+		}
+
+		rateLimits = append(rateLimits, &rl)
+	}
+
+	return rateLimits
+}
+
+// GlobalRateLimitFilter returns a configured HTTP global rate limit filter
+// which sends rate limit requests to the gRPC service reachable via the
+// supplied cluster.
+func GlobalRateLimitFilter(cluster, domain string, timeout time.Duration, failOpen bool) *http.HttpFilter {
+	config := &ratelimit_filter.RateLimit{
+		Domain:          domain,
+		FailureModeDeny: !failOpen,
+		RateLimitService: &ratelimit_config.RateLimitServiceConfig{
+			GrpcService: &envoy_api_v2_core.GrpcService{
+				TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+						ClusterName: cluster,
+					},
+				},
+			},
+		},
+	}
+
+	// A zero timeout leaves Envoy's default of 20ms in place.
+	if timeout > 0 {
+		config.Timeout = protobuf.Duration(timeout)
+	}
+
+	return &http.HttpFilter{
+		Name: wellknown.HTTPRateLimit,
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: toAny(config),
+		},
+	}
+}
+
+// LocalRateLimitFilterName is the name of Envoy's HTTP local rate limit
+// filter. It is also the key for per-route and per-virtual host
+// configuration of the filter.
+const LocalRateLimitFilterName = "envoy.filters.http.local_ratelimit"
+
+// localRateLimitTypeURL is the type of the local rate limit filter
+// configuration. The vendored go-control-plane predates this filter,
+// so its configuration is passed to Envoy as a TypedStruct.
+const localRateLimitTypeURL = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+
+// LocalRateLimitConfig returns a config for the HTTP local rate
+// limit filter, suitable for use as per-route or per-virtual host
+// filter configuration.
+func LocalRateLimitConfig(config *dag.LocalRateLimitPolicy, statPrefix string) *any.Any {
+	if config == nil {
+		return nil
+	}
+
+	fields := map[string]*_struct.Value{
+		"stat_prefix": sv(statPrefix),
+		"token_bucket": structValue(map[string]*_struct.Value{
+			"max_tokens":      numberValue(config.MaxTokens),
+			"tokens_per_fill": numberValue(config.TokensPerFill),
+			"fill_interval":   sv(durationString(config.FillInterval)),
+		}),
+		"filter_enabled":  runtimeFractionalPercentValue("local_rate_limit_enabled"),
+		"filter_enforced": runtimeFractionalPercentValue("local_rate_limit_enforced"),
+	}
+
+	// A zero status code leaves Envoy's default of 429 in place.
+	if config.ResponseStatusCode > 0 {
+		fields["status"] = structValue(map[string]*_struct.Value{
+			"code": numberValue(config.ResponseStatusCode),
+		})
+	}
+
+	return toAny(&udpa_type_v1.TypedStruct{
+		TypeUrl: localRateLimitTypeURL,
+		Value:   &_struct.Struct{Fields: fields},
+	})
+}
+
+// LocalRateLimitFilter returns an HTTP local rate limit filter with
+// no token bucket of its own. Requests are only rate limited on the
+// routes and virtual hosts that configure the filter.
+func LocalRateLimitFilter(statPrefix string) *http.HttpFilter {
+	return &http.HttpFilter{
+		Name: LocalRateLimitFilterName,
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: toAny(&udpa_type_v1.TypedStruct{
+				TypeUrl: localRateLimitTypeURL,
+				Value: &_struct.Struct{
+					Fields: map[string]*_struct.Value{
+						"stat_prefix": sv(statPrefix),
+					},
+				},
+			}),
+		},
+	}
+}
+
+// runtimeFractionalPercentValue returns a RuntimeFractionalPercent
+// which defaults to 100%, overridable by the supplied runtime key.
+func runtimeFractionalPercentValue(runtimeKey string) *_struct.Value {
+	return structValue(map[string]*_struct.Value{
+		"default_value": structValue(map[string]*_struct.Value{
+			"numerator":   numberValue(100),
+			"denominator": sv("HUNDRED"),
+		}),
+		"runtime_key": sv(runtimeKey),
+	})
+}
+
+// durationString formats d as a protobuf JSON duration.
+func durationString(d time.Duration) string {
+	return fmt.Sprintf("%gs", d.Seconds())
+}
+
+func numberValue(n uint32) *_struct.Value {
+	return &_struct.Value{Kind: &_struct.Value_NumberValue{NumberValue: float64(n)}}
+}
+
+func structValue(fields map[string]*_struct.Value) *_struct.Value {
+	return &_struct.Value{Kind: &_struct.Value_StructValue{StructValue: &_struct.Struct{Fields: fields}}}
+}
@@ -0,0 +1,245 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+	"time"
+
+	udpa_type_v1 "github.com/cncf/udpa/go/udpa/type/v1"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	ratelimit_filter "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/rate_limit/v2"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	ratelimit_config "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/jsonpb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+func TestGlobalRateLimits(t *testing.T) {
+	tests := map[string]struct {
+		descriptors []*dag.RateLimitDescriptor
+		want        []*envoy_api_v2_route.RateLimit
+	}{
+		"nil descriptors": {
+			descriptors: nil,
+			want:        nil,
+		},
+		"one descriptor with one entry": {
+			descriptors: []*dag.RateLimitDescriptor{{
+				Entries: []dag.RateLimitDescriptorEntry{{
+					RemoteAddress: &dag.RemoteAddressDescriptorEntry{},
+				}},
+			}},
+			want: []*envoy_api_v2_route.RateLimit{{
+				Actions: []*envoy_api_v2_route.RateLimit_Action{{
+					ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_RemoteAddress_{
+						RemoteAddress: &envoy_api_v2_route.RateLimit_Action_RemoteAddress{},
+					},
+				}},
+			}},
+		},
+		"multiple descriptors with multiple entries": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							GenericKey: &dag.GenericKeyDescriptorEntry{
+								Value: "generic-key-value",
+							},
+						},
+						{
+							HeaderMatch: &dag.HeaderMatchDescriptorEntry{
+								HeaderName: "X-Header",
+								Key:        "header-key",
+							},
+						},
+					},
+				},
+				{
+					Entries: []dag.RateLimitDescriptorEntry{{
+						RemoteAddress: &dag.RemoteAddressDescriptorEntry{},
+					}},
+				},
+			},
+			want: []*envoy_api_v2_route.RateLimit{
+				{
+					Actions: []*envoy_api_v2_route.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_GenericKey_{
+								GenericKey: &envoy_api_v2_route.RateLimit_Action_GenericKey{
+									DescriptorValue: "generic-key-value",
+								},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_RequestHeaders_{
+								RequestHeaders: &envoy_api_v2_route.RateLimit_Action_RequestHeaders{
+									HeaderName:    "X-Header",
+									DescriptorKey: "header-key",
+								},
+							},
+						},
+					},
+				},
+				{
+					Actions: []*envoy_api_v2_route.RateLimit_Action{{
+						ActionSpecifier: &envoy_api_v2_route.RateLimit_Action_RemoteAddress_{
+							RemoteAddress: &envoy_api_v2_route.RateLimit_Action_RemoteAddress{},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, GlobalRateLimits(tc.descriptors))
+		})
+	}
+}
+
+func TestGlobalRateLimitFilter(t *testing.T) {
+	tests := map[string]struct {
+		timeout  time.Duration
+		failOpen bool
+		want     *ratelimit_filter.RateLimit
+	}{
+		"default timeout, fail closed": {
+			want: &ratelimit_filter.RateLimit{
+				Domain:          "contour",
+				FailureModeDeny: true,
+				RateLimitService: &ratelimit_config.RateLimitServiceConfig{
+					GrpcService: &envoy_api_v2_core.GrpcService{
+						TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+							EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+								ClusterName: "ratelimit",
+							},
+						},
+					},
+				},
+			},
+		},
+		"timeout, fail open": {
+			timeout:  100 * time.Millisecond,
+			failOpen: true,
+			want: &ratelimit_filter.RateLimit{
+				Domain:          "contour",
+				Timeout:         protobuf.Duration(100 * time.Millisecond),
+				FailureModeDeny: false,
+				RateLimitService: &ratelimit_config.RateLimitServiceConfig{
+					GrpcService: &envoy_api_v2_core.GrpcService{
+						TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+							EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+								ClusterName: "ratelimit",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := GlobalRateLimitFilter("ratelimit", "contour", tc.timeout, tc.failOpen)
+			want := &http.HttpFilter{
+				Name: wellknown.HTTPRateLimit,
+				ConfigType: &http.HttpFilter_TypedConfig{
+					TypedConfig: toAny(tc.want),
+				},
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestLocalRateLimitConfig(t *testing.T) {
+	if got := LocalRateLimitConfig(nil, "vhost.www.example.com"); got != nil {
+		t.Fatalf("expected nil config for nil policy, got %v", got)
+	}
+
+	tests := map[string]struct {
+		policy *dag.LocalRateLimitPolicy
+		want   string
+	}{
+		"requests per minute with burst": {
+			policy: &dag.LocalRateLimitPolicy{
+				MaxTokens:     15,
+				TokensPerFill: 10,
+				FillInterval:  time.Minute,
+			},
+			want: `{
+				"stat_prefix": "vhost.www.example.com",
+				"token_bucket": {"max_tokens": 15, "tokens_per_fill": 10, "fill_interval": "60s"},
+				"filter_enabled": {"default_value": {"numerator": 100, "denominator": "HUNDRED"}, "runtime_key": "local_rate_limit_enabled"},
+				"filter_enforced": {"default_value": {"numerator": 100, "denominator": "HUNDRED"}, "runtime_key": "local_rate_limit_enforced"}
+			}`,
+		},
+		"custom response status code": {
+			policy: &dag.LocalRateLimitPolicy{
+				MaxTokens:          1,
+				TokensPerFill:      1,
+				FillInterval:       time.Second,
+				ResponseStatusCode: 503,
+			},
+			want: `{
+				"stat_prefix": "vhost.www.example.com",
+				"token_bucket": {"max_tokens": 1, "tokens_per_fill": 1, "fill_interval": "1s"},
+				"filter_enabled": {"default_value": {"numerator": 100, "denominator": "HUNDRED"}, "runtime_key": "local_rate_limit_enabled"},
+				"filter_enforced": {"default_value": {"numerator": 100, "denominator": "HUNDRED"}, "runtime_key": "local_rate_limit_enforced"},
+				"status": {"code": 503}
+			}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := LocalRateLimitConfig(tc.policy, "vhost.www.example.com")
+
+			var value _struct.Struct
+			if err := jsonpb.UnmarshalString(tc.want, &value); err != nil {
+				t.Fatal(err)
+			}
+			want := toAny(&udpa_type_v1.TypedStruct{
+				TypeUrl: "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit",
+				Value:   &value,
+			})
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestLocalRateLimitFilter(t *testing.T) {
+	got := LocalRateLimitFilter("ingress_http")
+	want := &http.HttpFilter{
+		Name: "envoy.filters.http.local_ratelimit",
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: toAny(&udpa_type_v1.TypedStruct{
+				TypeUrl: "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit",
+				Value: &_struct.Struct{
+					Fields: map[string]*_struct.Value{
+						"stat_prefix": sv("ingress_http"),
+					},
+				},
+			}),
+		},
+	}
+	assert.Equal(t, want, got)
+}
@@ -18,6 +18,7 @@ import (
 
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/protobuf"
 )
 
@@ -31,7 +32,7 @@ func TestSafeRegexMatch(t *testing.T) {
 			want: &matcher.RegexMatcher{
 				EngineType: &matcher.RegexMatcher_GoogleRe2{
 					GoogleRe2: &matcher.RegexMatcher_GoogleRE2{
-						MaxProgramSize: protobuf.UInt32(maxRegexProgramSize),
+						MaxProgramSize: protobuf.UInt32(dag.MaxRegexProgramSize),
 					},
 				},
 			},
@@ -41,7 +42,7 @@ func TestSafeRegexMatch(t *testing.T) {
 			want: &matcher.RegexMatcher{
 				EngineType: &matcher.RegexMatcher_GoogleRe2{
 					GoogleRe2: &matcher.RegexMatcher_GoogleRE2{
-						MaxProgramSize: protobuf.UInt32(maxRegexProgramSize),
+						MaxProgramSize: protobuf.UInt32(dag.MaxRegexProgramSize),
 					},
 				},
 				Regex: "chrome",
@@ -52,7 +53,7 @@ func TestSafeRegexMatch(t *testing.T) {
 			want: &matcher.RegexMatcher{
 				EngineType: &matcher.RegexMatcher_GoogleRe2{
 					GoogleRe2: &matcher.RegexMatcher_GoogleRE2{
-						MaxProgramSize: protobuf.UInt32(maxRegexProgramSize),
+						MaxProgramSize: protobuf.UInt32(dag.MaxRegexProgramSize),
 					},
 				},
 				Regex: "[a-z]+$", // meta characters are not escaped.
@@ -15,13 +15,16 @@ package envoy
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
 	"github.com/golang/protobuf/ptypes/duration"
 	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/projectcontour/contour/internal/dag"
@@ -36,18 +39,29 @@ func RouteMatch(route *dag.Route) *envoy_api_v2_route.RouteMatch {
 			PathSpecifier: &envoy_api_v2_route.RouteMatch_SafeRegex{
 				SafeRegex: SafeRegexMatch(c.Regex),
 			},
-			Headers: headerMatcher(route.HeaderConditions),
+			Headers:         headerMatcher(route.HeaderConditions),
+			QueryParameters: queryParamMatcher(route.QueryParamConditions),
 		}
 	case *dag.PrefixCondition:
 		return &envoy_api_v2_route.RouteMatch{
 			PathSpecifier: &envoy_api_v2_route.RouteMatch_Prefix{
 				Prefix: c.Prefix,
 			},
-			Headers: headerMatcher(route.HeaderConditions),
+			Headers:         headerMatcher(route.HeaderConditions),
+			QueryParameters: queryParamMatcher(route.QueryParamConditions),
+		}
+	case *dag.ExactCondition:
+		return &envoy_api_v2_route.RouteMatch{
+			PathSpecifier: &envoy_api_v2_route.RouteMatch_Path{
+				Path: c.Path,
+			},
+			Headers:         headerMatcher(route.HeaderConditions),
+			QueryParameters: queryParamMatcher(route.QueryParamConditions),
 		}
 	default:
 		return &envoy_api_v2_route.RouteMatch{
-			Headers: headerMatcher(route.HeaderConditions),
+			Headers:         headerMatcher(route.HeaderConditions),
+			QueryParameters: queryParamMatcher(route.QueryParamConditions),
 		}
 	}
 }
@@ -65,6 +79,10 @@ func RouteRoute(r *dag.Route) *envoy_api_v2_route.Route_Route {
 		RequestMirrorPolicies: mirrorPolicy(r),
 	}
 
+	if r.RateLimitPolicy != nil && r.RateLimitPolicy.Global != nil {
+		ra.RateLimits = GlobalRateLimits(r.RateLimitPolicy.Global.Descriptors)
+	}
+
 	// Check for host header policy and set if found
 	if val := hostReplaceHeader(r.RequestHeadersPolicy); val != "" {
 		ra.HostRewriteSpecifier = &envoy_api_v2_route.RouteAction_HostRewrite{
@@ -94,9 +112,14 @@ func RouteRoute(r *dag.Route) *envoy_api_v2_route.Route_Route {
 	}
 }
 
-// hashPolicy returns a slice of hash policies iff at least one of the route's
-// clusters supplied uses the `Cookie` load balancing stategy.
+// hashPolicy returns a slice of hash policies iff the route has request
+// hash policies, or at least one of the route's clusters supplied uses the
+// `Cookie` load balancing stategy.
 func hashPolicy(r *dag.Route) []*envoy_api_v2_route.RouteAction_HashPolicy {
+	if len(r.RequestHashPolicies) > 0 {
+		return requestHashPolicies(r.RequestHashPolicies)
+	}
+
 	for _, c := range r.Clusters {
 		if c.LoadBalancerPolicy == "Cookie" {
 			return []*envoy_api_v2_route.RouteAction_HashPolicy{{
@@ -113,6 +136,49 @@ func hashPolicy(r *dag.Route) []*envoy_api_v2_route.RouteAction_HashPolicy {
 	return nil
 }
 
+func requestHashPolicies(policies []dag.RequestHashPolicy) []*envoy_api_v2_route.RouteAction_HashPolicy {
+	var hp []*envoy_api_v2_route.RouteAction_HashPolicy
+	for _, p := range policies {
+		policy := &envoy_api_v2_route.RouteAction_HashPolicy{
+			Terminal: p.Terminal,
+		}
+
+		switch {
+		case p.HeaderHashOptions != nil:
+			policy.PolicySpecifier = &envoy_api_v2_route.RouteAction_HashPolicy_Header_{
+				Header: &envoy_api_v2_route.RouteAction_HashPolicy_Header{
+					HeaderName: p.HeaderHashOptions.HeaderName,
+				},
+			}
+		case p.CookieHashOptions != nil:
+			cookie := &envoy_api_v2_route.RouteAction_HashPolicy_Cookie{
+				Name: p.CookieHashOptions.CookieName,
+				Path: p.CookieHashOptions.Path,
+			}
+			if p.CookieHashOptions.TTL > 0 {
+				cookie.Ttl = protobuf.Duration(p.CookieHashOptions.TTL)
+			}
+			policy.PolicySpecifier = &envoy_api_v2_route.RouteAction_HashPolicy_Cookie_{
+				Cookie: cookie,
+			}
+		case p.QueryParameterHashOptions != nil:
+			policy.PolicySpecifier = &envoy_api_v2_route.RouteAction_HashPolicy_QueryParameter_{
+				QueryParameter: &envoy_api_v2_route.RouteAction_HashPolicy_QueryParameter{
+					Name: p.QueryParameterHashOptions.ParameterName,
+				},
+			}
+		case p.HashSourceIP:
+			policy.PolicySpecifier = &envoy_api_v2_route.RouteAction_HashPolicy_ConnectionProperties_{
+				ConnectionProperties: &envoy_api_v2_route.RouteAction_HashPolicy_ConnectionProperties{
+					SourceIp: true,
+				},
+			}
+		}
+		hp = append(hp, policy)
+	}
+	return hp
+}
+
 func mirrorPolicy(r *dag.Route) []*envoy_api_v2_route.RouteAction_RequestMirrorPolicy {
 	if r.MirrorPolicy == nil {
 		return nil
@@ -195,6 +261,104 @@ func UpgradeHTTPS() *envoy_api_v2_route.Route_Redirect {
 	}
 }
 
+// RouteRedirect returns a route Action that redirects the request
+// as described by the supplied *dag.Redirect.
+func RouteRedirect(r *dag.Redirect) *envoy_api_v2_route.Route_Redirect {
+	ra := &envoy_api_v2_route.RedirectAction{
+		HostRedirect: r.Hostname,
+		PortRedirect: r.PortNumber,
+		ResponseCode: redirectResponseCode(r.StatusCode),
+	}
+
+	if r.Scheme != "" {
+		ra.SchemeRewriteSpecifier = &envoy_api_v2_route.RedirectAction_SchemeRedirect{
+			SchemeRedirect: r.Scheme,
+		}
+	}
+
+	switch {
+	case r.PathRewrite != "":
+		ra.PathRewriteSpecifier = &envoy_api_v2_route.RedirectAction_PathRedirect{
+			PathRedirect: r.PathRewrite,
+		}
+	case r.PrefixRewrite != "":
+		ra.PathRewriteSpecifier = &envoy_api_v2_route.RedirectAction_PrefixRewrite{
+			PrefixRewrite: r.PrefixRewrite,
+		}
+	}
+
+	return &envoy_api_v2_route.Route_Redirect{
+		Redirect: ra,
+	}
+}
+
+// RouteDirectResponse returns a route Action that responds to the
+// request with the supplied *dag.DirectResponse.
+func RouteDirectResponse(r *dag.DirectResponse) *envoy_api_v2_route.Route_DirectResponse {
+	dr := &envoy_api_v2_route.DirectResponseAction{
+		Status: r.StatusCode,
+	}
+
+	if r.Body != "" {
+		dr.Body = &envoy_api_v2_core.DataSource{
+			Specifier: &envoy_api_v2_core.DataSource_InlineString{
+				InlineString: r.Body,
+			},
+		}
+	}
+
+	return &envoy_api_v2_route.Route_DirectResponse{
+		DirectResponse: dr,
+	}
+}
+
+// redirectResponseCode maps an HTTP status code to the corresponding
+// Envoy redirect response code, defaulting to 301.
+func redirectResponseCode(code int) envoy_api_v2_route.RedirectAction_RedirectResponseCode {
+	switch code {
+	case http.StatusFound:
+		return envoy_api_v2_route.RedirectAction_FOUND
+	case http.StatusTemporaryRedirect:
+		return envoy_api_v2_route.RedirectAction_TEMPORARY_REDIRECT
+	case http.StatusPermanentRedirect:
+		return envoy_api_v2_route.RedirectAction_PERMANENT_REDIRECT
+	default:
+		return envoy_api_v2_route.RedirectAction_MOVED_PERMANENTLY
+	}
+}
+
+// CORSPolicy returns the Envoy CorsPolicy for the supplied *dag.CORSPolicy,
+// or nil if no policy is supplied.
+func CORSPolicy(cp *dag.CORSPolicy) *envoy_api_v2_route.CorsPolicy {
+	if cp == nil {
+		return nil
+	}
+
+	ecp := &envoy_api_v2_route.CorsPolicy{
+		AllowCredentials: protobuf.Bool(cp.AllowCredentials),
+		AllowHeaders:     strings.Join(cp.AllowHeaders, ","),
+		AllowMethods:     strings.Join(cp.AllowMethods, ","),
+		ExposeHeaders:    strings.Join(cp.ExposeHeaders, ","),
+	}
+
+	// The Access-Control-Max-Age header is expressed in whole seconds.
+	if cp.MaxAge > 0 {
+		ecp.MaxAge = fmt.Sprintf("%.0f", cp.MaxAge.Seconds())
+	}
+
+	for _, origin := range cp.AllowOrigin {
+		ecp.AllowOriginStringMatch = append(ecp.AllowOriginStringMatch, &matcher.StringMatcher{
+			// Envoy always treats an allowed origin of "*"
+			// as matching any origin, even with an exact matcher.
+			MatchPattern: &matcher.StringMatcher_Exact{
+				Exact: origin,
+			},
+		})
+	}
+
+	return ecp
+}
+
 // HeaderValueList creates a list of Envoy HeaderValueOptions from the provided map.
 func HeaderValueList(hvm map[string]string, app bool) (hvs []*envoy_api_v2_core.HeaderValueOption) {
 	for key, value := range hvm {
@@ -341,6 +505,12 @@ func headerMatcher(headers []dag.HeaderCondition) []*envoy_api_v2_route.HeaderMa
 			header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_ExactMatch{ExactMatch: h.Value}
 		case "contains":
 			header.HeaderMatchSpecifier = containsMatch(h.Value)
+		case "prefix":
+			header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_PrefixMatch{PrefixMatch: h.Value}
+		case "suffix":
+			header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_SuffixMatch{SuffixMatch: h.Value}
+		case "regex":
+			header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_SafeRegexMatch{SafeRegexMatch: SafeRegexMatch(h.Value)}
 		case "present":
 			header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true}
 		}
@@ -349,6 +519,43 @@ func headerMatcher(headers []dag.HeaderCondition) []*envoy_api_v2_route.HeaderMa
 	return envoyHeaders
 }
 
+func queryParamMatcher(queryParams []dag.QueryParamCondition) []*envoy_api_v2_route.QueryParameterMatcher {
+	var envoyQueryParams []*envoy_api_v2_route.QueryParameterMatcher
+
+	for _, q := range queryParams {
+		queryParam := &envoy_api_v2_route.QueryParameterMatcher{
+			Name: q.Name,
+		}
+
+		switch q.MatchType {
+		case "exact":
+			queryParam.QueryParameterMatchSpecifier = stringMatch(&matcher.StringMatcher{
+				MatchPattern: &matcher.StringMatcher_Exact{Exact: q.Value},
+			})
+		case "prefix":
+			queryParam.QueryParameterMatchSpecifier = stringMatch(&matcher.StringMatcher{
+				MatchPattern: &matcher.StringMatcher_Prefix{Prefix: q.Value},
+			})
+		case "regex":
+			queryParam.QueryParameterMatchSpecifier = stringMatch(&matcher.StringMatcher{
+				MatchPattern: &matcher.StringMatcher_SafeRegex{SafeRegex: SafeRegexMatch(q.Value)},
+			})
+		case "present":
+			queryParam.QueryParameterMatchSpecifier = &envoy_api_v2_route.QueryParameterMatcher_PresentMatch{PresentMatch: true}
+		}
+		envoyQueryParams = append(envoyQueryParams, queryParam)
+	}
+	return envoyQueryParams
+}
+
+// stringMatch returns a QueryParameterMatchSpecifier which will match
+// the query parameter value using the supplied StringMatcher.
+func stringMatch(sm *matcher.StringMatcher) *envoy_api_v2_route.QueryParameterMatcher_StringMatch {
+	return &envoy_api_v2_route.QueryParameterMatcher_StringMatch{
+		StringMatch: sm,
+	}
+}
+
 // containsMatch returns a HeaderMatchSpecifier which will match the
 // supplied substring
 func containsMatch(s string) *envoy_api_v2_route.HeaderMatcher_SafeRegexMatch {
@@ -20,6 +20,7 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/projectcontour/contour/internal/assert"
 	"github.com/projectcontour/contour/internal/dag"
@@ -59,6 +60,14 @@ func TestRouteRoute(t *testing.T) {
 		},
 		LoadBalancerPolicy: "Cookie",
 	}
+	c3 := &dag.Cluster{
+		Upstream: &dag.Service{
+			Name:        s1.Name,
+			Namespace:   s1.Namespace,
+			ServicePort: &s1.Spec.Ports[0],
+		},
+		LoadBalancerPolicy: "RequestHash",
+	}
 
 	tests := map[string]struct {
 		route *dag.Route
@@ -417,6 +426,64 @@ func TestRouteRoute(t *testing.T) {
 				},
 			},
 		},
+		"single service w/ request hash policies": {
+			route: &dag.Route{
+				Clusters: []*dag.Cluster{c3},
+				RequestHashPolicies: []dag.RequestHashPolicy{{
+					Terminal: true,
+					HeaderHashOptions: &dag.HeaderHashOptions{
+						HeaderName: "X-Tenant-Id",
+					},
+				}, {
+					CookieHashOptions: &dag.CookieHashOptions{
+						CookieName: "session",
+						TTL:        time.Hour,
+						Path:       "/",
+					},
+				}, {
+					QueryParameterHashOptions: &dag.QueryParameterHashOptions{
+						ParameterName: "tenant",
+					},
+				}, {
+					HashSourceIP: true,
+				}},
+			},
+			want: &envoy_api_v2_route.Route_Route{
+				Route: &envoy_api_v2_route.RouteAction{
+					ClusterSpecifier: &envoy_api_v2_route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/1a2ffc1fef",
+					},
+					HashPolicy: []*envoy_api_v2_route.RouteAction_HashPolicy{{
+						Terminal: true,
+						PolicySpecifier: &envoy_api_v2_route.RouteAction_HashPolicy_Header_{
+							Header: &envoy_api_v2_route.RouteAction_HashPolicy_Header{
+								HeaderName: "X-Tenant-Id",
+							},
+						},
+					}, {
+						PolicySpecifier: &envoy_api_v2_route.RouteAction_HashPolicy_Cookie_{
+							Cookie: &envoy_api_v2_route.RouteAction_HashPolicy_Cookie{
+								Name: "session",
+								Ttl:  protobuf.Duration(time.Hour),
+								Path: "/",
+							},
+						},
+					}, {
+						PolicySpecifier: &envoy_api_v2_route.RouteAction_HashPolicy_QueryParameter_{
+							QueryParameter: &envoy_api_v2_route.RouteAction_HashPolicy_QueryParameter{
+								Name: "tenant",
+							},
+						},
+					}, {
+						PolicySpecifier: &envoy_api_v2_route.RouteAction_HashPolicy_ConnectionProperties_{
+							ConnectionProperties: &envoy_api_v2_route.RouteAction_HashPolicy_ConnectionProperties{
+								SourceIp: true,
+							},
+						},
+					}},
+				},
+			},
+		},
 		"host header rewrite": {
 			route: &dag.Route{
 				RequestHeadersPolicy: &dag.HeadersPolicy{
@@ -662,6 +729,14 @@ func TestVirtualHost(t *testing.T) {
 				Domains: []string{"www.example.com", "www.example.com:*"},
 			},
 		},
+		"wildcard hostname": {
+			hostname: "*.example.com",
+			port:     9999,
+			want: &envoy_api_v2_route.VirtualHost{
+				Name:    "*.example.com",
+				Domains: []string{"*.example.com", "*.example.com:*"},
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -684,6 +759,168 @@ func TestUpgradeHTTPS(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestRouteRedirect(t *testing.T) {
+	tests := map[string]struct {
+		redirect *dag.Redirect
+		want     *envoy_api_v2_route.RedirectAction
+	}{
+		"host and status code": {
+			redirect: &dag.Redirect{
+				Hostname:   "new.example.com",
+				StatusCode: 302,
+			},
+			want: &envoy_api_v2_route.RedirectAction{
+				HostRedirect: "new.example.com",
+				ResponseCode: envoy_api_v2_route.RedirectAction_FOUND,
+			},
+		},
+		"scheme, port and path": {
+			redirect: &dag.Redirect{
+				Scheme:      "https",
+				PortNumber:  8443,
+				StatusCode:  301,
+				PathRewrite: "/landing",
+			},
+			want: &envoy_api_v2_route.RedirectAction{
+				SchemeRewriteSpecifier: &envoy_api_v2_route.RedirectAction_SchemeRedirect{
+					SchemeRedirect: "https",
+				},
+				PortRedirect: 8443,
+				PathRewriteSpecifier: &envoy_api_v2_route.RedirectAction_PathRedirect{
+					PathRedirect: "/landing",
+				},
+				ResponseCode: envoy_api_v2_route.RedirectAction_MOVED_PERMANENTLY,
+			},
+		},
+		"prefix": {
+			redirect: &dag.Redirect{
+				StatusCode:    308,
+				PrefixRewrite: "/v2",
+			},
+			want: &envoy_api_v2_route.RedirectAction{
+				PathRewriteSpecifier: &envoy_api_v2_route.RedirectAction_PrefixRewrite{
+					PrefixRewrite: "/v2",
+				},
+				ResponseCode: envoy_api_v2_route.RedirectAction_PERMANENT_REDIRECT,
+			},
+		},
+		"temporary redirect": {
+			redirect: &dag.Redirect{
+				Hostname:   "new.example.com",
+				StatusCode: 307,
+			},
+			want: &envoy_api_v2_route.RedirectAction{
+				HostRedirect: "new.example.com",
+				ResponseCode: envoy_api_v2_route.RedirectAction_TEMPORARY_REDIRECT,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := RouteRedirect(tc.redirect)
+			want := &envoy_api_v2_route.Route_Redirect{
+				Redirect: tc.want,
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestRouteDirectResponse(t *testing.T) {
+	tests := map[string]struct {
+		direct *dag.DirectResponse
+		want   *envoy_api_v2_route.DirectResponseAction
+	}{
+		"status code only": {
+			direct: &dag.DirectResponse{
+				StatusCode: 410,
+			},
+			want: &envoy_api_v2_route.DirectResponseAction{
+				Status: 410,
+			},
+		},
+		"status code and body": {
+			direct: &dag.DirectResponse{
+				StatusCode: 503,
+				Body:       "down for maintenance",
+			},
+			want: &envoy_api_v2_route.DirectResponseAction{
+				Status: 503,
+				Body: &envoy_api_v2_core.DataSource{
+					Specifier: &envoy_api_v2_core.DataSource_InlineString{
+						InlineString: "down for maintenance",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := RouteDirectResponse(tc.direct)
+			want := &envoy_api_v2_route.Route_DirectResponse{
+				DirectResponse: tc.want,
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestCORSPolicy(t *testing.T) {
+	tests := map[string]struct {
+		cp   *dag.CORSPolicy
+		want *envoy_api_v2_route.CorsPolicy
+	}{
+		"nil policy": {
+			cp:   nil,
+			want: nil,
+		},
+		"all fields": {
+			cp: &dag.CORSPolicy{
+				AllowCredentials: true,
+				AllowOrigin:      []string{"*", "https://www.example.com"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"authorization", "cache-control"},
+				ExposeHeaders:    []string{"Content-Length"},
+				MaxAge:           10 * time.Minute,
+			},
+			want: &envoy_api_v2_route.CorsPolicy{
+				AllowOriginStringMatch: []*matcher.StringMatcher{{
+					MatchPattern: &matcher.StringMatcher_Exact{Exact: "*"},
+				}, {
+					MatchPattern: &matcher.StringMatcher_Exact{Exact: "https://www.example.com"},
+				}},
+				AllowCredentials: protobuf.Bool(true),
+				AllowMethods:     "GET,POST",
+				AllowHeaders:     "authorization,cache-control",
+				ExposeHeaders:    "Content-Length",
+				MaxAge:           "600",
+			},
+		},
+		"no max age": {
+			cp: &dag.CORSPolicy{
+				AllowOrigin:  []string{"*"},
+				AllowMethods: []string{"GET"},
+			},
+			want: &envoy_api_v2_route.CorsPolicy{
+				AllowOriginStringMatch: []*matcher.StringMatcher{{
+					MatchPattern: &matcher.StringMatcher_Exact{Exact: "*"},
+				}},
+				AllowCredentials: protobuf.Bool(false),
+				AllowMethods:     "GET",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := CORSPolicy(tc.cp)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestRouteMatch(t *testing.T) {
 	tests := map[string]struct {
 		route *dag.Route
@@ -746,6 +983,60 @@ func TestRouteMatch(t *testing.T) {
 				}},
 			},
 		},
+		"prefix match": {
+			route: &dag.Route{
+				HeaderConditions: []dag.HeaderCondition{{
+					Name:      "user-agent",
+					Value:     "Mozilla/",
+					MatchType: "prefix",
+				}},
+			},
+			want: &envoy_api_v2_route.RouteMatch{
+				Headers: []*envoy_api_v2_route.HeaderMatcher{{
+					Name: "user-agent",
+					HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PrefixMatch{
+						PrefixMatch: "Mozilla/",
+					},
+				}},
+			},
+		},
+		"inverted suffix match": {
+			route: &dag.Route{
+				HeaderConditions: []dag.HeaderCondition{{
+					Name:      "user-agent",
+					Value:     "bot",
+					MatchType: "suffix",
+					Invert:    true,
+				}},
+			},
+			want: &envoy_api_v2_route.RouteMatch{
+				Headers: []*envoy_api_v2_route.HeaderMatcher{{
+					Name:        "user-agent",
+					InvertMatch: true,
+					HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_SuffixMatch{
+						SuffixMatch: "bot",
+					},
+				}},
+			},
+		},
+		"regex match": {
+			route: &dag.Route{
+				HeaderConditions: []dag.HeaderCondition{{
+					Name:      "cookie",
+					Value:     ".*session=canary.*",
+					MatchType: "regex",
+				}},
+			},
+			want: &envoy_api_v2_route.RouteMatch{
+				Headers: []*envoy_api_v2_route.HeaderMatcher{{
+					Name: "cookie",
+					HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_SafeRegexMatch{
+						// unlike contains, the regex is passed to Envoy unquoted.
+						SafeRegexMatch: SafeRegexMatch(".*session=canary.*"),
+					},
+				}},
+			},
+		},
 		"path prefix": {
 			route: &dag.Route{
 				PathCondition: &dag.PrefixCondition{
@@ -758,6 +1049,73 @@ func TestRouteMatch(t *testing.T) {
 				},
 			},
 		},
+		"query parameters": {
+			route: &dag.Route{
+				PathCondition: &dag.PrefixCondition{
+					Prefix: "/",
+				},
+				QueryParamConditions: []dag.QueryParamCondition{{
+					Name:      "version",
+					Value:     "v1",
+					MatchType: "exact",
+				}, {
+					Name:      "feature",
+					Value:     "beta-",
+					MatchType: "prefix",
+				}, {
+					Name:      "id",
+					Value:     "[0-9]+",
+					MatchType: "regex",
+				}, {
+					Name:      "debug",
+					MatchType: "present",
+				}},
+			},
+			want: &envoy_api_v2_route.RouteMatch{
+				PathSpecifier: &envoy_api_v2_route.RouteMatch_Prefix{
+					Prefix: "/",
+				},
+				QueryParameters: []*envoy_api_v2_route.QueryParameterMatcher{{
+					Name: "version",
+					QueryParameterMatchSpecifier: &envoy_api_v2_route.QueryParameterMatcher_StringMatch{
+						StringMatch: &matcher.StringMatcher{
+							MatchPattern: &matcher.StringMatcher_Exact{Exact: "v1"},
+						},
+					},
+				}, {
+					Name: "feature",
+					QueryParameterMatchSpecifier: &envoy_api_v2_route.QueryParameterMatcher_StringMatch{
+						StringMatch: &matcher.StringMatcher{
+							MatchPattern: &matcher.StringMatcher_Prefix{Prefix: "beta-"},
+						},
+					},
+				}, {
+					Name: "id",
+					QueryParameterMatchSpecifier: &envoy_api_v2_route.QueryParameterMatcher_StringMatch{
+						StringMatch: &matcher.StringMatcher{
+							MatchPattern: &matcher.StringMatcher_SafeRegex{SafeRegex: SafeRegexMatch("[0-9]+")},
+						},
+					},
+				}, {
+					Name: "debug",
+					QueryParameterMatchSpecifier: &envoy_api_v2_route.QueryParameterMatcher_PresentMatch{
+						PresentMatch: true,
+					},
+				}},
+			},
+		},
+		"path exact": {
+			route: &dag.Route{
+				PathCondition: &dag.ExactCondition{
+					Path: "/healthz",
+				},
+			},
+			want: &envoy_api_v2_route.RouteMatch{
+				PathSpecifier: &envoy_api_v2_route.RouteMatch_Path{
+					Path: "/healthz",
+				},
+			},
+		},
 		"path regex": {
 			route: &dag.Route{
 				PathCondition: &dag.RegexCondition{
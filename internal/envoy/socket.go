@@ -14,8 +14,13 @@
 package envoy
 
 import (
+	"strings"
+
+	udpa_type_v1 "github.com/cncf/udpa/go/udpa/type/v1"
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/golang/protobuf/jsonpb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
 )
 
 // UpstreamTLSTransportSocket returns a custom transport socket using the UpstreamTlsContext provided.
@@ -37,3 +42,62 @@ func DownstreamTLSTransportSocket(tls *envoy_api_v2_auth.DownstreamTlsContext) *
 		},
 	}
 }
+
+// upstreamProxyProtocolTypeURL is the type of Envoy's upstream PROXY
+// protocol transport socket. go-control-plane v0.9.2 predates it,
+// so its configuration is passed to Envoy as a TypedStruct.
+const upstreamProxyProtocolTypeURL = "type.googleapis.com/envoy.extensions.transport_sockets.proxy_protocol.v3.ProxyProtocolUpstreamTransport"
+
+// UpstreamProxyProtocolTransportSocket returns a transport socket which
+// sends a PROXY protocol header of the supplied version, v1 or v2, before
+// handing the connection to the inner transport socket. If inner is nil
+// the connection is plaintext.
+func UpstreamProxyProtocolTransportSocket(version string, inner *envoy_api_v2_core.TransportSocket) *envoy_api_v2_core.TransportSocket {
+	if inner == nil {
+		inner = &envoy_api_v2_core.TransportSocket{
+			Name: "envoy.transport_sockets.raw_buffer",
+		}
+	}
+
+	return &envoy_api_v2_core.TransportSocket{
+		Name: "envoy.transport_sockets.upstream_proxy_protocol",
+		ConfigType: &envoy_api_v2_core.TransportSocket_TypedConfig{
+			TypedConfig: toAny(&udpa_type_v1.TypedStruct{
+				TypeUrl: upstreamProxyProtocolTypeURL,
+				Value: &_struct.Struct{
+					Fields: map[string]*_struct.Value{
+						"config": {
+							Kind: &_struct.Value_StructValue{
+								StructValue: &_struct.Struct{
+									Fields: map[string]*_struct.Value{
+										"version": sv(strings.ToUpper(version)),
+									},
+								},
+							},
+						},
+						"transport_socket": {
+							Kind: &_struct.Value_StructValue{
+								StructValue: toStruct(inner),
+							},
+						},
+					},
+				},
+			}),
+		},
+	}
+}
+
+// toStruct converts the transport socket to its JSON representation
+// so it can be nested inside a TypedStruct.
+func toStruct(ts *envoy_api_v2_core.TransportSocket) *_struct.Struct {
+	m := jsonpb.Marshaler{OrigName: true}
+	js, err := m.MarshalToString(ts)
+	if err != nil {
+		panic(err.Error())
+	}
+	var s _struct.Struct
+	if err := jsonpb.UnmarshalString(js, &s); err != nil {
+		panic(err.Error())
+	}
+	return &s
+}
@@ -16,8 +16,11 @@ package envoy
 import (
 	"testing"
 
+	udpa_type_v1 "github.com/cncf/udpa/go/udpa/type/v1"
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/golang/protobuf/jsonpb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
 	"github.com/projectcontour/contour/internal/assert"
 )
 
@@ -27,11 +30,11 @@ func TestUpstreamTLSTransportSocket(t *testing.T) {
 		want *envoy_api_v2_core.TransportSocket
 	}{
 		"h2": {
-			ctxt: UpstreamTLSContext(nil, "", "h2"),
+			ctxt: UpstreamTLSContext(nil, "", "h2", nil),
 			want: &envoy_api_v2_core.TransportSocket{
 				Name: "envoy.transport_sockets.tls",
 				ConfigType: &envoy_api_v2_core.TransportSocket_TypedConfig{
-					TypedConfig: toAny(UpstreamTLSContext(nil, "", "h2")),
+					TypedConfig: toAny(UpstreamTLSContext(nil, "", "h2", nil)),
 				},
 			},
 		},
@@ -51,11 +54,11 @@ func TestDownstreamTLSTransportSocket(t *testing.T) {
 		want *envoy_api_v2_core.TransportSocket
 	}{
 		"default/tls": {
-			ctxt: DownstreamTLSContext("default/tls", envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+			ctxt: DownstreamTLSContext("default/tls", envoy_api_v2_auth.TlsParameters_TLSv1_1, envoy_api_v2_auth.TlsParameters_TLSv1_3, nil, nil, "h2", "http/1.1"),
 			want: &envoy_api_v2_core.TransportSocket{
 				Name: "envoy.transport_sockets.tls",
 				ConfigType: &envoy_api_v2_core.TransportSocket_TypedConfig{
-					TypedConfig: toAny(DownstreamTLSContext("default/tls", envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1")),
+					TypedConfig: toAny(DownstreamTLSContext("default/tls", envoy_api_v2_auth.TlsParameters_TLSv1_1, envoy_api_v2_auth.TlsParameters_TLSv1_3, nil, nil, "h2", "http/1.1")),
 				},
 			},
 		},
@@ -68,3 +71,54 @@ func TestDownstreamTLSTransportSocket(t *testing.T) {
 		})
 	}
 }
+
+func TestUpstreamProxyProtocolTransportSocket(t *testing.T) {
+	tests := map[string]struct {
+		version string
+		inner   *envoy_api_v2_core.TransportSocket
+		want    string
+	}{
+		"v1 plaintext": {
+			version: "v1",
+			want: `{
+				"config": {"version": "V1"},
+				"transport_socket": {"name": "envoy.transport_sockets.raw_buffer"}
+			}`,
+		},
+		"v2 tls": {
+			version: "v2",
+			inner:   UpstreamTLSTransportSocket(UpstreamTLSContext(nil, "", "", nil)),
+			want: `{
+				"config": {"version": "V2"},
+				"transport_socket": {
+					"name": "envoy.transport_sockets.tls",
+					"typed_config": {
+						"@type": "type.googleapis.com/envoy.api.v2.auth.UpstreamTlsContext",
+						"common_tls_context": {}
+					}
+				}
+			}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := UpstreamProxyProtocolTransportSocket(tc.version, tc.inner)
+
+			var value _struct.Struct
+			if err := jsonpb.UnmarshalString(tc.want, &value); err != nil {
+				t.Fatal(err)
+			}
+			want := &envoy_api_v2_core.TransportSocket{
+				Name: "envoy.transport_sockets.upstream_proxy_protocol",
+				ConfigType: &envoy_api_v2_core.TransportSocket_TypedConfig{
+					TypedConfig: toAny(&udpa_type_v1.TypedStruct{
+						TypeUrl: "type.googleapis.com/envoy.extensions.transport_sockets.proxy_protocol.v3.ProxyProtocolUpstreamTransport",
+						Value:   &value,
+					}),
+				},
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
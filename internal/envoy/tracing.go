@@ -0,0 +1,138 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	trace "github.com/envoyproxy/go-control-plane/envoy/config/trace/v2"
+	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
+	tracing "github.com/envoyproxy/go-control-plane/envoy/type/tracing/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/projectcontour/contour/internal/dag"
+)
+
+// TracingCluster is the name of the static cluster
+// spans are sent to.
+const TracingCluster = "tracing"
+
+// TracingConfig holds the tracing parameters of the
+// HTTP connection managers.
+type TracingConfig struct {
+	// ClientSampling is the percentage of requests carrying
+	// an x-client-trace-id header which are traced.
+	ClientSampling float64
+
+	// RandomSampling is the percentage of requests which
+	// are randomly selected for tracing.
+	RandomSampling float64
+
+	// OverallSampling is the percentage of requests which are
+	// traced after all other sampling checks have been applied.
+	OverallSampling float64
+
+	// Verbose adds span annotations for stream events.
+	Verbose bool
+
+	// CustomTags are added to every span.
+	CustomTags []*TracingCustomTag
+}
+
+// TracingCustomTag is a span tag whose value is either a literal
+// or taken from a request header.
+type TracingCustomTag struct {
+	Name          string
+	Literal       string
+	RequestHeader string
+}
+
+// httpConnectionManagerTracing returns the tracing configuration
+// of an HTTP connection manager, or nil if tracing is disabled.
+func httpConnectionManagerTracing(tc *TracingConfig) *http.HttpConnectionManager_Tracing {
+	if tc == nil {
+		return nil
+	}
+
+	t := &http.HttpConnectionManager_Tracing{
+		ClientSampling:  &envoy_type.Percent{Value: tc.ClientSampling},
+		RandomSampling:  &envoy_type.Percent{Value: tc.RandomSampling},
+		OverallSampling: &envoy_type.Percent{Value: tc.OverallSampling},
+		Verbose:         tc.Verbose,
+	}
+	for _, ct := range tc.CustomTags {
+		tag := &tracing.CustomTag{
+			Tag: ct.Name,
+		}
+		switch {
+		case ct.RequestHeader != "":
+			tag.Type = &tracing.CustomTag_RequestHeader{
+				RequestHeader: &tracing.CustomTag_Header{
+					Name: ct.RequestHeader,
+				},
+			}
+		default:
+			tag.Type = &tracing.CustomTag_Literal_{
+				Literal: &tracing.CustomTag_Literal{
+					Value: ct.Literal,
+				},
+			}
+		}
+		t.CustomTags = append(t.CustomTags, tag)
+	}
+	return t
+}
+
+// RouteTracing returns the per route tracing overrides for
+// the supplied *dag.TracingPolicy, or nil if no policy is supplied.
+func RouteTracing(tp *dag.TracingPolicy) *envoy_api_v2_route.Tracing {
+	switch {
+	case tp == nil:
+		return nil
+	case tp.Disabled:
+		// Overall sampling is applied after every other
+		// check, so sampling nothing disables tracing.
+		return &envoy_api_v2_route.Tracing{
+			OverallSampling: &envoy_type.FractionalPercent{
+				Numerator:   0,
+				Denominator: envoy_type.FractionalPercent_HUNDRED,
+			},
+		}
+	case tp.RandomSampling > 0:
+		return &envoy_api_v2_route.Tracing{
+			RandomSampling: &envoy_type.FractionalPercent{
+				Numerator:   tp.RandomSampling,
+				Denominator: envoy_type.FractionalPercent_HUNDRED,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// ZipkinTracer returns a tracer which sends spans in the Zipkin v2
+// JSON format to the supplied endpoint of the TracingCluster.
+func ZipkinTracer(endpoint string) *trace.Tracing {
+	return &trace.Tracing{
+		Http: &trace.Tracing_Http{
+			Name: wellknown.Zipkin,
+			ConfigType: &trace.Tracing_Http_TypedConfig{
+				TypedConfig: toAny(&trace.ZipkinConfig{
+					CollectorCluster:         TracingCluster,
+					CollectorEndpoint:        endpoint,
+					CollectorEndpointVersion: trace.ZipkinConfig_HTTP_JSON,
+				}),
+			},
+		},
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright © 2020 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+
+	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
+	tracing "github.com/envoyproxy/go-control-plane/envoy/type/tracing/v2"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
+)
+
+func TestHTTPConnectionManagerTracing(t *testing.T) {
+	tests := map[string]struct {
+		tc   *TracingConfig
+		want *http.HttpConnectionManager_Tracing
+	}{
+		"nil config": {
+			tc:   nil,
+			want: nil,
+		},
+		"sampling": {
+			tc: &TracingConfig{
+				ClientSampling:  100,
+				RandomSampling:  12.5,
+				OverallSampling: 50,
+				Verbose:         true,
+			},
+			want: &http.HttpConnectionManager_Tracing{
+				ClientSampling:  &envoy_type.Percent{Value: 100},
+				RandomSampling:  &envoy_type.Percent{Value: 12.5},
+				OverallSampling: &envoy_type.Percent{Value: 50},
+				Verbose:         true,
+			},
+		},
+		"custom tags": {
+			tc: &TracingConfig{
+				ClientSampling:  100,
+				RandomSampling:  100,
+				OverallSampling: 100,
+				CustomTags: []*TracingCustomTag{{
+					Name:    "cluster",
+					Literal: "production",
+				}, {
+					Name:          "user-agent",
+					RequestHeader: "User-Agent",
+				}},
+			},
+			want: &http.HttpConnectionManager_Tracing{
+				ClientSampling:  &envoy_type.Percent{Value: 100},
+				RandomSampling:  &envoy_type.Percent{Value: 100},
+				OverallSampling: &envoy_type.Percent{Value: 100},
+				CustomTags: []*tracing.CustomTag{{
+					Tag: "cluster",
+					Type: &tracing.CustomTag_Literal_{
+						Literal: &tracing.CustomTag_Literal{
+							Value: "production",
+						},
+					},
+				}, {
+					Tag: "user-agent",
+					Type: &tracing.CustomTag_RequestHeader{
+						RequestHeader: &tracing.CustomTag_Header{
+							Name: "User-Agent",
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := httpConnectionManagerTracing(tc.tc)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRouteTracing(t *testing.T) {
+	tests := map[string]struct {
+		tp   *dag.TracingPolicy
+		want *envoy_api_v2_route.Tracing
+	}{
+		"nil policy": {
+			tp:   nil,
+			want: nil,
+		},
+		"disabled": {
+			tp: &dag.TracingPolicy{
+				Disabled: true,
+			},
+			want: &envoy_api_v2_route.Tracing{
+				OverallSampling: &envoy_type.FractionalPercent{
+					Numerator:   0,
+					Denominator: envoy_type.FractionalPercent_HUNDRED,
+				},
+			},
+		},
+		"random sampling": {
+			tp: &dag.TracingPolicy{
+				RandomSampling: 25,
+			},
+			want: &envoy_api_v2_route.Tracing{
+				RandomSampling: &envoy_type.FractionalPercent{
+					Numerator:   25,
+					Denominator: envoy_type.FractionalPercent_HUNDRED,
+				},
+			},
+		},
+		"empty policy": {
+			tp:   &dag.TracingPolicy{},
+			want: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := RouteTracing(tc.tp)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -109,7 +109,7 @@ func cluster(name, servicename, statName string) *v2.Cluster {
 
 func tlsCluster(c *v2.Cluster, ca []byte, subjectName string, sni string, alpnProtocols ...string) *v2.Cluster {
 	c.TransportSocket = envoy.UpstreamTLSTransportSocket(
-		envoy.UpstreamTLSContext(ca, subjectName, sni, alpnProtocols...),
+		envoy.UpstreamTLSContext(ca, subjectName, sni, nil, alpnProtocols...),
 	)
 	return c
 }
@@ -209,10 +209,15 @@ func filterchaintls(domain string, secret *v1.Secret, filter *envoy_api_v2_liste
 	return []*envoy_api_v2_listener.FilterChain{
 		envoy.FilterChainTLS(
 			domain,
-			&dag.Secret{Object: secret},
+			envoy.DownstreamTLSContext(
+				envoy.Secretname(&dag.Secret{Object: secret}),
+				envoy_api_v2_auth.TlsParameters_TLSv1_1,
+				envoy_api_v2_auth.TlsParameters_TLSv1_3,
+				nil,
+				nil,
+				alpn...,
+			),
 			envoy.Filters(filter),
-			envoy_api_v2_auth.TlsParameters_TLSv1_1,
-			alpn...,
 		),
 	}
 }
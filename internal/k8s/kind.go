@@ -18,6 +18,7 @@ import (
 	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
 )
 
 // KindOf returns the kind string for the given Kubernetes object.
@@ -41,6 +42,14 @@ func KindOf(obj interface{}) string {
 		return "TLSCertificateDelegation"
 	case *projectcontour.TLSCertificateDelegation:
 		return "TLSCertificateDelegation"
+	case *serviceapis.GatewayClass:
+		return "GatewayClass"
+	case *serviceapis.Gateway:
+		return "Gateway"
+	case *serviceapis.HTTPRoute:
+		return "HTTPRoute"
+	case *serviceapis.TcpRoute:
+		return "TcpRoute"
 	default:
 		return ""
 	}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	jsonpatch "github.com/evanphx/json-patch"
@@ -27,6 +28,7 @@ import (
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
 )
 
 const (
@@ -39,6 +41,9 @@ const (
 type StatusClient interface {
 	SetStatus(status string, desc string, obj interface{}) error
 	GetStatus(obj interface{}) (*projcontour.Status, error)
+
+	// SetHTTPRouteStatus records the Gateways which admitted the HTTPRoute.
+	SetHTTPRouteStatus(status string, desc string, gateways []types.NamespacedName, route *serviceapis.HTTPRoute) error
 }
 
 // StatusCacher keeps a cache of the latest status updates for Kubernetes objects.
@@ -58,6 +63,16 @@ func objectKey(obj interface{}) string {
 			KindOf(obj),
 			obj.GetObjectMeta().GetNamespace(),
 			obj.GetObjectMeta().GetName())
+	case *serviceapis.Gateway:
+		return fmt.Sprintf("%s/%s/%s",
+			KindOf(obj),
+			obj.GetObjectMeta().GetNamespace(),
+			obj.GetObjectMeta().GetName())
+	case *serviceapis.HTTPRoute:
+		return fmt.Sprintf("%s/%s/%s",
+			KindOf(obj),
+			obj.GetObjectMeta().GetNamespace(),
+			obj.GetObjectMeta().GetName())
 	default:
 		panic(fmt.Sprintf("status caching not supported for object type %T", obj))
 	}
@@ -67,7 +82,7 @@ func objectKey(obj interface{}) string {
 // the status cache.
 func (c *StatusCacher) IsCacheable(obj interface{}) bool {
 	switch obj.(type) {
-	case *ingressroutev1.IngressRoute, *projcontour.HTTPProxy:
+	case *ingressroutev1.IngressRoute, *projcontour.HTTPProxy, *serviceapis.Gateway, *serviceapis.HTTPRoute:
 		return true
 	default:
 		return false
@@ -109,6 +124,12 @@ func (c *StatusCacher) SetStatus(status, desc string, obj interface{}) error {
 	return nil
 }
 
+// SetHTTPRouteStatus sets the HTTPRoute status field to an Valid or Invalid status.
+// The Gateways which admitted the route are not cached.
+func (c *StatusCacher) SetHTTPRouteStatus(status, desc string, gateways []types.NamespacedName, route *serviceapis.HTTPRoute) error {
+	return c.SetStatus(status, desc, route)
+}
+
 // StatusWriter updates the object's Status field.
 type StatusWriter struct {
 	Client dynamic.Interface
@@ -142,10 +163,87 @@ func (irs *StatusWriter) SetStatus(status, desc string, existing interface{}) er
 			}
 			return irs.setHTTPProxyStatus(exist, updated)
 		}
+	case *serviceapis.Gateway:
+		// Gateway status is expressed as conditions, so compare
+		// the desired conditions with the existing ones.
+		conditions := gatewayConditions(status, desc)
+		if irs.gatewayUpdateNeeded(conditions, exist.Status.Conditions) {
+			updated := exist.DeepCopy()
+			updated.Status.Conditions = conditions
+			return irs.setGatewayStatus(exist, updated)
+		}
 	}
 	return nil
 }
 
+// SetHTTPRouteStatus sets the HTTPRoute status to the list of Gateways which
+// admitted it. HTTPRouteStatus has no field for the description, so routes
+// rejected by every Gateway have an empty list of Gateways.
+func (irs *StatusWriter) SetHTTPRouteStatus(status, desc string, gateways []types.NamespacedName, existing *serviceapis.HTTPRoute) error {
+	var refs []v1.ObjectReference
+	for _, gw := range gateways {
+		refs = append(refs, v1.ObjectReference{
+			APIVersion: serviceapis.GroupVersion.String(),
+			Kind:       "Gateway",
+			Namespace:  gw.Namespace,
+			Name:       gw.Name,
+		})
+	}
+	if irs.httpRouteUpdateNeeded(refs, existing.Status.Gateways) {
+		updated := existing.DeepCopy()
+		updated.Status.Gateways = refs
+		return irs.setHTTPRouteStatus(existing, updated)
+	}
+	return nil
+}
+
+func (irs *StatusWriter) httpRouteUpdateNeeded(desired, existing []v1.ObjectReference) bool {
+	if len(desired) != len(existing) {
+		return true
+	}
+	for i := range desired {
+		if desired[i].Kind != existing[i].Kind ||
+			desired[i].Namespace != existing[i].Namespace ||
+			desired[i].Name != existing[i].Name {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayConditions returns the Gateway conditions that represent the status
+// and description. A Gateway that could not be fully programmed is reported
+// as having listeners that are not ready.
+func gatewayConditions(status, desc string) []serviceapis.GatewayCondition {
+	condition := serviceapis.GatewayCondition{
+		Type:               serviceapis.ConditionListenersNotReady,
+		Status:             v1.ConditionFalse,
+		Reason:             "Valid",
+		Message:            desc,
+		LastTransitionTime: metav1.Now(),
+	}
+	if status != StatusValid {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = "Invalid"
+	}
+	return []serviceapis.GatewayCondition{condition}
+}
+
+func (irs *StatusWriter) gatewayUpdateNeeded(desired, existing []serviceapis.GatewayCondition) bool {
+	if len(desired) != len(existing) {
+		return true
+	}
+	for i := range desired {
+		if desired[i].Type != existing[i].Type ||
+			desired[i].Status != existing[i].Status ||
+			desired[i].Reason != existing[i].Reason ||
+			desired[i].Message != existing[i].Message {
+			return true
+		}
+	}
+	return false
+}
+
 func (irs *StatusWriter) updateNeeded(status, desc string, existing projcontour.Status) bool {
 	if existing.CurrentStatus != status || existing.Description != desc {
 		return true
@@ -196,3 +294,47 @@ func (irs *StatusWriter) setHTTPProxyStatus(existing, updated *projcontour.HTTPP
 	_, err = irs.Client.Resource(projcontour.HTTPProxyGVR).Namespace(existing.GetNamespace()).Patch(existing.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{})
 	return err
 }
+
+func (irs *StatusWriter) setGatewayStatus(existing, updated *serviceapis.Gateway) error {
+	existingBytes, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	// Need to set the resource version of the updated endpoints to the resource
+	// version of the current service. Otherwise, the resulting patch does not
+	// have a resource version, and the server complains.
+	updated.ResourceVersion = existing.ResourceVersion
+	updatedBytes, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	patchBytes, err := jsonpatch.CreateMergePatch(existingBytes, updatedBytes)
+	if err != nil {
+		return err
+	}
+
+	_, err = irs.Client.Resource(serviceapis.GroupVersion.WithResource("gateways")).Namespace(existing.GetNamespace()).Patch(existing.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+func (irs *StatusWriter) setHTTPRouteStatus(existing, updated *serviceapis.HTTPRoute) error {
+	existingBytes, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	// Need to set the resource version of the updated endpoints to the resource
+	// version of the current service. Otherwise, the resulting patch does not
+	// have a resource version, and the server complains.
+	updated.ResourceVersion = existing.ResourceVersion
+	updatedBytes, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	patchBytes, err := jsonpatch.CreateMergePatch(existingBytes, updatedBytes)
+	if err != nil {
+		return err
+	}
+
+	_, err = irs.Client.Resource(serviceapis.GroupVersion.WithResource("httproutes")).Namespace(existing.GetNamespace()).Patch(existing.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
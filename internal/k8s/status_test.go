@@ -16,6 +16,7 @@ package k8s
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/projectcontour/contour/internal/assert"
@@ -26,9 +27,12 @@ import (
 	ingressroutev1beta1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	k8stesting "k8s.io/client-go/testing"
+	serviceapis "sigs.k8s.io/service-apis/api/v1alpha1"
 )
 
 func TestSetIngressRouteStatus(t *testing.T) {
@@ -227,6 +231,167 @@ func TestSetHTTPProxyStatus(t *testing.T) {
 	})
 }
 
+func TestSetGatewayStatus(t *testing.T) {
+	type testcase struct {
+		msg           string
+		desc          string
+		existing      *serviceapis.Gateway
+		expectedPatch string
+		expectedVerbs []string
+	}
+
+	run := func(t *testing.T, name string, tc testcase) {
+		t.Helper()
+
+		t.Run(name, func(t *testing.T) {
+			t.Helper()
+			var gotPatchBytes []byte
+			s := runtime.NewScheme()
+			if err := serviceapis.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			client := fake.NewSimpleDynamicClient(s, tc.existing)
+
+			client.PrependReactor("patch", "gateways", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				switch patchAction := action.(type) {
+				default:
+					return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+				case k8stesting.PatchActionImpl:
+					gotPatchBytes = patchAction.GetPatch()
+					return true, tc.existing, nil
+				}
+			})
+
+			gws := StatusWriter{
+				Client: client,
+			}
+			if err := gws.SetStatus(tc.msg, tc.desc, tc.existing); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(client.Actions()) != len(tc.expectedVerbs) {
+				t.Fatalf("Expected verbs mismatch: want: %d, got: %d", len(tc.expectedVerbs), len(client.Actions()))
+			}
+
+			if tc.expectedPatch != "" && !strings.Contains(string(gotPatchBytes), tc.expectedPatch) {
+				t.Fatalf("expected patch to contain: %s, got: %s", tc.expectedPatch, string(gotPatchBytes))
+			}
+		})
+	}
+
+	run(t, "simple update", testcase{
+		msg:  "invalid",
+		desc: `HTTPRoute "missing" not found`,
+		existing: &serviceapis.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+		},
+		expectedPatch: `"message":"HTTPRoute \"missing\" not found","reason":"Invalid","status":"True","type":"ListenersNotReady"`,
+		expectedVerbs: []string{"patch"},
+	})
+
+	run(t, "no update", testcase{
+		msg:  "valid",
+		desc: "valid Gateway",
+		existing: &serviceapis.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Status: serviceapis.GatewayStatus{
+				Conditions: []serviceapis.GatewayCondition{{
+					Type:    serviceapis.ConditionListenersNotReady,
+					Status:  v1.ConditionFalse,
+					Reason:  "Valid",
+					Message: "valid Gateway",
+				}},
+			},
+		},
+		expectedVerbs: []string{},
+	})
+}
+
+func TestSetHTTPRouteStatus(t *testing.T) {
+	type testcase struct {
+		gateways      []types.NamespacedName
+		existing      *serviceapis.HTTPRoute
+		expectedPatch string
+		expectedVerbs []string
+	}
+
+	run := func(t *testing.T, name string, tc testcase) {
+		t.Helper()
+
+		t.Run(name, func(t *testing.T) {
+			t.Helper()
+			var gotPatchBytes []byte
+			s := runtime.NewScheme()
+			if err := serviceapis.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			client := fake.NewSimpleDynamicClient(s, tc.existing)
+
+			client.PrependReactor("patch", "httproutes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				switch patchAction := action.(type) {
+				default:
+					return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+				case k8stesting.PatchActionImpl:
+					gotPatchBytes = patchAction.GetPatch()
+					return true, tc.existing, nil
+				}
+			})
+
+			hrs := StatusWriter{
+				Client: client,
+			}
+			if err := hrs.SetHTTPRouteStatus("valid", "valid HTTPRoute", tc.gateways, tc.existing); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(client.Actions()) != len(tc.expectedVerbs) {
+				t.Fatalf("Expected verbs mismatch: want: %d, got: %d", len(tc.expectedVerbs), len(client.Actions()))
+			}
+
+			if tc.expectedPatch != "" && !strings.Contains(string(gotPatchBytes), tc.expectedPatch) {
+				t.Fatalf("expected patch to contain: %s, got: %s", tc.expectedPatch, string(gotPatchBytes))
+			}
+		})
+	}
+
+	run(t, "simple update", testcase{
+		gateways: []types.NamespacedName{{Name: "gateway", Namespace: "default"}},
+		existing: &serviceapis.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+		},
+		expectedPatch: `{"status":{"gateways":[{"apiVersion":"networking.x.k8s.io/v1alpha1","kind":"Gateway","name":"gateway","namespace":"default"}]}}`,
+		expectedVerbs: []string{"patch"},
+	})
+
+	run(t, "no update", testcase{
+		gateways: []types.NamespacedName{{Name: "gateway", Namespace: "default"}},
+		existing: &serviceapis.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Status: serviceapis.HTTPRouteStatus{
+				Gateways: []v1.ObjectReference{{
+					APIVersion: serviceapis.GroupVersion.String(),
+					Kind:       "Gateway",
+					Name:       "gateway",
+					Namespace:  "default",
+				}},
+			},
+		},
+		expectedVerbs: []string{},
+	})
+}
+
 func TestGetStatus(t *testing.T) {
 	type testcase struct {
 		input          interface{}